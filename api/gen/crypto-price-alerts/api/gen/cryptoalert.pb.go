@@ -9,6 +9,7 @@ package gen
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -22,6 +23,127 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Candle lengths; candles are aligned to the interval in UTC
+type CandleInterval int32
+
+const (
+	CandleInterval_CANDLE_INTERVAL_UNSPECIFIED CandleInterval = 0 // Treated as 1m
+	CandleInterval_CANDLE_INTERVAL_1M          CandleInterval = 1
+	CandleInterval_CANDLE_INTERVAL_5M          CandleInterval = 2
+	CandleInterval_CANDLE_INTERVAL_15M         CandleInterval = 3
+	CandleInterval_CANDLE_INTERVAL_1H          CandleInterval = 4
+	CandleInterval_CANDLE_INTERVAL_1D          CandleInterval = 5
+)
+
+// Enum value maps for CandleInterval.
+var (
+	CandleInterval_name = map[int32]string{
+		0: "CANDLE_INTERVAL_UNSPECIFIED",
+		1: "CANDLE_INTERVAL_1M",
+		2: "CANDLE_INTERVAL_5M",
+		3: "CANDLE_INTERVAL_15M",
+		4: "CANDLE_INTERVAL_1H",
+		5: "CANDLE_INTERVAL_1D",
+	}
+	CandleInterval_value = map[string]int32{
+		"CANDLE_INTERVAL_UNSPECIFIED": 0,
+		"CANDLE_INTERVAL_1M":          1,
+		"CANDLE_INTERVAL_5M":          2,
+		"CANDLE_INTERVAL_15M":         3,
+		"CANDLE_INTERVAL_1H":          4,
+		"CANDLE_INTERVAL_1D":          5,
+	}
+)
+
+func (x CandleInterval) Enum() *CandleInterval {
+	p := new(CandleInterval)
+	*p = x
+	return p
+}
+
+func (x CandleInterval) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CandleInterval) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_cryptoalert_proto_enumTypes[0].Descriptor()
+}
+
+func (CandleInterval) Type() protoreflect.EnumType {
+	return &file_api_cryptoalert_proto_enumTypes[0]
+}
+
+func (x CandleInterval) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CandleInterval.Descriptor instead.
+func (CandleInterval) EnumDescriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{0}
+}
+
+// Price history resolutions; bars are aligned to the resolution in UTC
+type HistoryResolution int32
+
+const (
+	HistoryResolution_HISTORY_RESOLUTION_UNSPECIFIED HistoryResolution = 0 // Picked by the server to fit the range
+	HistoryResolution_HISTORY_RESOLUTION_RAW         HistoryResolution = 1 // Every recorded tick
+	HistoryResolution_HISTORY_RESOLUTION_1M          HistoryResolution = 2
+	HistoryResolution_HISTORY_RESOLUTION_5M          HistoryResolution = 3
+	HistoryResolution_HISTORY_RESOLUTION_15M         HistoryResolution = 4
+	HistoryResolution_HISTORY_RESOLUTION_1H          HistoryResolution = 5
+	HistoryResolution_HISTORY_RESOLUTION_1D          HistoryResolution = 6
+)
+
+// Enum value maps for HistoryResolution.
+var (
+	HistoryResolution_name = map[int32]string{
+		0: "HISTORY_RESOLUTION_UNSPECIFIED",
+		1: "HISTORY_RESOLUTION_RAW",
+		2: "HISTORY_RESOLUTION_1M",
+		3: "HISTORY_RESOLUTION_5M",
+		4: "HISTORY_RESOLUTION_15M",
+		5: "HISTORY_RESOLUTION_1H",
+		6: "HISTORY_RESOLUTION_1D",
+	}
+	HistoryResolution_value = map[string]int32{
+		"HISTORY_RESOLUTION_UNSPECIFIED": 0,
+		"HISTORY_RESOLUTION_RAW":         1,
+		"HISTORY_RESOLUTION_1M":          2,
+		"HISTORY_RESOLUTION_5M":          3,
+		"HISTORY_RESOLUTION_15M":         4,
+		"HISTORY_RESOLUTION_1H":          5,
+		"HISTORY_RESOLUTION_1D":          6,
+	}
+)
+
+func (x HistoryResolution) Enum() *HistoryResolution {
+	p := new(HistoryResolution)
+	*p = x
+	return p
+}
+
+func (x HistoryResolution) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HistoryResolution) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_cryptoalert_proto_enumTypes[1].Descriptor()
+}
+
+func (HistoryResolution) Type() protoreflect.EnumType {
+	return &file_api_cryptoalert_proto_enumTypes[1]
+}
+
+func (x HistoryResolution) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HistoryResolution.Descriptor instead.
+func (HistoryResolution) EnumDescriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{1}
+}
+
 // Alert comparator types
 type Comparator int32
 
@@ -65,11 +187,11 @@ func (x Comparator) String() string {
 }
 
 func (Comparator) Descriptor() protoreflect.EnumDescriptor {
-	return file_api_cryptoalert_proto_enumTypes[0].Descriptor()
+	return file_api_cryptoalert_proto_enumTypes[2].Descriptor()
 }
 
 func (Comparator) Type() protoreflect.EnumType {
-	return &file_api_cryptoalert_proto_enumTypes[0]
+	return &file_api_cryptoalert_proto_enumTypes[2]
 }
 
 func (x Comparator) Number() protoreflect.EnumNumber {
@@ -78,32 +200,1452 @@ func (x Comparator) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Comparator.Descriptor instead.
 func (Comparator) EnumDescriptor() ([]byte, []int) {
-	return file_api_cryptoalert_proto_rawDescGZIP(), []int{0}
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{2}
+}
+
+// Trigger modes for threshold alerts
+type TriggerMode int32
+
+const (
+	TriggerMode_TRIGGER_MODE_UNSPECIFIED TriggerMode = 0 // Treated as level
+	TriggerMode_TRIGGER_MODE_LEVEL       TriggerMode = 1 // Fire whenever the comparison holds (subject to cooldown)
+	TriggerMode_TRIGGER_MODE_CROSS_UP    TriggerMode = 2 // Fire when the price crosses the threshold upwards
+	TriggerMode_TRIGGER_MODE_CROSS_DOWN  TriggerMode = 3 // Fire when the price crosses the threshold downwards
+	TriggerMode_TRIGGER_MODE_CROSS_ANY   TriggerMode = 4 // Fire on a crossing in either direction
+)
+
+// Enum value maps for TriggerMode.
+var (
+	TriggerMode_name = map[int32]string{
+		0: "TRIGGER_MODE_UNSPECIFIED",
+		1: "TRIGGER_MODE_LEVEL",
+		2: "TRIGGER_MODE_CROSS_UP",
+		3: "TRIGGER_MODE_CROSS_DOWN",
+		4: "TRIGGER_MODE_CROSS_ANY",
+	}
+	TriggerMode_value = map[string]int32{
+		"TRIGGER_MODE_UNSPECIFIED": 0,
+		"TRIGGER_MODE_LEVEL":       1,
+		"TRIGGER_MODE_CROSS_UP":    2,
+		"TRIGGER_MODE_CROSS_DOWN":  3,
+		"TRIGGER_MODE_CROSS_ANY":   4,
+	}
+)
+
+func (x TriggerMode) Enum() *TriggerMode {
+	p := new(TriggerMode)
+	*p = x
+	return p
+}
+
+func (x TriggerMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TriggerMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_cryptoalert_proto_enumTypes[3].Descriptor()
+}
+
+func (TriggerMode) Type() protoreflect.EnumType {
+	return &file_api_cryptoalert_proto_enumTypes[3]
+}
+
+func (x TriggerMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TriggerMode.Descriptor instead.
+func (TriggerMode) EnumDescriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{3}
+}
+
+// Alert severity levels
+type Severity int32
+
+const (
+	Severity_SEVERITY_UNSPECIFIED Severity = 0 // Treated as info
+	Severity_SEVERITY_INFO        Severity = 1
+	Severity_SEVERITY_WARNING     Severity = 2
+	Severity_SEVERITY_CRITICAL    Severity = 3
+)
+
+// Enum value maps for Severity.
+var (
+	Severity_name = map[int32]string{
+		0: "SEVERITY_UNSPECIFIED",
+		1: "SEVERITY_INFO",
+		2: "SEVERITY_WARNING",
+		3: "SEVERITY_CRITICAL",
+	}
+	Severity_value = map[string]int32{
+		"SEVERITY_UNSPECIFIED": 0,
+		"SEVERITY_INFO":        1,
+		"SEVERITY_WARNING":     2,
+		"SEVERITY_CRITICAL":    3,
+	}
+)
+
+func (x Severity) Enum() *Severity {
+	p := new(Severity)
+	*p = x
+	return p
+}
+
+func (x Severity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Severity) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_cryptoalert_proto_enumTypes[4].Descriptor()
+}
+
+func (Severity) Type() protoreflect.EnumType {
+	return &file_api_cryptoalert_proto_enumTypes[4]
+}
+
+func (x Severity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Severity.Descriptor instead.
+func (Severity) EnumDescriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{4}
+}
+
+// Alert kinds
+type AlertKind int32
+
+const (
+	AlertKind_ALERT_KIND_UNSPECIFIED  AlertKind = 0 // Treated as threshold
+	AlertKind_ALERT_KIND_THRESHOLD    AlertKind = 1 // Price compared against a fixed threshold
+	AlertKind_ALERT_KIND_PERCENT_MOVE AlertKind = 2 // Price moves by a percentage within a window
+	AlertKind_ALERT_KIND_FEED_SILENCE AlertKind = 3 // System alert: no ticks for longer than window; cannot be created
+	AlertKind_ALERT_KIND_COMPOUND     AlertKind = 4 // Condition tree over one or more symbols
+	AlertKind_ALERT_KIND_EXPRESSION   AlertKind = 5 // Rule written in the expression language
+	AlertKind_ALERT_KIND_MA_CROSSOVER AlertKind = 6 // Fast moving average crosses the slow one in direction
+	AlertKind_ALERT_KIND_RSI          AlertKind = 7 // RSI crosses threshold: up = rises above, down = falls below
+	AlertKind_ALERT_KIND_BOLLINGER    AlertKind = 8 // Price closes outside the bands: up = above, down = below
+)
+
+// Enum value maps for AlertKind.
+var (
+	AlertKind_name = map[int32]string{
+		0: "ALERT_KIND_UNSPECIFIED",
+		1: "ALERT_KIND_THRESHOLD",
+		2: "ALERT_KIND_PERCENT_MOVE",
+		3: "ALERT_KIND_FEED_SILENCE",
+		4: "ALERT_KIND_COMPOUND",
+		5: "ALERT_KIND_EXPRESSION",
+		6: "ALERT_KIND_MA_CROSSOVER",
+		7: "ALERT_KIND_RSI",
+		8: "ALERT_KIND_BOLLINGER",
+	}
+	AlertKind_value = map[string]int32{
+		"ALERT_KIND_UNSPECIFIED":  0,
+		"ALERT_KIND_THRESHOLD":    1,
+		"ALERT_KIND_PERCENT_MOVE": 2,
+		"ALERT_KIND_FEED_SILENCE": 3,
+		"ALERT_KIND_COMPOUND":     4,
+		"ALERT_KIND_EXPRESSION":   5,
+		"ALERT_KIND_MA_CROSSOVER": 6,
+		"ALERT_KIND_RSI":          7,
+		"ALERT_KIND_BOLLINGER":    8,
+	}
+)
+
+func (x AlertKind) Enum() *AlertKind {
+	p := new(AlertKind)
+	*p = x
+	return p
+}
+
+func (x AlertKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AlertKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_cryptoalert_proto_enumTypes[5].Descriptor()
+}
+
+func (AlertKind) Type() protoreflect.EnumType {
+	return &file_api_cryptoalert_proto_enumTypes[5]
+}
+
+func (x AlertKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AlertKind.Descriptor instead.
+func (AlertKind) EnumDescriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{5}
+}
+
+// Moving average used by crossover alerts
+type MovingAverage int32
+
+const (
+	MovingAverage_MOVING_AVERAGE_UNSPECIFIED MovingAverage = 0 // Treated as SMA
+	MovingAverage_MOVING_AVERAGE_SMA         MovingAverage = 1
+	MovingAverage_MOVING_AVERAGE_EMA         MovingAverage = 2
+)
+
+// Enum value maps for MovingAverage.
+var (
+	MovingAverage_name = map[int32]string{
+		0: "MOVING_AVERAGE_UNSPECIFIED",
+		1: "MOVING_AVERAGE_SMA",
+		2: "MOVING_AVERAGE_EMA",
+	}
+	MovingAverage_value = map[string]int32{
+		"MOVING_AVERAGE_UNSPECIFIED": 0,
+		"MOVING_AVERAGE_SMA":         1,
+		"MOVING_AVERAGE_EMA":         2,
+	}
+)
+
+func (x MovingAverage) Enum() *MovingAverage {
+	p := new(MovingAverage)
+	*p = x
+	return p
+}
+
+func (x MovingAverage) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MovingAverage) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_cryptoalert_proto_enumTypes[6].Descriptor()
+}
+
+func (MovingAverage) Type() protoreflect.EnumType {
+	return &file_api_cryptoalert_proto_enumTypes[6]
+}
+
+func (x MovingAverage) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MovingAverage.Descriptor instead.
+func (MovingAverage) EnumDescriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{6}
+}
+
+// Condition tree node operators
+type ConditionOp int32
+
+const (
+	ConditionOp_CONDITION_OP_UNSPECIFIED ConditionOp = 0
+	ConditionOp_CONDITION_OP_COMPARE     ConditionOp = 1 // symbol's latest price compared against threshold
+	ConditionOp_CONDITION_OP_AND         ConditionOp = 2 // All children hold
+	ConditionOp_CONDITION_OP_OR          ConditionOp = 3 // Any child holds
+	ConditionOp_CONDITION_OP_NOT         ConditionOp = 4 // The single child does not hold
+)
+
+// Enum value maps for ConditionOp.
+var (
+	ConditionOp_name = map[int32]string{
+		0: "CONDITION_OP_UNSPECIFIED",
+		1: "CONDITION_OP_COMPARE",
+		2: "CONDITION_OP_AND",
+		3: "CONDITION_OP_OR",
+		4: "CONDITION_OP_NOT",
+	}
+	ConditionOp_value = map[string]int32{
+		"CONDITION_OP_UNSPECIFIED": 0,
+		"CONDITION_OP_COMPARE":     1,
+		"CONDITION_OP_AND":         2,
+		"CONDITION_OP_OR":          3,
+		"CONDITION_OP_NOT":         4,
+	}
+)
+
+func (x ConditionOp) Enum() *ConditionOp {
+	p := new(ConditionOp)
+	*p = x
+	return p
+}
+
+func (x ConditionOp) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ConditionOp) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_cryptoalert_proto_enumTypes[7].Descriptor()
+}
+
+func (ConditionOp) Type() protoreflect.EnumType {
+	return &file_api_cryptoalert_proto_enumTypes[7]
+}
+
+func (x ConditionOp) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ConditionOp.Descriptor instead.
+func (ConditionOp) EnumDescriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{7}
+}
+
+// Direction of a percentage move
+type MoveDirection int32
+
+const (
+	MoveDirection_MOVE_DIRECTION_UNSPECIFIED MoveDirection = 0 // Treated as either
+	MoveDirection_MOVE_DIRECTION_EITHER      MoveDirection = 1
+	MoveDirection_MOVE_DIRECTION_UP          MoveDirection = 2
+	MoveDirection_MOVE_DIRECTION_DOWN        MoveDirection = 3
+)
+
+// Enum value maps for MoveDirection.
+var (
+	MoveDirection_name = map[int32]string{
+		0: "MOVE_DIRECTION_UNSPECIFIED",
+		1: "MOVE_DIRECTION_EITHER",
+		2: "MOVE_DIRECTION_UP",
+		3: "MOVE_DIRECTION_DOWN",
+	}
+	MoveDirection_value = map[string]int32{
+		"MOVE_DIRECTION_UNSPECIFIED": 0,
+		"MOVE_DIRECTION_EITHER":      1,
+		"MOVE_DIRECTION_UP":          2,
+		"MOVE_DIRECTION_DOWN":        3,
+	}
+)
+
+func (x MoveDirection) Enum() *MoveDirection {
+	p := new(MoveDirection)
+	*p = x
+	return p
+}
+
+func (x MoveDirection) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MoveDirection) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_cryptoalert_proto_enumTypes[8].Descriptor()
+}
+
+func (MoveDirection) Type() protoreflect.EnumType {
+	return &file_api_cryptoalert_proto_enumTypes[8]
+}
+
+func (x MoveDirection) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MoveDirection.Descriptor instead.
+func (MoveDirection) EnumDescriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{8}
+}
+
+// What an AlertTrigger reports
+type TriggerEvent int32
+
+const (
+	TriggerEvent_TRIGGER_EVENT_ALERT          TriggerEvent = 0 // A user alert fired
+	TriggerEvent_TRIGGER_EVENT_FEED_STALE     TriggerEvent = 1 // System: the symbol stopped ticking
+	TriggerEvent_TRIGGER_EVENT_FEED_RECOVERED TriggerEvent = 2 // System: ticks resumed for the symbol
+)
+
+// Enum value maps for TriggerEvent.
+var (
+	TriggerEvent_name = map[int32]string{
+		0: "TRIGGER_EVENT_ALERT",
+		1: "TRIGGER_EVENT_FEED_STALE",
+		2: "TRIGGER_EVENT_FEED_RECOVERED",
+	}
+	TriggerEvent_value = map[string]int32{
+		"TRIGGER_EVENT_ALERT":          0,
+		"TRIGGER_EVENT_FEED_STALE":     1,
+		"TRIGGER_EVENT_FEED_RECOVERED": 2,
+	}
+)
+
+func (x TriggerEvent) Enum() *TriggerEvent {
+	p := new(TriggerEvent)
+	*p = x
+	return p
+}
+
+func (x TriggerEvent) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TriggerEvent) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_cryptoalert_proto_enumTypes[9].Descriptor()
+}
+
+func (TriggerEvent) Type() protoreflect.EnumType {
+	return &file_api_cryptoalert_proto_enumTypes[9]
+}
+
+func (x TriggerEvent) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TriggerEvent.Descriptor instead.
+func (TriggerEvent) EnumDescriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{9}
+}
+
+// Price subscription request
+type PriceSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbols       []string               `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"` // Crypto symbols to subscribe to (e.g., ["BTC", "ETH", "ADA"])
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`   // Venue to follow (e.g., "binance"); empty for the server's default price
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceSubscriptionRequest) Reset() {
+	*x = PriceSubscriptionRequest{}
+	mi := &file_api_cryptoalert_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceSubscriptionRequest) ProtoMessage() {}
+
+func (x *PriceSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_cryptoalert_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*PriceSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PriceSubscriptionRequest) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *PriceSubscriptionRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+// Real-time price tick
+type PriceTick struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Price         float64                `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Source        string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"` // Venue name, or "consolidated" for the aggregated price
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceTick) Reset() {
+	*x = PriceTick{}
+	mi := &file_api_cryptoalert_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceTick) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceTick) ProtoMessage() {}
+
+func (x *PriceTick) ProtoReflect() protoreflect.Message {
+	mi := &file_api_cryptoalert_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceTick.ProtoReflect.Descriptor instead.
+func (*PriceTick) Descriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PriceTick) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *PriceTick) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *PriceTick) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *PriceTick) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+// OHLCV candle built from the ticks within one interval
+type Candle struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Source        string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	Interval      CandleInterval         `protobuf:"varint,3,opt,name=interval,proto3,enum=cryptoalert.CandleInterval" json:"interval,omitempty"`
+	OpenTime      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=open_time,json=openTime,proto3" json:"open_time,omitempty"`    // Start of the interval
+	CloseTime     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=close_time,json=closeTime,proto3" json:"close_time,omitempty"` // End of the interval (exclusive)
+	Open          float64                `protobuf:"fixed64,6,opt,name=open,proto3" json:"open,omitempty"`
+	High          float64                `protobuf:"fixed64,7,opt,name=high,proto3" json:"high,omitempty"`
+	Low           float64                `protobuf:"fixed64,8,opt,name=low,proto3" json:"low,omitempty"`
+	Close         float64                `protobuf:"fixed64,9,opt,name=close,proto3" json:"close,omitempty"`
+	Volume        float64                `protobuf:"fixed64,10,opt,name=volume,proto3" json:"volume,omitempty"` // Last rolling 24h volume reported in the interval; zero for feeds that do not report volume
+	TickCount     uint32                 `protobuf:"varint,11,opt,name=tick_count,json=tickCount,proto3" json:"tick_count,omitempty"`
+	Closed        bool                   `protobuf:"varint,12,opt,name=closed,proto3" json:"closed,omitempty"` // False while later ticks may still change the candle
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Candle) Reset() {
+	*x = Candle{}
+	mi := &file_api_cryptoalert_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Candle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Candle) ProtoMessage() {}
+
+func (x *Candle) ProtoReflect() protoreflect.Message {
+	mi := &file_api_cryptoalert_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Candle.ProtoReflect.Descriptor instead.
+func (*Candle) Descriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Candle) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Candle) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Candle) GetInterval() CandleInterval {
+	if x != nil {
+		return x.Interval
+	}
+	return CandleInterval_CANDLE_INTERVAL_UNSPECIFIED
+}
+
+func (x *Candle) GetOpenTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OpenTime
+	}
+	return nil
+}
+
+func (x *Candle) GetCloseTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CloseTime
+	}
+	return nil
+}
+
+func (x *Candle) GetOpen() float64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *Candle) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *Candle) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *Candle) GetClose() float64 {
+	if x != nil {
+		return x.Close
+	}
+	return 0
+}
+
+func (x *Candle) GetVolume() float64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+func (x *Candle) GetTickCount() uint32 {
+	if x != nil {
+		return x.TickCount
+	}
+	return 0
+}
+
+func (x *Candle) GetClosed() bool {
+	if x != nil {
+		return x.Closed
+	}
+	return false
+}
+
+type CandleSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbols       []string               `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	Interval      CandleInterval         `protobuf:"varint,2,opt,name=interval,proto3,enum=cryptoalert.CandleInterval" json:"interval,omitempty"`
+	Source        string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"` // Venue to follow; empty for the server's default price
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CandleSubscriptionRequest) Reset() {
+	*x = CandleSubscriptionRequest{}
+	mi := &file_api_cryptoalert_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CandleSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CandleSubscriptionRequest) ProtoMessage() {}
+
+func (x *CandleSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_cryptoalert_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CandleSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*CandleSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CandleSubscriptionRequest) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *CandleSubscriptionRequest) GetInterval() CandleInterval {
+	if x != nil {
+		return x.Interval
+	}
+	return CandleInterval_CANDLE_INTERVAL_UNSPECIFIED
+}
+
+func (x *CandleSubscriptionRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type GetCandlesRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Symbol         string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Interval       CandleInterval         `protobuf:"varint,2,opt,name=interval,proto3,enum=cryptoalert.CandleInterval" json:"interval,omitempty"`
+	Source         string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`                                        // Venue to follow; empty for the server's default price
+	Limit          uint32                 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`                                         // Most recent candles to return; 0 for 100
+	IncludePartial bool                   `protobuf:"varint,5,opt,name=include_partial,json=includePartial,proto3" json:"include_partial,omitempty"` // Append the candle still in progress
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetCandlesRequest) Reset() {
+	*x = GetCandlesRequest{}
+	mi := &file_api_cryptoalert_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCandlesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCandlesRequest) ProtoMessage() {}
+
+func (x *GetCandlesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_cryptoalert_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCandlesRequest.ProtoReflect.Descriptor instead.
+func (*GetCandlesRequest) Descriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetCandlesRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetCandlesRequest) GetInterval() CandleInterval {
+	if x != nil {
+		return x.Interval
+	}
+	return CandleInterval_CANDLE_INTERVAL_UNSPECIFIED
+}
+
+func (x *GetCandlesRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *GetCandlesRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetCandlesRequest) GetIncludePartial() bool {
+	if x != nil {
+		return x.IncludePartial
+	}
+	return false
+}
+
+type GetCandlesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Candles       []*Candle              `protobuf:"bytes,1,rep,name=candles,proto3" json:"candles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCandlesResponse) Reset() {
+	*x = GetCandlesResponse{}
+	mi := &file_api_cryptoalert_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCandlesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCandlesResponse) ProtoMessage() {}
+
+func (x *GetCandlesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_cryptoalert_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCandlesResponse.ProtoReflect.Descriptor instead.
+func (*GetCandlesResponse) Descriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetCandlesResponse) GetCandles() []*Candle {
+	if x != nil {
+		return x.Candles
+	}
+	return nil
+}
+
+type GetPriceHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"` // Defaults to an hour before to
+	To            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`     // Exclusive; defaults to now
+	Resolution    HistoryResolution      `protobuf:"varint,4,opt,name=resolution,proto3,enum=cryptoalert.HistoryResolution" json:"resolution,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPriceHistoryRequest) Reset() {
+	*x = GetPriceHistoryRequest{}
+	mi := &file_api_cryptoalert_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPriceHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPriceHistoryRequest) ProtoMessage() {}
+
+func (x *GetPriceHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_cryptoalert_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPriceHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetPriceHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetPriceHistoryRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetPriceHistoryRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GetPriceHistoryRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *GetPriceHistoryRequest) GetResolution() HistoryResolution {
+	if x != nil {
+		return x.Resolution
+	}
+	return HistoryResolution_HISTORY_RESOLUTION_UNSPECIFIED
+}
+
+// One tick at the raw resolution, or an OHLC bar starting at timestamp
+type PricePoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Open          float64                `protobuf:"fixed64,2,opt,name=open,proto3" json:"open,omitempty"`
+	High          float64                `protobuf:"fixed64,3,opt,name=high,proto3" json:"high,omitempty"`
+	Low           float64                `protobuf:"fixed64,4,opt,name=low,proto3" json:"low,omitempty"`
+	Close         float64                `protobuf:"fixed64,5,opt,name=close,proto3" json:"close,omitempty"`
+	Volume        float64                `protobuf:"fixed64,6,opt,name=volume,proto3" json:"volume,omitempty"` // Last rolling 24h volume reported in the bar
+	TickCount     uint32                 `protobuf:"varint,7,opt,name=tick_count,json=tickCount,proto3" json:"tick_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PricePoint) Reset() {
+	*x = PricePoint{}
+	mi := &file_api_cryptoalert_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PricePoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PricePoint) ProtoMessage() {}
+
+func (x *PricePoint) ProtoReflect() protoreflect.Message {
+	mi := &file_api_cryptoalert_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PricePoint.ProtoReflect.Descriptor instead.
+func (*PricePoint) Descriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PricePoint) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *PricePoint) GetOpen() float64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *PricePoint) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *PricePoint) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *PricePoint) GetClose() float64 {
+	if x != nil {
+		return x.Close
+	}
+	return 0
+}
+
+func (x *PricePoint) GetVolume() float64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+func (x *PricePoint) GetTickCount() uint32 {
+	if x != nil {
+		return x.TickCount
+	}
+	return 0
+}
+
+type GetPriceHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Points        []*PricePoint          `protobuf:"bytes,1,rep,name=points,proto3" json:"points,omitempty"`
+	Resolution    HistoryResolution      `protobuf:"varint,2,opt,name=resolution,proto3,enum=cryptoalert.HistoryResolution" json:"resolution,omitempty"` // The resolution used
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPriceHistoryResponse) Reset() {
+	*x = GetPriceHistoryResponse{}
+	mi := &file_api_cryptoalert_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPriceHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPriceHistoryResponse) ProtoMessage() {}
+
+func (x *GetPriceHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_cryptoalert_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPriceHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetPriceHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetPriceHistoryResponse) GetPoints() []*PricePoint {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+func (x *GetPriceHistoryResponse) GetResolution() HistoryResolution {
+	if x != nil {
+		return x.Resolution
+	}
+	return HistoryResolution_HISTORY_RESOLUTION_UNSPECIFIED
+}
+
+// Indicator parameters for crossover, RSI and Bollinger alerts. Indicators
+// are computed on the close of each interval-long bar, or on every tick
+// when interval is unset.
+type Indicator struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Average       MovingAverage          `protobuf:"varint,1,opt,name=average,proto3,enum=cryptoalert.MovingAverage" json:"average,omitempty"` // Crossover alerts only
+	Period        uint32                 `protobuf:"varint,2,opt,name=period,proto3" json:"period,omitempty"`                                  // Fast period for crossovers; RSI and Bollinger period
+	SlowPeriod    uint32                 `protobuf:"varint,3,opt,name=slow_period,json=slowPeriod,proto3" json:"slow_period,omitempty"`        // Crossover alerts only
+	StdDevs       float64                `protobuf:"fixed64,4,opt,name=std_devs,json=stdDevs,proto3" json:"std_devs,omitempty"`                // Bollinger band width; defaults to 2
+	Interval      *durationpb.Duration   `protobuf:"bytes,5,opt,name=interval,proto3" json:"interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Indicator) Reset() {
+	*x = Indicator{}
+	mi := &file_api_cryptoalert_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Indicator) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Indicator) ProtoMessage() {}
+
+func (x *Indicator) ProtoReflect() protoreflect.Message {
+	mi := &file_api_cryptoalert_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Indicator.ProtoReflect.Descriptor instead.
+func (*Indicator) Descriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Indicator) GetAverage() MovingAverage {
+	if x != nil {
+		return x.Average
+	}
+	return MovingAverage_MOVING_AVERAGE_UNSPECIFIED
+}
+
+func (x *Indicator) GetPeriod() uint32 {
+	if x != nil {
+		return x.Period
+	}
+	return 0
+}
+
+func (x *Indicator) GetSlowPeriod() uint32 {
+	if x != nil {
+		return x.SlowPeriod
+	}
+	return 0
+}
+
+func (x *Indicator) GetStdDevs() float64 {
+	if x != nil {
+		return x.StdDevs
+	}
+	return 0
+}
+
+func (x *Indicator) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+// Condition tree for compound alerts. A compound alert is re-evaluated
+// whenever any symbol it references ticks.
+type Condition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Op            ConditionOp            `protobuf:"varint,1,opt,name=op,proto3,enum=cryptoalert.ConditionOp" json:"op,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`                                      // Compare nodes only
+	Comparator    Comparator             `protobuf:"varint,3,opt,name=comparator,proto3,enum=cryptoalert.Comparator" json:"comparator,omitempty"` // Compare nodes only
+	Threshold     float64                `protobuf:"fixed64,4,opt,name=threshold,proto3" json:"threshold,omitempty"`                              // Compare nodes only
+	Children      []*Condition           `protobuf:"bytes,5,rep,name=children,proto3" json:"children,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Condition) Reset() {
+	*x = Condition{}
+	mi := &file_api_cryptoalert_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Condition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Condition) ProtoMessage() {}
+
+func (x *Condition) ProtoReflect() protoreflect.Message {
+	mi := &file_api_cryptoalert_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Condition.ProtoReflect.Descriptor instead.
+func (*Condition) Descriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Condition) GetOp() ConditionOp {
+	if x != nil {
+		return x.Op
+	}
+	return ConditionOp_CONDITION_OP_UNSPECIFIED
+}
+
+func (x *Condition) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Condition) GetComparator() Comparator {
+	if x != nil {
+		return x.Comparator
+	}
+	return Comparator_COMPARATOR_UNSPECIFIED
+}
+
+func (x *Condition) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *Condition) GetChildren() []*Condition {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+// Alert definition
+type Alert struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Comparator    Comparator             `protobuf:"varint,3,opt,name=comparator,proto3,enum=cryptoalert.Comparator" json:"comparator,omitempty"`
+	Threshold     float64                `protobuf:"fixed64,4,opt,name=threshold,proto3" json:"threshold,omitempty"` // Price for threshold alerts, percent for percent-move alerts
+	Note          string                 `protobuf:"bytes,5,opt,name=note,proto3" json:"note,omitempty"`
+	Enabled       bool                   `protobuf:"varint,6,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	LastTrigger   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=last_trigger,json=lastTrigger,proto3" json:"last_trigger,omitempty"`
+	Kind          AlertKind              `protobuf:"varint,8,opt,name=kind,proto3,enum=cryptoalert.AlertKind" json:"kind,omitempty"`
+	Direction     MoveDirection          `protobuf:"varint,9,opt,name=direction,proto3,enum=cryptoalert.MoveDirection" json:"direction,omitempty"`
+	Window        *durationpb.Duration   `protobuf:"bytes,10,opt,name=window,proto3" json:"window,omitempty"`
+	Mode          TriggerMode            `protobuf:"varint,11,opt,name=mode,proto3,enum=cryptoalert.TriggerMode" json:"mode,omitempty"`
+	Source        string                 `protobuf:"bytes,12,opt,name=source,proto3" json:"source,omitempty"` // Venue the alert follows; empty for the server's default price
+	Labels        []string               `protobuf:"bytes,13,rep,name=labels,proto3" json:"labels,omitempty"`
+	Severity      Severity               `protobuf:"varint,14,opt,name=severity,proto3,enum=cryptoalert.Severity" json:"severity,omitempty"`
+	WebhookUrl    string                 `protobuf:"bytes,15,opt,name=webhook_url,json=webhookUrl,proto3" json:"webhook_url,omitempty"` // Notified on every trigger in addition to the server's global webhooks
+	OwnerId       string                 `protobuf:"bytes,16,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	Condition     *Condition             `protobuf:"bytes,17,opt,name=condition,proto3" json:"condition,omitempty"`                   // Compound alerts only
+	Expression    string                 `protobuf:"bytes,18,opt,name=expression,proto3" json:"expression,omitempty"`                 // Expression alerts only
+	Indicator     *Indicator             `protobuf:"bytes,19,opt,name=indicator,proto3" json:"indicator,omitempty"`                   // Indicator alerts only
+	Cooldown      *durationpb.Duration   `protobuf:"bytes,20,opt,name=cooldown,proto3" json:"cooldown,omitempty"`                     // Minimum time between triggers; unset uses the server's cooldown
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,21,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`  // The alert disables itself once this passes
+	FireOnce      bool                   `protobuf:"varint,22,opt,name=fire_once,json=fireOnce,proto3" json:"fire_once,omitempty"`    // Disable after the first trigger
+	MaxFires      int32                  `protobuf:"varint,23,opt,name=max_fires,json=maxFires,proto3" json:"max_fires,omitempty"`    // Disable after this many triggers; 0 is unlimited
+	FireCount     int32                  `protobuf:"varint,24,opt,name=fire_count,json=fireCount,proto3" json:"fire_count,omitempty"` // Triggers since the alert was last enabled
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Alert) Reset() {
+	*x = Alert{}
+	mi := &file_api_cryptoalert_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Alert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alert) ProtoMessage() {}
+
+func (x *Alert) ProtoReflect() protoreflect.Message {
+	mi := &file_api_cryptoalert_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alert.ProtoReflect.Descriptor instead.
+func (*Alert) Descriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Alert) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Alert) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Alert) GetComparator() Comparator {
+	if x != nil {
+		return x.Comparator
+	}
+	return Comparator_COMPARATOR_UNSPECIFIED
+}
+
+func (x *Alert) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *Alert) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *Alert) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *Alert) GetLastTrigger() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastTrigger
+	}
+	return nil
+}
+
+func (x *Alert) GetKind() AlertKind {
+	if x != nil {
+		return x.Kind
+	}
+	return AlertKind_ALERT_KIND_UNSPECIFIED
+}
+
+func (x *Alert) GetDirection() MoveDirection {
+	if x != nil {
+		return x.Direction
+	}
+	return MoveDirection_MOVE_DIRECTION_UNSPECIFIED
+}
+
+func (x *Alert) GetWindow() *durationpb.Duration {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *Alert) GetMode() TriggerMode {
+	if x != nil {
+		return x.Mode
+	}
+	return TriggerMode_TRIGGER_MODE_UNSPECIFIED
+}
+
+func (x *Alert) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Alert) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Alert) GetSeverity() Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+func (x *Alert) GetWebhookUrl() string {
+	if x != nil {
+		return x.WebhookUrl
+	}
+	return ""
+}
+
+func (x *Alert) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *Alert) GetCondition() *Condition {
+	if x != nil {
+		return x.Condition
+	}
+	return nil
+}
+
+func (x *Alert) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+func (x *Alert) GetIndicator() *Indicator {
+	if x != nil {
+		return x.Indicator
+	}
+	return nil
+}
+
+func (x *Alert) GetCooldown() *durationpb.Duration {
+	if x != nil {
+		return x.Cooldown
+	}
+	return nil
+}
+
+func (x *Alert) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Alert) GetFireOnce() bool {
+	if x != nil {
+		return x.FireOnce
+	}
+	return false
+}
+
+func (x *Alert) GetMaxFires() int32 {
+	if x != nil {
+		return x.MaxFires
+	}
+	return 0
+}
+
+func (x *Alert) GetFireCount() int32 {
+	if x != nil {
+		return x.FireCount
+	}
+	return 0
 }
 
-// Price subscription request
-type PriceSubscriptionRequest struct {
+// Label set, used where the whole list must be replaced at once
+type LabelList struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Symbols       []string               `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"` // Crypto symbols to subscribe to (e.g., ["BTC", "ETH", "ADA"])
+	Labels        []string               `protobuf:"bytes,1,rep,name=labels,proto3" json:"labels,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PriceSubscriptionRequest) Reset() {
-	*x = PriceSubscriptionRequest{}
-	mi := &file_api_cryptoalert_proto_msgTypes[0]
+func (x *LabelList) Reset() {
+	*x = LabelList{}
+	mi := &file_api_cryptoalert_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PriceSubscriptionRequest) String() string {
+func (x *LabelList) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PriceSubscriptionRequest) ProtoMessage() {}
+func (*LabelList) ProtoMessage() {}
 
-func (x *PriceSubscriptionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_cryptoalert_proto_msgTypes[0]
+func (x *LabelList) ProtoReflect() protoreflect.Message {
+	mi := &file_api_cryptoalert_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -114,43 +1656,64 @@ func (x *PriceSubscriptionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PriceSubscriptionRequest.ProtoReflect.Descriptor instead.
-func (*PriceSubscriptionRequest) Descriptor() ([]byte, []int) {
-	return file_api_cryptoalert_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use LabelList.ProtoReflect.Descriptor instead.
+func (*LabelList) Descriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *PriceSubscriptionRequest) GetSymbols() []string {
+func (x *LabelList) GetLabels() []string {
 	if x != nil {
-		return x.Symbols
+		return x.Labels
 	}
 	return nil
 }
 
-// Real-time price tick
-type PriceTick struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
-	Price         float64                `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+// Create alert request
+type CreateAlertRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Symbol     string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Comparator Comparator             `protobuf:"varint,2,opt,name=comparator,proto3,enum=cryptoalert.Comparator" json:"comparator,omitempty"` // Ignored for percent-move alerts
+	Threshold  float64                `protobuf:"fixed64,3,opt,name=threshold,proto3" json:"threshold,omitempty"`                              // Price for threshold alerts, percent for percent-move alerts
+	Note       string                 `protobuf:"bytes,4,opt,name=note,proto3" json:"note,omitempty"`
+	Kind       AlertKind              `protobuf:"varint,5,opt,name=kind,proto3,enum=cryptoalert.AlertKind" json:"kind,omitempty"`
+	Direction  MoveDirection          `protobuf:"varint,6,opt,name=direction,proto3,enum=cryptoalert.MoveDirection" json:"direction,omitempty"` // Percent-move alerts only
+	Window     *durationpb.Duration   `protobuf:"bytes,7,opt,name=window,proto3" json:"window,omitempty"`                                       // Percent-move alerts only
+	Mode       TriggerMode            `protobuf:"varint,8,opt,name=mode,proto3,enum=cryptoalert.TriggerMode" json:"mode,omitempty"`             // Threshold alerts only; comparator is ignored for crossings
+	Source     string                 `protobuf:"bytes,9,opt,name=source,proto3" json:"source,omitempty"`                                       // Venue to follow (e.g., "binance"); empty for the server's default price
+	Labels     []string               `protobuf:"bytes,10,rep,name=labels,proto3" json:"labels,omitempty"`
+	Severity   Severity               `protobuf:"varint,11,opt,name=severity,proto3,enum=cryptoalert.Severity" json:"severity,omitempty"`
+	WebhookUrl string                 `protobuf:"bytes,12,opt,name=webhook_url,json=webhookUrl,proto3" json:"webhook_url,omitempty"`
+	OwnerId    string                 `protobuf:"bytes,13,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"` // Admins only: create the alert for another user
+	Condition  *Condition             `protobuf:"bytes,14,opt,name=condition,proto3" json:"condition,omitempty"`            // Compound alerts only; symbol, comparator and threshold are ignored
+	// Expression alerts only, e.g. "pct_change(BTC, 1h) > 5 && price(ETH) < sma(ETH, 50)".
+	// Built-ins: price(sym), change(sym, window), pct_change(sym, window),
+	// high(sym, window), low(sym, window), sma(sym, samples), abs(x), min(x, y),
+	// max(x, y). Errors are reported as InvalidArgument with the column.
+	Expression    string                 `protobuf:"bytes,15,opt,name=expression,proto3" json:"expression,omitempty"`
+	Indicator     *Indicator             `protobuf:"bytes,16,opt,name=indicator,proto3" json:"indicator,omitempty"`                  // Indicator alerts only; threshold is the RSI level
+	Cooldown      *durationpb.Duration   `protobuf:"bytes,17,opt,name=cooldown,proto3" json:"cooldown,omitempty"`                    // Unset uses the server's cooldown
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Must be in the future
+	FireOnce      bool                   `protobuf:"varint,19,opt,name=fire_once,json=fireOnce,proto3" json:"fire_once,omitempty"`
+	MaxFires      int32                  `protobuf:"varint,20,opt,name=max_fires,json=maxFires,proto3" json:"max_fires,omitempty"` // 0 is unlimited
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PriceTick) Reset() {
-	*x = PriceTick{}
-	mi := &file_api_cryptoalert_proto_msgTypes[1]
+func (x *CreateAlertRequest) Reset() {
+	*x = CreateAlertRequest{}
+	mi := &file_api_cryptoalert_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PriceTick) String() string {
+func (x *CreateAlertRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PriceTick) ProtoMessage() {}
+func (*CreateAlertRequest) ProtoMessage() {}
 
-func (x *PriceTick) ProtoReflect() protoreflect.Message {
-	mi := &file_api_cryptoalert_proto_msgTypes[1]
+func (x *CreateAlertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_cryptoalert_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -161,205 +1724,163 @@ func (x *PriceTick) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PriceTick.ProtoReflect.Descriptor instead.
-func (*PriceTick) Descriptor() ([]byte, []int) {
-	return file_api_cryptoalert_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use CreateAlertRequest.ProtoReflect.Descriptor instead.
+func (*CreateAlertRequest) Descriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *PriceTick) GetSymbol() string {
+func (x *CreateAlertRequest) GetSymbol() string {
 	if x != nil {
 		return x.Symbol
 	}
 	return ""
 }
 
-func (x *PriceTick) GetPrice() float64 {
+func (x *CreateAlertRequest) GetComparator() Comparator {
 	if x != nil {
-		return x.Price
+		return x.Comparator
 	}
-	return 0
+	return Comparator_COMPARATOR_UNSPECIFIED
 }
 
-func (x *PriceTick) GetTimestamp() *timestamppb.Timestamp {
+func (x *CreateAlertRequest) GetThreshold() float64 {
 	if x != nil {
-		return x.Timestamp
+		return x.Threshold
 	}
-	return nil
-}
-
-// Alert definition
-type Alert struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
-	Comparator    Comparator             `protobuf:"varint,3,opt,name=comparator,proto3,enum=cryptoalert.Comparator" json:"comparator,omitempty"`
-	Threshold     float64                `protobuf:"fixed64,4,opt,name=threshold,proto3" json:"threshold,omitempty"`
-	Note          string                 `protobuf:"bytes,5,opt,name=note,proto3" json:"note,omitempty"`
-	Enabled       bool                   `protobuf:"varint,6,opt,name=enabled,proto3" json:"enabled,omitempty"`
-	LastTrigger   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=last_trigger,json=lastTrigger,proto3" json:"last_trigger,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	return 0
 }
 
-func (x *Alert) Reset() {
-	*x = Alert{}
-	mi := &file_api_cryptoalert_proto_msgTypes[2]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *CreateAlertRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
 }
 
-func (x *Alert) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *CreateAlertRequest) GetKind() AlertKind {
+	if x != nil {
+		return x.Kind
+	}
+	return AlertKind_ALERT_KIND_UNSPECIFIED
 }
 
-func (*Alert) ProtoMessage() {}
-
-func (x *Alert) ProtoReflect() protoreflect.Message {
-	mi := &file_api_cryptoalert_proto_msgTypes[2]
+func (x *CreateAlertRequest) GetDirection() MoveDirection {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Direction
 	}
-	return mi.MessageOf(x)
+	return MoveDirection_MOVE_DIRECTION_UNSPECIFIED
 }
 
-// Deprecated: Use Alert.ProtoReflect.Descriptor instead.
-func (*Alert) Descriptor() ([]byte, []int) {
-	return file_api_cryptoalert_proto_rawDescGZIP(), []int{2}
+func (x *CreateAlertRequest) GetWindow() *durationpb.Duration {
+	if x != nil {
+		return x.Window
+	}
+	return nil
 }
 
-func (x *Alert) GetId() string {
+func (x *CreateAlertRequest) GetMode() TriggerMode {
 	if x != nil {
-		return x.Id
+		return x.Mode
 	}
-	return ""
+	return TriggerMode_TRIGGER_MODE_UNSPECIFIED
 }
 
-func (x *Alert) GetSymbol() string {
+func (x *CreateAlertRequest) GetSource() string {
 	if x != nil {
-		return x.Symbol
+		return x.Source
 	}
 	return ""
 }
 
-func (x *Alert) GetComparator() Comparator {
+func (x *CreateAlertRequest) GetLabels() []string {
 	if x != nil {
-		return x.Comparator
+		return x.Labels
 	}
-	return Comparator_COMPARATOR_UNSPECIFIED
+	return nil
 }
 
-func (x *Alert) GetThreshold() float64 {
+func (x *CreateAlertRequest) GetSeverity() Severity {
 	if x != nil {
-		return x.Threshold
+		return x.Severity
 	}
-	return 0
+	return Severity_SEVERITY_UNSPECIFIED
 }
 
-func (x *Alert) GetNote() string {
+func (x *CreateAlertRequest) GetWebhookUrl() string {
 	if x != nil {
-		return x.Note
+		return x.WebhookUrl
 	}
 	return ""
 }
 
-func (x *Alert) GetEnabled() bool {
+func (x *CreateAlertRequest) GetOwnerId() string {
 	if x != nil {
-		return x.Enabled
+		return x.OwnerId
 	}
-	return false
+	return ""
 }
 
-func (x *Alert) GetLastTrigger() *timestamppb.Timestamp {
+func (x *CreateAlertRequest) GetCondition() *Condition {
 	if x != nil {
-		return x.LastTrigger
+		return x.Condition
 	}
 	return nil
 }
 
-// Create alert request
-type CreateAlertRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
-	Comparator    Comparator             `protobuf:"varint,2,opt,name=comparator,proto3,enum=cryptoalert.Comparator" json:"comparator,omitempty"`
-	Threshold     float64                `protobuf:"fixed64,3,opt,name=threshold,proto3" json:"threshold,omitempty"`
-	Note          string                 `protobuf:"bytes,4,opt,name=note,proto3" json:"note,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *CreateAlertRequest) Reset() {
-	*x = CreateAlertRequest{}
-	mi := &file_api_cryptoalert_proto_msgTypes[3]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *CreateAlertRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*CreateAlertRequest) ProtoMessage() {}
-
-func (x *CreateAlertRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_cryptoalert_proto_msgTypes[3]
+func (x *CreateAlertRequest) GetExpression() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Expression
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use CreateAlertRequest.ProtoReflect.Descriptor instead.
-func (*CreateAlertRequest) Descriptor() ([]byte, []int) {
-	return file_api_cryptoalert_proto_rawDescGZIP(), []int{3}
+func (x *CreateAlertRequest) GetIndicator() *Indicator {
+	if x != nil {
+		return x.Indicator
+	}
+	return nil
 }
 
-func (x *CreateAlertRequest) GetSymbol() string {
+func (x *CreateAlertRequest) GetCooldown() *durationpb.Duration {
 	if x != nil {
-		return x.Symbol
+		return x.Cooldown
 	}
-	return ""
+	return nil
 }
 
-func (x *CreateAlertRequest) GetComparator() Comparator {
+func (x *CreateAlertRequest) GetExpiresAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Comparator
+		return x.ExpiresAt
 	}
-	return Comparator_COMPARATOR_UNSPECIFIED
+	return nil
 }
 
-func (x *CreateAlertRequest) GetThreshold() float64 {
+func (x *CreateAlertRequest) GetFireOnce() bool {
 	if x != nil {
-		return x.Threshold
+		return x.FireOnce
 	}
-	return 0
+	return false
 }
 
-func (x *CreateAlertRequest) GetNote() string {
+func (x *CreateAlertRequest) GetMaxFires() int32 {
 	if x != nil {
-		return x.Note
+		return x.MaxFires
 	}
-	return ""
+	return 0
 }
 
 // Create alert response
 type CreateAlertResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Alert         *Alert                 `protobuf:"bytes,1,opt,name=alert,proto3" json:"alert,omitempty"`
+	WebhookSecret string                 `protobuf:"bytes,2,opt,name=webhook_secret,json=webhookSecret,proto3" json:"webhook_secret,omitempty"` // Signs the alert's webhook deliveries; only returned here
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateAlertResponse) Reset() {
 	*x = CreateAlertResponse{}
-	mi := &file_api_cryptoalert_proto_msgTypes[4]
+	mi := &file_api_cryptoalert_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -371,7 +1892,7 @@ func (x *CreateAlertResponse) String() string {
 func (*CreateAlertResponse) ProtoMessage() {}
 
 func (x *CreateAlertResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_cryptoalert_proto_msgTypes[4]
+	mi := &file_api_cryptoalert_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -384,7 +1905,7 @@ func (x *CreateAlertResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateAlertResponse.ProtoReflect.Descriptor instead.
 func (*CreateAlertResponse) Descriptor() ([]byte, []int) {
-	return file_api_cryptoalert_proto_rawDescGZIP(), []int{4}
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *CreateAlertResponse) GetAlert() *Alert {
@@ -394,16 +1915,24 @@ func (x *CreateAlertResponse) GetAlert() *Alert {
 	return nil
 }
 
+func (x *CreateAlertResponse) GetWebhookSecret() string {
+	if x != nil {
+		return x.WebhookSecret
+	}
+	return ""
+}
+
 // Get alerts request
 type GetAlertsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	OwnerId       string                 `protobuf:"bytes,1,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"` // Admins only: list one owner's alerts; empty lists all
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetAlertsRequest) Reset() {
 	*x = GetAlertsRequest{}
-	mi := &file_api_cryptoalert_proto_msgTypes[5]
+	mi := &file_api_cryptoalert_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -415,7 +1944,7 @@ func (x *GetAlertsRequest) String() string {
 func (*GetAlertsRequest) ProtoMessage() {}
 
 func (x *GetAlertsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_cryptoalert_proto_msgTypes[5]
+	mi := &file_api_cryptoalert_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -428,7 +1957,14 @@ func (x *GetAlertsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAlertsRequest.ProtoReflect.Descriptor instead.
 func (*GetAlertsRequest) Descriptor() ([]byte, []int) {
-	return file_api_cryptoalert_proto_rawDescGZIP(), []int{5}
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetAlertsRequest) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
 }
 
 // Get alerts response
@@ -441,7 +1977,7 @@ type GetAlertsResponse struct {
 
 func (x *GetAlertsResponse) Reset() {
 	*x = GetAlertsResponse{}
-	mi := &file_api_cryptoalert_proto_msgTypes[6]
+	mi := &file_api_cryptoalert_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -453,7 +1989,7 @@ func (x *GetAlertsResponse) String() string {
 func (*GetAlertsResponse) ProtoMessage() {}
 
 func (x *GetAlertsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_cryptoalert_proto_msgTypes[6]
+	mi := &file_api_cryptoalert_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -466,7 +2002,7 @@ func (x *GetAlertsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAlertsResponse.ProtoReflect.Descriptor instead.
 func (*GetAlertsResponse) Descriptor() ([]byte, []int) {
-	return file_api_cryptoalert_proto_rawDescGZIP(), []int{6}
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *GetAlertsResponse) GetAlerts() []*Alert {
@@ -485,13 +2021,27 @@ type UpdateAlertRequest struct {
 	Threshold     *float64               `protobuf:"fixed64,4,opt,name=threshold,proto3,oneof" json:"threshold,omitempty"`
 	Note          *string                `protobuf:"bytes,5,opt,name=note,proto3,oneof" json:"note,omitempty"`
 	Enabled       *bool                  `protobuf:"varint,6,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`
+	Direction     *MoveDirection         `protobuf:"varint,7,opt,name=direction,proto3,enum=cryptoalert.MoveDirection,oneof" json:"direction,omitempty"`
+	Window        *durationpb.Duration   `protobuf:"bytes,8,opt,name=window,proto3" json:"window,omitempty"`
+	Mode          *TriggerMode           `protobuf:"varint,9,opt,name=mode,proto3,enum=cryptoalert.TriggerMode,oneof" json:"mode,omitempty"`
+	Source        *string                `protobuf:"bytes,10,opt,name=source,proto3,oneof" json:"source,omitempty"`
+	Severity      *Severity              `protobuf:"varint,11,opt,name=severity,proto3,enum=cryptoalert.Severity,oneof" json:"severity,omitempty"`
+	Labels        *LabelList             `protobuf:"bytes,12,opt,name=labels,proto3" json:"labels,omitempty"`                                 // Replaces all labels when set
+	WebhookUrl    *string                `protobuf:"bytes,13,opt,name=webhook_url,json=webhookUrl,proto3,oneof" json:"webhook_url,omitempty"` // Empty string removes the webhook
+	Condition     *Condition             `protobuf:"bytes,14,opt,name=condition,proto3" json:"condition,omitempty"`                           // Compound alerts only: replaces the condition when set
+	Expression    *string                `protobuf:"bytes,15,opt,name=expression,proto3,oneof" json:"expression,omitempty"`                   // Expression alerts only
+	Indicator     *Indicator             `protobuf:"bytes,16,opt,name=indicator,proto3" json:"indicator,omitempty"`                           // Indicator alerts only: replaces the parameters when set
+	Cooldown      *durationpb.Duration   `protobuf:"bytes,17,opt,name=cooldown,proto3" json:"cooldown,omitempty"`                             // Zero restores the server's cooldown
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`          // The Unix epoch (seconds = 0) removes the expiry
+	FireOnce      *bool                  `protobuf:"varint,19,opt,name=fire_once,json=fireOnce,proto3,oneof" json:"fire_once,omitempty"`
+	MaxFires      *int32                 `protobuf:"varint,20,opt,name=max_fires,json=maxFires,proto3,oneof" json:"max_fires,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateAlertRequest) Reset() {
 	*x = UpdateAlertRequest{}
-	mi := &file_api_cryptoalert_proto_msgTypes[7]
+	mi := &file_api_cryptoalert_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -503,7 +2053,7 @@ func (x *UpdateAlertRequest) String() string {
 func (*UpdateAlertRequest) ProtoMessage() {}
 
 func (x *UpdateAlertRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_cryptoalert_proto_msgTypes[7]
+	mi := &file_api_cryptoalert_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -511,67 +2061,166 @@ func (x *UpdateAlertRequest) ProtoReflect() protoreflect.Message {
 		}
 		return ms
 	}
-	return mi.MessageOf(x)
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAlertRequest.ProtoReflect.Descriptor instead.
+func (*UpdateAlertRequest) Descriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UpdateAlertRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateAlertRequest) GetSymbol() string {
+	if x != nil && x.Symbol != nil {
+		return *x.Symbol
+	}
+	return ""
+}
+
+func (x *UpdateAlertRequest) GetComparator() Comparator {
+	if x != nil && x.Comparator != nil {
+		return *x.Comparator
+	}
+	return Comparator_COMPARATOR_UNSPECIFIED
+}
+
+func (x *UpdateAlertRequest) GetThreshold() float64 {
+	if x != nil && x.Threshold != nil {
+		return *x.Threshold
+	}
+	return 0
+}
+
+func (x *UpdateAlertRequest) GetNote() string {
+	if x != nil && x.Note != nil {
+		return *x.Note
+	}
+	return ""
+}
+
+func (x *UpdateAlertRequest) GetEnabled() bool {
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
+	}
+	return false
+}
+
+func (x *UpdateAlertRequest) GetDirection() MoveDirection {
+	if x != nil && x.Direction != nil {
+		return *x.Direction
+	}
+	return MoveDirection_MOVE_DIRECTION_UNSPECIFIED
+}
+
+func (x *UpdateAlertRequest) GetWindow() *durationpb.Duration {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *UpdateAlertRequest) GetMode() TriggerMode {
+	if x != nil && x.Mode != nil {
+		return *x.Mode
+	}
+	return TriggerMode_TRIGGER_MODE_UNSPECIFIED
+}
+
+func (x *UpdateAlertRequest) GetSource() string {
+	if x != nil && x.Source != nil {
+		return *x.Source
+	}
+	return ""
 }
 
-// Deprecated: Use UpdateAlertRequest.ProtoReflect.Descriptor instead.
-func (*UpdateAlertRequest) Descriptor() ([]byte, []int) {
-	return file_api_cryptoalert_proto_rawDescGZIP(), []int{7}
+func (x *UpdateAlertRequest) GetSeverity() Severity {
+	if x != nil && x.Severity != nil {
+		return *x.Severity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
 }
 
-func (x *UpdateAlertRequest) GetId() string {
+func (x *UpdateAlertRequest) GetLabels() *LabelList {
 	if x != nil {
-		return x.Id
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *UpdateAlertRequest) GetWebhookUrl() string {
+	if x != nil && x.WebhookUrl != nil {
+		return *x.WebhookUrl
 	}
 	return ""
 }
 
-func (x *UpdateAlertRequest) GetSymbol() string {
-	if x != nil && x.Symbol != nil {
-		return *x.Symbol
+func (x *UpdateAlertRequest) GetCondition() *Condition {
+	if x != nil {
+		return x.Condition
+	}
+	return nil
+}
+
+func (x *UpdateAlertRequest) GetExpression() string {
+	if x != nil && x.Expression != nil {
+		return *x.Expression
 	}
 	return ""
 }
 
-func (x *UpdateAlertRequest) GetComparator() Comparator {
-	if x != nil && x.Comparator != nil {
-		return *x.Comparator
+func (x *UpdateAlertRequest) GetIndicator() *Indicator {
+	if x != nil {
+		return x.Indicator
 	}
-	return Comparator_COMPARATOR_UNSPECIFIED
+	return nil
 }
 
-func (x *UpdateAlertRequest) GetThreshold() float64 {
-	if x != nil && x.Threshold != nil {
-		return *x.Threshold
+func (x *UpdateAlertRequest) GetCooldown() *durationpb.Duration {
+	if x != nil {
+		return x.Cooldown
 	}
-	return 0
+	return nil
 }
 
-func (x *UpdateAlertRequest) GetNote() string {
-	if x != nil && x.Note != nil {
-		return *x.Note
+func (x *UpdateAlertRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
 	}
-	return ""
+	return nil
 }
 
-func (x *UpdateAlertRequest) GetEnabled() bool {
-	if x != nil && x.Enabled != nil {
-		return *x.Enabled
+func (x *UpdateAlertRequest) GetFireOnce() bool {
+	if x != nil && x.FireOnce != nil {
+		return *x.FireOnce
 	}
 	return false
 }
 
+func (x *UpdateAlertRequest) GetMaxFires() int32 {
+	if x != nil && x.MaxFires != nil {
+		return *x.MaxFires
+	}
+	return 0
+}
+
 // Update alert response
 type UpdateAlertResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Alert         *Alert                 `protobuf:"bytes,1,opt,name=alert,proto3" json:"alert,omitempty"`
+	WebhookSecret string                 `protobuf:"bytes,2,opt,name=webhook_secret,json=webhookSecret,proto3" json:"webhook_secret,omitempty"` // Set when the update gave the alert its first webhook; only returned here
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateAlertResponse) Reset() {
 	*x = UpdateAlertResponse{}
-	mi := &file_api_cryptoalert_proto_msgTypes[8]
+	mi := &file_api_cryptoalert_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -583,7 +2232,7 @@ func (x *UpdateAlertResponse) String() string {
 func (*UpdateAlertResponse) ProtoMessage() {}
 
 func (x *UpdateAlertResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_cryptoalert_proto_msgTypes[8]
+	mi := &file_api_cryptoalert_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -596,7 +2245,7 @@ func (x *UpdateAlertResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateAlertResponse.ProtoReflect.Descriptor instead.
 func (*UpdateAlertResponse) Descriptor() ([]byte, []int) {
-	return file_api_cryptoalert_proto_rawDescGZIP(), []int{8}
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *UpdateAlertResponse) GetAlert() *Alert {
@@ -606,6 +2255,13 @@ func (x *UpdateAlertResponse) GetAlert() *Alert {
 	return nil
 }
 
+func (x *UpdateAlertResponse) GetWebhookSecret() string {
+	if x != nil {
+		return x.WebhookSecret
+	}
+	return ""
+}
+
 // Delete alert request
 type DeleteAlertRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -616,7 +2272,7 @@ type DeleteAlertRequest struct {
 
 func (x *DeleteAlertRequest) Reset() {
 	*x = DeleteAlertRequest{}
-	mi := &file_api_cryptoalert_proto_msgTypes[9]
+	mi := &file_api_cryptoalert_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -628,7 +2284,7 @@ func (x *DeleteAlertRequest) String() string {
 func (*DeleteAlertRequest) ProtoMessage() {}
 
 func (x *DeleteAlertRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_cryptoalert_proto_msgTypes[9]
+	mi := &file_api_cryptoalert_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -641,7 +2297,7 @@ func (x *DeleteAlertRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteAlertRequest.ProtoReflect.Descriptor instead.
 func (*DeleteAlertRequest) Descriptor() ([]byte, []int) {
-	return file_api_cryptoalert_proto_rawDescGZIP(), []int{9}
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *DeleteAlertRequest) GetId() string {
@@ -661,7 +2317,7 @@ type DeleteAlertResponse struct {
 
 func (x *DeleteAlertResponse) Reset() {
 	*x = DeleteAlertResponse{}
-	mi := &file_api_cryptoalert_proto_msgTypes[10]
+	mi := &file_api_cryptoalert_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -673,7 +2329,7 @@ func (x *DeleteAlertResponse) String() string {
 func (*DeleteAlertResponse) ProtoMessage() {}
 
 func (x *DeleteAlertResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_cryptoalert_proto_msgTypes[10]
+	mi := &file_api_cryptoalert_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -686,7 +2342,7 @@ func (x *DeleteAlertResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteAlertResponse.ProtoReflect.Descriptor instead.
 func (*DeleteAlertResponse) Descriptor() ([]byte, []int) {
-	return file_api_cryptoalert_proto_rawDescGZIP(), []int{10}
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *DeleteAlertResponse) GetSuccess() bool {
@@ -698,14 +2354,22 @@ func (x *DeleteAlertResponse) GetSuccess() bool {
 
 // Alert subscription request
 type AlertSubscriptionRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Filters are combined with AND; an empty filter matches every trigger.
+	Symbols     []string `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`                                                       // Only triggers for these symbols
+	AlertIds    []string `protobuf:"bytes,2,rep,name=alert_ids,json=alertIds,proto3" json:"alert_ids,omitempty"`                                     // Only triggers for these alerts
+	Labels      []string `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty"`                                                         // Only alerts carrying at least one of these labels
+	MinSeverity Severity `protobuf:"varint,4,opt,name=min_severity,json=minSeverity,proto3,enum=cryptoalert.Severity" json:"min_severity,omitempty"` // Only alerts at or above this severity
+	// Last sequence number the client received. Retained triggers after it
+	// are replayed before live triggers; unset streams live triggers only.
+	ResumeFromSequence *uint64 `protobuf:"varint,5,opt,name=resume_from_sequence,json=resumeFromSequence,proto3,oneof" json:"resume_from_sequence,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *AlertSubscriptionRequest) Reset() {
 	*x = AlertSubscriptionRequest{}
-	mi := &file_api_cryptoalert_proto_msgTypes[11]
+	mi := &file_api_cryptoalert_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -717,7 +2381,7 @@ func (x *AlertSubscriptionRequest) String() string {
 func (*AlertSubscriptionRequest) ProtoMessage() {}
 
 func (x *AlertSubscriptionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_cryptoalert_proto_msgTypes[11]
+	mi := &file_api_cryptoalert_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -730,22 +2394,59 @@ func (x *AlertSubscriptionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AlertSubscriptionRequest.ProtoReflect.Descriptor instead.
 func (*AlertSubscriptionRequest) Descriptor() ([]byte, []int) {
-	return file_api_cryptoalert_proto_rawDescGZIP(), []int{11}
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *AlertSubscriptionRequest) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *AlertSubscriptionRequest) GetAlertIds() []string {
+	if x != nil {
+		return x.AlertIds
+	}
+	return nil
+}
+
+func (x *AlertSubscriptionRequest) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *AlertSubscriptionRequest) GetMinSeverity() Severity {
+	if x != nil {
+		return x.MinSeverity
+	}
+	return Severity_SEVERITY_UNSPECIFIED
+}
+
+func (x *AlertSubscriptionRequest) GetResumeFromSequence() uint64 {
+	if x != nil && x.ResumeFromSequence != nil {
+		return *x.ResumeFromSequence
+	}
+	return 0
 }
 
 // Alert trigger notification
 type AlertTrigger struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	Alert          *Alert                 `protobuf:"bytes,1,opt,name=alert,proto3" json:"alert,omitempty"`
-	TriggeredPrice float64                `protobuf:"fixed64,2,opt,name=triggered_price,json=triggeredPrice,proto3" json:"triggered_price,omitempty"`
+	TriggeredPrice float64                `protobuf:"fixed64,2,opt,name=triggered_price,json=triggeredPrice,proto3" json:"triggered_price,omitempty"` // Last known price for system events
 	Timestamp      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Sequence       uint64                 `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"` // Monotonically increasing across all triggers
+	Event          TriggerEvent           `protobuf:"varint,5,opt,name=event,proto3,enum=cryptoalert.TriggerEvent" json:"event,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
 func (x *AlertTrigger) Reset() {
 	*x = AlertTrigger{}
-	mi := &file_api_cryptoalert_proto_msgTypes[12]
+	mi := &file_api_cryptoalert_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -757,7 +2458,7 @@ func (x *AlertTrigger) String() string {
 func (*AlertTrigger) ProtoMessage() {}
 
 func (x *AlertTrigger) ProtoReflect() protoreflect.Message {
-	mi := &file_api_cryptoalert_proto_msgTypes[12]
+	mi := &file_api_cryptoalert_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -770,7 +2471,7 @@ func (x *AlertTrigger) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AlertTrigger.ProtoReflect.Descriptor instead.
 func (*AlertTrigger) Descriptor() ([]byte, []int) {
-	return file_api_cryptoalert_proto_rawDescGZIP(), []int{12}
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *AlertTrigger) GetAlert() *Alert {
@@ -794,17 +2495,297 @@ func (x *AlertTrigger) GetTimestamp() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *AlertTrigger) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *AlertTrigger) GetEvent() TriggerEvent {
+	if x != nil {
+		return x.Event
+	}
+	return TriggerEvent_TRIGGER_EVENT_ALERT
+}
+
+// List triggers request
+type ListTriggersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AlertId       string                 `protobuf:"bytes,1,opt,name=alert_id,json=alertId,proto3" json:"alert_id,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`                            // Inclusive; unset for the oldest retained trigger
+	To            *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`                                // Exclusive; unset for now
+	PageSize      uint32                 `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // 0 for 50; at most 1000
+	PageToken     string                 `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token from the previous page
+	OwnerId       string                 `protobuf:"bytes,7,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`       // Admins only: list one owner's triggers; empty lists all
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTriggersRequest) Reset() {
+	*x = ListTriggersRequest{}
+	mi := &file_api_cryptoalert_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTriggersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTriggersRequest) ProtoMessage() {}
+
+func (x *ListTriggersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_cryptoalert_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTriggersRequest.ProtoReflect.Descriptor instead.
+func (*ListTriggersRequest) Descriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListTriggersRequest) GetAlertId() string {
+	if x != nil {
+		return x.AlertId
+	}
+	return ""
+}
+
+func (x *ListTriggersRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *ListTriggersRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListTriggersRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ListTriggersRequest) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListTriggersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListTriggersRequest) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+// A past trigger
+type TriggerRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Trigger       *AlertTrigger          `protobuf:"bytes,1,opt,name=trigger,proto3" json:"trigger,omitempty"`     // The alert as it was when it fired
+	Condition     string                 `protobuf:"bytes,2,opt,name=condition,proto3" json:"condition,omitempty"` // The alert's rule when it fired, e.g. "BTC > 100000.00"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerRecord) Reset() {
+	*x = TriggerRecord{}
+	mi := &file_api_cryptoalert_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerRecord) ProtoMessage() {}
+
+func (x *TriggerRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_api_cryptoalert_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerRecord.ProtoReflect.Descriptor instead.
+func (*TriggerRecord) Descriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *TriggerRecord) GetTrigger() *AlertTrigger {
+	if x != nil {
+		return x.Trigger
+	}
+	return nil
+}
+
+func (x *TriggerRecord) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+// List triggers response
+type ListTriggersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Triggers      []*TriggerRecord       `protobuf:"bytes,1,rep,name=triggers,proto3" json:"triggers,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTriggersResponse) Reset() {
+	*x = ListTriggersResponse{}
+	mi := &file_api_cryptoalert_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTriggersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTriggersResponse) ProtoMessage() {}
+
+func (x *ListTriggersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_cryptoalert_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTriggersResponse.ProtoReflect.Descriptor instead.
+func (*ListTriggersResponse) Descriptor() ([]byte, []int) {
+	return file_api_cryptoalert_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListTriggersResponse) GetTriggers() []*TriggerRecord {
+	if x != nil {
+		return x.Triggers
+	}
+	return nil
+}
+
+func (x *ListTriggersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 var File_api_cryptoalert_proto protoreflect.FileDescriptor
 
 const file_api_cryptoalert_proto_rawDesc = "" +
 	"\n" +
-	"\x15api/cryptoalert.proto\x12\vcryptoalert\x1a\x1fgoogle/protobuf/timestamp.proto\"4\n" +
+	"\x15api/cryptoalert.proto\x12\vcryptoalert\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"L\n" +
 	"\x18PriceSubscriptionRequest\x12\x18\n" +
-	"\asymbols\x18\x01 \x03(\tR\asymbols\"s\n" +
+	"\asymbols\x18\x01 \x03(\tR\asymbols\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\"\x8b\x01\n" +
 	"\tPriceTick\x12\x16\n" +
 	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x14\n" +
 	"\x05price\x18\x02 \x01(\x01R\x05price\x128\n" +
-	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xf3\x01\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\"\x84\x03\n" +
+	"\x06Candle\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x16\n" +
+	"\x06source\x18\x02 \x01(\tR\x06source\x127\n" +
+	"\binterval\x18\x03 \x01(\x0e2\x1b.cryptoalert.CandleIntervalR\binterval\x127\n" +
+	"\topen_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bopenTime\x129\n" +
+	"\n" +
+	"close_time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcloseTime\x12\x12\n" +
+	"\x04open\x18\x06 \x01(\x01R\x04open\x12\x12\n" +
+	"\x04high\x18\a \x01(\x01R\x04high\x12\x10\n" +
+	"\x03low\x18\b \x01(\x01R\x03low\x12\x14\n" +
+	"\x05close\x18\t \x01(\x01R\x05close\x12\x16\n" +
+	"\x06volume\x18\n" +
+	" \x01(\x01R\x06volume\x12\x1d\n" +
+	"\n" +
+	"tick_count\x18\v \x01(\rR\ttickCount\x12\x16\n" +
+	"\x06closed\x18\f \x01(\bR\x06closed\"\x86\x01\n" +
+	"\x19CandleSubscriptionRequest\x12\x18\n" +
+	"\asymbols\x18\x01 \x03(\tR\asymbols\x127\n" +
+	"\binterval\x18\x02 \x01(\x0e2\x1b.cryptoalert.CandleIntervalR\binterval\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\"\xbb\x01\n" +
+	"\x11GetCandlesRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x127\n" +
+	"\binterval\x18\x02 \x01(\x0e2\x1b.cryptoalert.CandleIntervalR\binterval\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\rR\x05limit\x12'\n" +
+	"\x0finclude_partial\x18\x05 \x01(\bR\x0eincludePartial\"C\n" +
+	"\x12GetCandlesResponse\x12-\n" +
+	"\acandles\x18\x01 \x03(\v2\x13.cryptoalert.CandleR\acandles\"\xcc\x01\n" +
+	"\x16GetPriceHistoryRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12>\n" +
+	"\n" +
+	"resolution\x18\x04 \x01(\x0e2\x1e.cryptoalert.HistoryResolutionR\n" +
+	"resolution\"\xcd\x01\n" +
+	"\n" +
+	"PricePoint\x128\n" +
+	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x12\n" +
+	"\x04open\x18\x02 \x01(\x01R\x04open\x12\x12\n" +
+	"\x04high\x18\x03 \x01(\x01R\x04high\x12\x10\n" +
+	"\x03low\x18\x04 \x01(\x01R\x03low\x12\x14\n" +
+	"\x05close\x18\x05 \x01(\x01R\x05close\x12\x16\n" +
+	"\x06volume\x18\x06 \x01(\x01R\x06volume\x12\x1d\n" +
+	"\n" +
+	"tick_count\x18\a \x01(\rR\ttickCount\"\x8a\x01\n" +
+	"\x17GetPriceHistoryResponse\x12/\n" +
+	"\x06points\x18\x01 \x03(\v2\x17.cryptoalert.PricePointR\x06points\x12>\n" +
+	"\n" +
+	"resolution\x18\x02 \x01(\x0e2\x1e.cryptoalert.HistoryResolutionR\n" +
+	"resolution\"\xcc\x01\n" +
+	"\tIndicator\x124\n" +
+	"\aaverage\x18\x01 \x01(\x0e2\x1a.cryptoalert.MovingAverageR\aaverage\x12\x16\n" +
+	"\x06period\x18\x02 \x01(\rR\x06period\x12\x1f\n" +
+	"\vslow_period\x18\x03 \x01(\rR\n" +
+	"slowPeriod\x12\x19\n" +
+	"\bstd_devs\x18\x04 \x01(\x01R\astdDevs\x125\n" +
+	"\binterval\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\binterval\"\xd8\x01\n" +
+	"\tCondition\x12(\n" +
+	"\x02op\x18\x01 \x01(\x0e2\x18.cryptoalert.ConditionOpR\x02op\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x127\n" +
+	"\n" +
+	"comparator\x18\x03 \x01(\x0e2\x17.cryptoalert.ComparatorR\n" +
+	"comparator\x12\x1c\n" +
+	"\tthreshold\x18\x04 \x01(\x01R\tthreshold\x122\n" +
+	"\bchildren\x18\x05 \x03(\v2\x16.cryptoalert.ConditionR\bchildren\"\xb0\a\n" +
 	"\x05Alert\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
 	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x127\n" +
@@ -814,19 +2795,67 @@ const file_api_cryptoalert_proto_rawDesc = "" +
 	"\tthreshold\x18\x04 \x01(\x01R\tthreshold\x12\x12\n" +
 	"\x04note\x18\x05 \x01(\tR\x04note\x12\x18\n" +
 	"\aenabled\x18\x06 \x01(\bR\aenabled\x12=\n" +
-	"\flast_trigger\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vlastTrigger\"\x97\x01\n" +
+	"\flast_trigger\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\vlastTrigger\x12*\n" +
+	"\x04kind\x18\b \x01(\x0e2\x16.cryptoalert.AlertKindR\x04kind\x128\n" +
+	"\tdirection\x18\t \x01(\x0e2\x1a.cryptoalert.MoveDirectionR\tdirection\x121\n" +
+	"\x06window\x18\n" +
+	" \x01(\v2\x19.google.protobuf.DurationR\x06window\x12,\n" +
+	"\x04mode\x18\v \x01(\x0e2\x18.cryptoalert.TriggerModeR\x04mode\x12\x16\n" +
+	"\x06source\x18\f \x01(\tR\x06source\x12\x16\n" +
+	"\x06labels\x18\r \x03(\tR\x06labels\x121\n" +
+	"\bseverity\x18\x0e \x01(\x0e2\x15.cryptoalert.SeverityR\bseverity\x12\x1f\n" +
+	"\vwebhook_url\x18\x0f \x01(\tR\n" +
+	"webhookUrl\x12\x19\n" +
+	"\bowner_id\x18\x10 \x01(\tR\aownerId\x124\n" +
+	"\tcondition\x18\x11 \x01(\v2\x16.cryptoalert.ConditionR\tcondition\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x12 \x01(\tR\n" +
+	"expression\x124\n" +
+	"\tindicator\x18\x13 \x01(\v2\x16.cryptoalert.IndicatorR\tindicator\x125\n" +
+	"\bcooldown\x18\x14 \x01(\v2\x19.google.protobuf.DurationR\bcooldown\x129\n" +
+	"\n" +
+	"expires_at\x18\x15 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x1b\n" +
+	"\tfire_once\x18\x16 \x01(\bR\bfireOnce\x12\x1b\n" +
+	"\tmax_fires\x18\x17 \x01(\x05R\bmaxFires\x12\x1d\n" +
+	"\n" +
+	"fire_count\x18\x18 \x01(\x05R\tfireCount\"#\n" +
+	"\tLabelList\x12\x16\n" +
+	"\x06labels\x18\x01 \x03(\tR\x06labels\"\xb5\x06\n" +
 	"\x12CreateAlertRequest\x12\x16\n" +
 	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x127\n" +
 	"\n" +
 	"comparator\x18\x02 \x01(\x0e2\x17.cryptoalert.ComparatorR\n" +
 	"comparator\x12\x1c\n" +
 	"\tthreshold\x18\x03 \x01(\x01R\tthreshold\x12\x12\n" +
-	"\x04note\x18\x04 \x01(\tR\x04note\"?\n" +
+	"\x04note\x18\x04 \x01(\tR\x04note\x12*\n" +
+	"\x04kind\x18\x05 \x01(\x0e2\x16.cryptoalert.AlertKindR\x04kind\x128\n" +
+	"\tdirection\x18\x06 \x01(\x0e2\x1a.cryptoalert.MoveDirectionR\tdirection\x121\n" +
+	"\x06window\x18\a \x01(\v2\x19.google.protobuf.DurationR\x06window\x12,\n" +
+	"\x04mode\x18\b \x01(\x0e2\x18.cryptoalert.TriggerModeR\x04mode\x12\x16\n" +
+	"\x06source\x18\t \x01(\tR\x06source\x12\x16\n" +
+	"\x06labels\x18\n" +
+	" \x03(\tR\x06labels\x121\n" +
+	"\bseverity\x18\v \x01(\x0e2\x15.cryptoalert.SeverityR\bseverity\x12\x1f\n" +
+	"\vwebhook_url\x18\f \x01(\tR\n" +
+	"webhookUrl\x12\x19\n" +
+	"\bowner_id\x18\r \x01(\tR\aownerId\x124\n" +
+	"\tcondition\x18\x0e \x01(\v2\x16.cryptoalert.ConditionR\tcondition\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x0f \x01(\tR\n" +
+	"expression\x124\n" +
+	"\tindicator\x18\x10 \x01(\v2\x16.cryptoalert.IndicatorR\tindicator\x125\n" +
+	"\bcooldown\x18\x11 \x01(\v2\x19.google.protobuf.DurationR\bcooldown\x129\n" +
+	"\n" +
+	"expires_at\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x1b\n" +
+	"\tfire_once\x18\x13 \x01(\bR\bfireOnce\x12\x1b\n" +
+	"\tmax_fires\x18\x14 \x01(\x05R\bmaxFires\"f\n" +
 	"\x13CreateAlertResponse\x12(\n" +
-	"\x05alert\x18\x01 \x01(\v2\x12.cryptoalert.AlertR\x05alert\"\x12\n" +
-	"\x10GetAlertsRequest\"?\n" +
+	"\x05alert\x18\x01 \x01(\v2\x12.cryptoalert.AlertR\x05alert\x12%\n" +
+	"\x0ewebhook_secret\x18\x02 \x01(\tR\rwebhookSecret\"-\n" +
+	"\x10GetAlertsRequest\x12\x19\n" +
+	"\bowner_id\x18\x01 \x01(\tR\aownerId\"?\n" +
 	"\x11GetAlertsResponse\x12*\n" +
-	"\x06alerts\x18\x01 \x03(\v2\x12.cryptoalert.AlertR\x06alerts\"\x97\x02\n" +
+	"\x06alerts\x18\x01 \x03(\v2\x12.cryptoalert.AlertR\x06alerts\"\x98\b\n" +
 	"\x12UpdateAlertRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
 	"\x06symbol\x18\x02 \x01(\tH\x00R\x06symbol\x88\x01\x01\x12<\n" +
@@ -835,25 +2864,95 @@ const file_api_cryptoalert_proto_rawDesc = "" +
 	"comparator\x88\x01\x01\x12!\n" +
 	"\tthreshold\x18\x04 \x01(\x01H\x02R\tthreshold\x88\x01\x01\x12\x17\n" +
 	"\x04note\x18\x05 \x01(\tH\x03R\x04note\x88\x01\x01\x12\x1d\n" +
-	"\aenabled\x18\x06 \x01(\bH\x04R\aenabled\x88\x01\x01B\t\n" +
+	"\aenabled\x18\x06 \x01(\bH\x04R\aenabled\x88\x01\x01\x12=\n" +
+	"\tdirection\x18\a \x01(\x0e2\x1a.cryptoalert.MoveDirectionH\x05R\tdirection\x88\x01\x01\x121\n" +
+	"\x06window\x18\b \x01(\v2\x19.google.protobuf.DurationR\x06window\x121\n" +
+	"\x04mode\x18\t \x01(\x0e2\x18.cryptoalert.TriggerModeH\x06R\x04mode\x88\x01\x01\x12\x1b\n" +
+	"\x06source\x18\n" +
+	" \x01(\tH\aR\x06source\x88\x01\x01\x126\n" +
+	"\bseverity\x18\v \x01(\x0e2\x15.cryptoalert.SeverityH\bR\bseverity\x88\x01\x01\x12.\n" +
+	"\x06labels\x18\f \x01(\v2\x16.cryptoalert.LabelListR\x06labels\x12$\n" +
+	"\vwebhook_url\x18\r \x01(\tH\tR\n" +
+	"webhookUrl\x88\x01\x01\x124\n" +
+	"\tcondition\x18\x0e \x01(\v2\x16.cryptoalert.ConditionR\tcondition\x12#\n" +
+	"\n" +
+	"expression\x18\x0f \x01(\tH\n" +
+	"R\n" +
+	"expression\x88\x01\x01\x124\n" +
+	"\tindicator\x18\x10 \x01(\v2\x16.cryptoalert.IndicatorR\tindicator\x125\n" +
+	"\bcooldown\x18\x11 \x01(\v2\x19.google.protobuf.DurationR\bcooldown\x129\n" +
+	"\n" +
+	"expires_at\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12 \n" +
+	"\tfire_once\x18\x13 \x01(\bH\vR\bfireOnce\x88\x01\x01\x12 \n" +
+	"\tmax_fires\x18\x14 \x01(\x05H\fR\bmaxFires\x88\x01\x01B\t\n" +
 	"\a_symbolB\r\n" +
 	"\v_comparatorB\f\n" +
 	"\n" +
 	"_thresholdB\a\n" +
 	"\x05_noteB\n" +
 	"\n" +
-	"\b_enabled\"?\n" +
+	"\b_enabledB\f\n" +
+	"\n" +
+	"_directionB\a\n" +
+	"\x05_modeB\t\n" +
+	"\a_sourceB\v\n" +
+	"\t_severityB\x0e\n" +
+	"\f_webhook_urlB\r\n" +
+	"\v_expressionB\f\n" +
+	"\n" +
+	"_fire_onceB\f\n" +
+	"\n" +
+	"_max_fires\"f\n" +
 	"\x13UpdateAlertResponse\x12(\n" +
-	"\x05alert\x18\x01 \x01(\v2\x12.cryptoalert.AlertR\x05alert\"$\n" +
+	"\x05alert\x18\x01 \x01(\v2\x12.cryptoalert.AlertR\x05alert\x12%\n" +
+	"\x0ewebhook_secret\x18\x02 \x01(\tR\rwebhookSecret\"$\n" +
 	"\x12DeleteAlertRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\"/\n" +
 	"\x13DeleteAlertResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x1a\n" +
-	"\x18AlertSubscriptionRequest\"\x9b\x01\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xf3\x01\n" +
+	"\x18AlertSubscriptionRequest\x12\x18\n" +
+	"\asymbols\x18\x01 \x03(\tR\asymbols\x12\x1b\n" +
+	"\talert_ids\x18\x02 \x03(\tR\balertIds\x12\x16\n" +
+	"\x06labels\x18\x03 \x03(\tR\x06labels\x128\n" +
+	"\fmin_severity\x18\x04 \x01(\x0e2\x15.cryptoalert.SeverityR\vminSeverity\x125\n" +
+	"\x14resume_from_sequence\x18\x05 \x01(\x04H\x00R\x12resumeFromSequence\x88\x01\x01B\x17\n" +
+	"\x15_resume_from_sequence\"\xe8\x01\n" +
 	"\fAlertTrigger\x12(\n" +
 	"\x05alert\x18\x01 \x01(\v2\x12.cryptoalert.AlertR\x05alert\x12'\n" +
 	"\x0ftriggered_price\x18\x02 \x01(\x01R\x0etriggeredPrice\x128\n" +
-	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp*\x89\x01\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x04R\bsequence\x12/\n" +
+	"\x05event\x18\x05 \x01(\x0e2\x19.cryptoalert.TriggerEventR\x05event\"\xfb\x01\n" +
+	"\x13ListTriggersRequest\x12\x19\n" +
+	"\balert_id\x18\x01 \x01(\tR\aalertId\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12.\n" +
+	"\x04from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\rR\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x06 \x01(\tR\tpageToken\x12\x19\n" +
+	"\bowner_id\x18\a \x01(\tR\aownerId\"b\n" +
+	"\rTriggerRecord\x123\n" +
+	"\atrigger\x18\x01 \x01(\v2\x19.cryptoalert.AlertTriggerR\atrigger\x12\x1c\n" +
+	"\tcondition\x18\x02 \x01(\tR\tcondition\"v\n" +
+	"\x14ListTriggersResponse\x126\n" +
+	"\btriggers\x18\x01 \x03(\v2\x1a.cryptoalert.TriggerRecordR\btriggers\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken*\xaa\x01\n" +
+	"\x0eCandleInterval\x12\x1f\n" +
+	"\x1bCANDLE_INTERVAL_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12CANDLE_INTERVAL_1M\x10\x01\x12\x16\n" +
+	"\x12CANDLE_INTERVAL_5M\x10\x02\x12\x17\n" +
+	"\x13CANDLE_INTERVAL_15M\x10\x03\x12\x16\n" +
+	"\x12CANDLE_INTERVAL_1H\x10\x04\x12\x16\n" +
+	"\x12CANDLE_INTERVAL_1D\x10\x05*\xdb\x01\n" +
+	"\x11HistoryResolution\x12\"\n" +
+	"\x1eHISTORY_RESOLUTION_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16HISTORY_RESOLUTION_RAW\x10\x01\x12\x19\n" +
+	"\x15HISTORY_RESOLUTION_1M\x10\x02\x12\x19\n" +
+	"\x15HISTORY_RESOLUTION_5M\x10\x03\x12\x1a\n" +
+	"\x16HISTORY_RESOLUTION_15M\x10\x04\x12\x19\n" +
+	"\x15HISTORY_RESOLUTION_1H\x10\x05\x12\x19\n" +
+	"\x15HISTORY_RESOLUTION_1D\x10\x06*\x89\x01\n" +
 	"\n" +
 	"Comparator\x12\x1a\n" +
 	"\x16COMPARATOR_UNSPECIFIED\x10\x00\x12\x11\n" +
@@ -861,15 +2960,60 @@ const file_api_cryptoalert_proto_rawDesc = "" +
 	"\x0eCOMPARATOR_GTE\x10\x02\x12\x11\n" +
 	"\rCOMPARATOR_LT\x10\x03\x12\x12\n" +
 	"\x0eCOMPARATOR_LTE\x10\x04\x12\x11\n" +
-	"\rCOMPARATOR_EQ\x10\x052f\n" +
+	"\rCOMPARATOR_EQ\x10\x05*\x97\x01\n" +
+	"\vTriggerMode\x12\x1c\n" +
+	"\x18TRIGGER_MODE_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12TRIGGER_MODE_LEVEL\x10\x01\x12\x19\n" +
+	"\x15TRIGGER_MODE_CROSS_UP\x10\x02\x12\x1b\n" +
+	"\x17TRIGGER_MODE_CROSS_DOWN\x10\x03\x12\x1a\n" +
+	"\x16TRIGGER_MODE_CROSS_ANY\x10\x04*d\n" +
+	"\bSeverity\x12\x18\n" +
+	"\x14SEVERITY_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rSEVERITY_INFO\x10\x01\x12\x14\n" +
+	"\x10SEVERITY_WARNING\x10\x02\x12\x15\n" +
+	"\x11SEVERITY_CRITICAL\x10\x03*\xfa\x01\n" +
+	"\tAlertKind\x12\x1a\n" +
+	"\x16ALERT_KIND_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14ALERT_KIND_THRESHOLD\x10\x01\x12\x1b\n" +
+	"\x17ALERT_KIND_PERCENT_MOVE\x10\x02\x12\x1b\n" +
+	"\x17ALERT_KIND_FEED_SILENCE\x10\x03\x12\x17\n" +
+	"\x13ALERT_KIND_COMPOUND\x10\x04\x12\x19\n" +
+	"\x15ALERT_KIND_EXPRESSION\x10\x05\x12\x1b\n" +
+	"\x17ALERT_KIND_MA_CROSSOVER\x10\x06\x12\x12\n" +
+	"\x0eALERT_KIND_RSI\x10\a\x12\x18\n" +
+	"\x14ALERT_KIND_BOLLINGER\x10\b*_\n" +
+	"\rMovingAverage\x12\x1e\n" +
+	"\x1aMOVING_AVERAGE_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12MOVING_AVERAGE_SMA\x10\x01\x12\x16\n" +
+	"\x12MOVING_AVERAGE_EMA\x10\x02*\x86\x01\n" +
+	"\vConditionOp\x12\x1c\n" +
+	"\x18CONDITION_OP_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14CONDITION_OP_COMPARE\x10\x01\x12\x14\n" +
+	"\x10CONDITION_OP_AND\x10\x02\x12\x13\n" +
+	"\x0fCONDITION_OP_OR\x10\x03\x12\x14\n" +
+	"\x10CONDITION_OP_NOT\x10\x04*z\n" +
+	"\rMoveDirection\x12\x1e\n" +
+	"\x1aMOVE_DIRECTION_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15MOVE_DIRECTION_EITHER\x10\x01\x12\x15\n" +
+	"\x11MOVE_DIRECTION_UP\x10\x02\x12\x17\n" +
+	"\x13MOVE_DIRECTION_DOWN\x10\x03*g\n" +
+	"\fTriggerEvent\x12\x17\n" +
+	"\x13TRIGGER_EVENT_ALERT\x10\x00\x12\x1c\n" +
+	"\x18TRIGGER_EVENT_FEED_STALE\x10\x01\x12 \n" +
+	"\x1cTRIGGER_EVENT_FEED_RECOVERED\x10\x022\xe6\x02\n" +
 	"\x10CryptoMarketData\x12R\n" +
-	"\x0fSubscribePrices\x12%.cryptoalert.PriceSubscriptionRequest\x1a\x16.cryptoalert.PriceTick0\x012\xad\x03\n" +
+	"\x0fSubscribePrices\x12%.cryptoalert.PriceSubscriptionRequest\x1a\x16.cryptoalert.PriceTick0\x01\x12Q\n" +
+	"\x10SubscribeCandles\x12&.cryptoalert.CandleSubscriptionRequest\x1a\x13.cryptoalert.Candle0\x01\x12M\n" +
+	"\n" +
+	"GetCandles\x12\x1e.cryptoalert.GetCandlesRequest\x1a\x1f.cryptoalert.GetCandlesResponse\x12\\\n" +
+	"\x0fGetPriceHistory\x12#.cryptoalert.GetPriceHistoryRequest\x1a$.cryptoalert.GetPriceHistoryResponse2\x82\x04\n" +
 	"\x12CryptoAlertService\x12P\n" +
 	"\vCreateAlert\x12\x1f.cryptoalert.CreateAlertRequest\x1a .cryptoalert.CreateAlertResponse\x12J\n" +
 	"\tGetAlerts\x12\x1d.cryptoalert.GetAlertsRequest\x1a\x1e.cryptoalert.GetAlertsResponse\x12P\n" +
 	"\vUpdateAlert\x12\x1f.cryptoalert.UpdateAlertRequest\x1a .cryptoalert.UpdateAlertResponse\x12P\n" +
 	"\vDeleteAlert\x12\x1f.cryptoalert.DeleteAlertRequest\x1a .cryptoalert.DeleteAlertResponse\x12U\n" +
-	"\x0fSubscribeAlerts\x12%.cryptoalert.AlertSubscriptionRequest\x1a\x19.cryptoalert.AlertTrigger0\x01B\x1dZ\x1bcrypto-price-alerts/api/genb\x06proto3"
+	"\x0fSubscribeAlerts\x12%.cryptoalert.AlertSubscriptionRequest\x1a\x19.cryptoalert.AlertTrigger0\x01\x12S\n" +
+	"\fListTriggers\x12 .cryptoalert.ListTriggersRequest\x1a!.cryptoalert.ListTriggersResponseB\x1dZ\x1bcrypto-price-alerts/api/genb\x06proto3"
 
 var (
 	file_api_cryptoalert_proto_rawDescOnce sync.Once
@@ -883,53 +3027,134 @@ func file_api_cryptoalert_proto_rawDescGZIP() []byte {
 	return file_api_cryptoalert_proto_rawDescData
 }
 
-var file_api_cryptoalert_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_api_cryptoalert_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_api_cryptoalert_proto_enumTypes = make([]protoimpl.EnumInfo, 10)
+var file_api_cryptoalert_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
 var file_api_cryptoalert_proto_goTypes = []any{
-	(Comparator)(0),                  // 0: cryptoalert.Comparator
-	(*PriceSubscriptionRequest)(nil), // 1: cryptoalert.PriceSubscriptionRequest
-	(*PriceTick)(nil),                // 2: cryptoalert.PriceTick
-	(*Alert)(nil),                    // 3: cryptoalert.Alert
-	(*CreateAlertRequest)(nil),       // 4: cryptoalert.CreateAlertRequest
-	(*CreateAlertResponse)(nil),      // 5: cryptoalert.CreateAlertResponse
-	(*GetAlertsRequest)(nil),         // 6: cryptoalert.GetAlertsRequest
-	(*GetAlertsResponse)(nil),        // 7: cryptoalert.GetAlertsResponse
-	(*UpdateAlertRequest)(nil),       // 8: cryptoalert.UpdateAlertRequest
-	(*UpdateAlertResponse)(nil),      // 9: cryptoalert.UpdateAlertResponse
-	(*DeleteAlertRequest)(nil),       // 10: cryptoalert.DeleteAlertRequest
-	(*DeleteAlertResponse)(nil),      // 11: cryptoalert.DeleteAlertResponse
-	(*AlertSubscriptionRequest)(nil), // 12: cryptoalert.AlertSubscriptionRequest
-	(*AlertTrigger)(nil),             // 13: cryptoalert.AlertTrigger
-	(*timestamppb.Timestamp)(nil),    // 14: google.protobuf.Timestamp
+	(CandleInterval)(0),               // 0: cryptoalert.CandleInterval
+	(HistoryResolution)(0),            // 1: cryptoalert.HistoryResolution
+	(Comparator)(0),                   // 2: cryptoalert.Comparator
+	(TriggerMode)(0),                  // 3: cryptoalert.TriggerMode
+	(Severity)(0),                     // 4: cryptoalert.Severity
+	(AlertKind)(0),                    // 5: cryptoalert.AlertKind
+	(MovingAverage)(0),                // 6: cryptoalert.MovingAverage
+	(ConditionOp)(0),                  // 7: cryptoalert.ConditionOp
+	(MoveDirection)(0),                // 8: cryptoalert.MoveDirection
+	(TriggerEvent)(0),                 // 9: cryptoalert.TriggerEvent
+	(*PriceSubscriptionRequest)(nil),  // 10: cryptoalert.PriceSubscriptionRequest
+	(*PriceTick)(nil),                 // 11: cryptoalert.PriceTick
+	(*Candle)(nil),                    // 12: cryptoalert.Candle
+	(*CandleSubscriptionRequest)(nil), // 13: cryptoalert.CandleSubscriptionRequest
+	(*GetCandlesRequest)(nil),         // 14: cryptoalert.GetCandlesRequest
+	(*GetCandlesResponse)(nil),        // 15: cryptoalert.GetCandlesResponse
+	(*GetPriceHistoryRequest)(nil),    // 16: cryptoalert.GetPriceHistoryRequest
+	(*PricePoint)(nil),                // 17: cryptoalert.PricePoint
+	(*GetPriceHistoryResponse)(nil),   // 18: cryptoalert.GetPriceHistoryResponse
+	(*Indicator)(nil),                 // 19: cryptoalert.Indicator
+	(*Condition)(nil),                 // 20: cryptoalert.Condition
+	(*Alert)(nil),                     // 21: cryptoalert.Alert
+	(*LabelList)(nil),                 // 22: cryptoalert.LabelList
+	(*CreateAlertRequest)(nil),        // 23: cryptoalert.CreateAlertRequest
+	(*CreateAlertResponse)(nil),       // 24: cryptoalert.CreateAlertResponse
+	(*GetAlertsRequest)(nil),          // 25: cryptoalert.GetAlertsRequest
+	(*GetAlertsResponse)(nil),         // 26: cryptoalert.GetAlertsResponse
+	(*UpdateAlertRequest)(nil),        // 27: cryptoalert.UpdateAlertRequest
+	(*UpdateAlertResponse)(nil),       // 28: cryptoalert.UpdateAlertResponse
+	(*DeleteAlertRequest)(nil),        // 29: cryptoalert.DeleteAlertRequest
+	(*DeleteAlertResponse)(nil),       // 30: cryptoalert.DeleteAlertResponse
+	(*AlertSubscriptionRequest)(nil),  // 31: cryptoalert.AlertSubscriptionRequest
+	(*AlertTrigger)(nil),              // 32: cryptoalert.AlertTrigger
+	(*ListTriggersRequest)(nil),       // 33: cryptoalert.ListTriggersRequest
+	(*TriggerRecord)(nil),             // 34: cryptoalert.TriggerRecord
+	(*ListTriggersResponse)(nil),      // 35: cryptoalert.ListTriggersResponse
+	(*timestamppb.Timestamp)(nil),     // 36: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),       // 37: google.protobuf.Duration
 }
 var file_api_cryptoalert_proto_depIdxs = []int32{
-	14, // 0: cryptoalert.PriceTick.timestamp:type_name -> google.protobuf.Timestamp
-	0,  // 1: cryptoalert.Alert.comparator:type_name -> cryptoalert.Comparator
-	14, // 2: cryptoalert.Alert.last_trigger:type_name -> google.protobuf.Timestamp
-	0,  // 3: cryptoalert.CreateAlertRequest.comparator:type_name -> cryptoalert.Comparator
-	3,  // 4: cryptoalert.CreateAlertResponse.alert:type_name -> cryptoalert.Alert
-	3,  // 5: cryptoalert.GetAlertsResponse.alerts:type_name -> cryptoalert.Alert
-	0,  // 6: cryptoalert.UpdateAlertRequest.comparator:type_name -> cryptoalert.Comparator
-	3,  // 7: cryptoalert.UpdateAlertResponse.alert:type_name -> cryptoalert.Alert
-	3,  // 8: cryptoalert.AlertTrigger.alert:type_name -> cryptoalert.Alert
-	14, // 9: cryptoalert.AlertTrigger.timestamp:type_name -> google.protobuf.Timestamp
-	1,  // 10: cryptoalert.CryptoMarketData.SubscribePrices:input_type -> cryptoalert.PriceSubscriptionRequest
-	4,  // 11: cryptoalert.CryptoAlertService.CreateAlert:input_type -> cryptoalert.CreateAlertRequest
-	6,  // 12: cryptoalert.CryptoAlertService.GetAlerts:input_type -> cryptoalert.GetAlertsRequest
-	8,  // 13: cryptoalert.CryptoAlertService.UpdateAlert:input_type -> cryptoalert.UpdateAlertRequest
-	10, // 14: cryptoalert.CryptoAlertService.DeleteAlert:input_type -> cryptoalert.DeleteAlertRequest
-	12, // 15: cryptoalert.CryptoAlertService.SubscribeAlerts:input_type -> cryptoalert.AlertSubscriptionRequest
-	2,  // 16: cryptoalert.CryptoMarketData.SubscribePrices:output_type -> cryptoalert.PriceTick
-	5,  // 17: cryptoalert.CryptoAlertService.CreateAlert:output_type -> cryptoalert.CreateAlertResponse
-	7,  // 18: cryptoalert.CryptoAlertService.GetAlerts:output_type -> cryptoalert.GetAlertsResponse
-	9,  // 19: cryptoalert.CryptoAlertService.UpdateAlert:output_type -> cryptoalert.UpdateAlertResponse
-	11, // 20: cryptoalert.CryptoAlertService.DeleteAlert:output_type -> cryptoalert.DeleteAlertResponse
-	13, // 21: cryptoalert.CryptoAlertService.SubscribeAlerts:output_type -> cryptoalert.AlertTrigger
-	16, // [16:22] is the sub-list for method output_type
-	10, // [10:16] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	36, // 0: cryptoalert.PriceTick.timestamp:type_name -> google.protobuf.Timestamp
+	0,  // 1: cryptoalert.Candle.interval:type_name -> cryptoalert.CandleInterval
+	36, // 2: cryptoalert.Candle.open_time:type_name -> google.protobuf.Timestamp
+	36, // 3: cryptoalert.Candle.close_time:type_name -> google.protobuf.Timestamp
+	0,  // 4: cryptoalert.CandleSubscriptionRequest.interval:type_name -> cryptoalert.CandleInterval
+	0,  // 5: cryptoalert.GetCandlesRequest.interval:type_name -> cryptoalert.CandleInterval
+	12, // 6: cryptoalert.GetCandlesResponse.candles:type_name -> cryptoalert.Candle
+	36, // 7: cryptoalert.GetPriceHistoryRequest.from:type_name -> google.protobuf.Timestamp
+	36, // 8: cryptoalert.GetPriceHistoryRequest.to:type_name -> google.protobuf.Timestamp
+	1,  // 9: cryptoalert.GetPriceHistoryRequest.resolution:type_name -> cryptoalert.HistoryResolution
+	36, // 10: cryptoalert.PricePoint.timestamp:type_name -> google.protobuf.Timestamp
+	17, // 11: cryptoalert.GetPriceHistoryResponse.points:type_name -> cryptoalert.PricePoint
+	1,  // 12: cryptoalert.GetPriceHistoryResponse.resolution:type_name -> cryptoalert.HistoryResolution
+	6,  // 13: cryptoalert.Indicator.average:type_name -> cryptoalert.MovingAverage
+	37, // 14: cryptoalert.Indicator.interval:type_name -> google.protobuf.Duration
+	7,  // 15: cryptoalert.Condition.op:type_name -> cryptoalert.ConditionOp
+	2,  // 16: cryptoalert.Condition.comparator:type_name -> cryptoalert.Comparator
+	20, // 17: cryptoalert.Condition.children:type_name -> cryptoalert.Condition
+	2,  // 18: cryptoalert.Alert.comparator:type_name -> cryptoalert.Comparator
+	36, // 19: cryptoalert.Alert.last_trigger:type_name -> google.protobuf.Timestamp
+	5,  // 20: cryptoalert.Alert.kind:type_name -> cryptoalert.AlertKind
+	8,  // 21: cryptoalert.Alert.direction:type_name -> cryptoalert.MoveDirection
+	37, // 22: cryptoalert.Alert.window:type_name -> google.protobuf.Duration
+	3,  // 23: cryptoalert.Alert.mode:type_name -> cryptoalert.TriggerMode
+	4,  // 24: cryptoalert.Alert.severity:type_name -> cryptoalert.Severity
+	20, // 25: cryptoalert.Alert.condition:type_name -> cryptoalert.Condition
+	19, // 26: cryptoalert.Alert.indicator:type_name -> cryptoalert.Indicator
+	37, // 27: cryptoalert.Alert.cooldown:type_name -> google.protobuf.Duration
+	36, // 28: cryptoalert.Alert.expires_at:type_name -> google.protobuf.Timestamp
+	2,  // 29: cryptoalert.CreateAlertRequest.comparator:type_name -> cryptoalert.Comparator
+	5,  // 30: cryptoalert.CreateAlertRequest.kind:type_name -> cryptoalert.AlertKind
+	8,  // 31: cryptoalert.CreateAlertRequest.direction:type_name -> cryptoalert.MoveDirection
+	37, // 32: cryptoalert.CreateAlertRequest.window:type_name -> google.protobuf.Duration
+	3,  // 33: cryptoalert.CreateAlertRequest.mode:type_name -> cryptoalert.TriggerMode
+	4,  // 34: cryptoalert.CreateAlertRequest.severity:type_name -> cryptoalert.Severity
+	20, // 35: cryptoalert.CreateAlertRequest.condition:type_name -> cryptoalert.Condition
+	19, // 36: cryptoalert.CreateAlertRequest.indicator:type_name -> cryptoalert.Indicator
+	37, // 37: cryptoalert.CreateAlertRequest.cooldown:type_name -> google.protobuf.Duration
+	36, // 38: cryptoalert.CreateAlertRequest.expires_at:type_name -> google.protobuf.Timestamp
+	21, // 39: cryptoalert.CreateAlertResponse.alert:type_name -> cryptoalert.Alert
+	21, // 40: cryptoalert.GetAlertsResponse.alerts:type_name -> cryptoalert.Alert
+	2,  // 41: cryptoalert.UpdateAlertRequest.comparator:type_name -> cryptoalert.Comparator
+	8,  // 42: cryptoalert.UpdateAlertRequest.direction:type_name -> cryptoalert.MoveDirection
+	37, // 43: cryptoalert.UpdateAlertRequest.window:type_name -> google.protobuf.Duration
+	3,  // 44: cryptoalert.UpdateAlertRequest.mode:type_name -> cryptoalert.TriggerMode
+	4,  // 45: cryptoalert.UpdateAlertRequest.severity:type_name -> cryptoalert.Severity
+	22, // 46: cryptoalert.UpdateAlertRequest.labels:type_name -> cryptoalert.LabelList
+	20, // 47: cryptoalert.UpdateAlertRequest.condition:type_name -> cryptoalert.Condition
+	19, // 48: cryptoalert.UpdateAlertRequest.indicator:type_name -> cryptoalert.Indicator
+	37, // 49: cryptoalert.UpdateAlertRequest.cooldown:type_name -> google.protobuf.Duration
+	36, // 50: cryptoalert.UpdateAlertRequest.expires_at:type_name -> google.protobuf.Timestamp
+	21, // 51: cryptoalert.UpdateAlertResponse.alert:type_name -> cryptoalert.Alert
+	4,  // 52: cryptoalert.AlertSubscriptionRequest.min_severity:type_name -> cryptoalert.Severity
+	21, // 53: cryptoalert.AlertTrigger.alert:type_name -> cryptoalert.Alert
+	36, // 54: cryptoalert.AlertTrigger.timestamp:type_name -> google.protobuf.Timestamp
+	9,  // 55: cryptoalert.AlertTrigger.event:type_name -> cryptoalert.TriggerEvent
+	36, // 56: cryptoalert.ListTriggersRequest.from:type_name -> google.protobuf.Timestamp
+	36, // 57: cryptoalert.ListTriggersRequest.to:type_name -> google.protobuf.Timestamp
+	32, // 58: cryptoalert.TriggerRecord.trigger:type_name -> cryptoalert.AlertTrigger
+	34, // 59: cryptoalert.ListTriggersResponse.triggers:type_name -> cryptoalert.TriggerRecord
+	10, // 60: cryptoalert.CryptoMarketData.SubscribePrices:input_type -> cryptoalert.PriceSubscriptionRequest
+	13, // 61: cryptoalert.CryptoMarketData.SubscribeCandles:input_type -> cryptoalert.CandleSubscriptionRequest
+	14, // 62: cryptoalert.CryptoMarketData.GetCandles:input_type -> cryptoalert.GetCandlesRequest
+	16, // 63: cryptoalert.CryptoMarketData.GetPriceHistory:input_type -> cryptoalert.GetPriceHistoryRequest
+	23, // 64: cryptoalert.CryptoAlertService.CreateAlert:input_type -> cryptoalert.CreateAlertRequest
+	25, // 65: cryptoalert.CryptoAlertService.GetAlerts:input_type -> cryptoalert.GetAlertsRequest
+	27, // 66: cryptoalert.CryptoAlertService.UpdateAlert:input_type -> cryptoalert.UpdateAlertRequest
+	29, // 67: cryptoalert.CryptoAlertService.DeleteAlert:input_type -> cryptoalert.DeleteAlertRequest
+	31, // 68: cryptoalert.CryptoAlertService.SubscribeAlerts:input_type -> cryptoalert.AlertSubscriptionRequest
+	33, // 69: cryptoalert.CryptoAlertService.ListTriggers:input_type -> cryptoalert.ListTriggersRequest
+	11, // 70: cryptoalert.CryptoMarketData.SubscribePrices:output_type -> cryptoalert.PriceTick
+	12, // 71: cryptoalert.CryptoMarketData.SubscribeCandles:output_type -> cryptoalert.Candle
+	15, // 72: cryptoalert.CryptoMarketData.GetCandles:output_type -> cryptoalert.GetCandlesResponse
+	18, // 73: cryptoalert.CryptoMarketData.GetPriceHistory:output_type -> cryptoalert.GetPriceHistoryResponse
+	24, // 74: cryptoalert.CryptoAlertService.CreateAlert:output_type -> cryptoalert.CreateAlertResponse
+	26, // 75: cryptoalert.CryptoAlertService.GetAlerts:output_type -> cryptoalert.GetAlertsResponse
+	28, // 76: cryptoalert.CryptoAlertService.UpdateAlert:output_type -> cryptoalert.UpdateAlertResponse
+	30, // 77: cryptoalert.CryptoAlertService.DeleteAlert:output_type -> cryptoalert.DeleteAlertResponse
+	32, // 78: cryptoalert.CryptoAlertService.SubscribeAlerts:output_type -> cryptoalert.AlertTrigger
+	35, // 79: cryptoalert.CryptoAlertService.ListTriggers:output_type -> cryptoalert.ListTriggersResponse
+	70, // [70:80] is the sub-list for method output_type
+	60, // [60:70] is the sub-list for method input_type
+	60, // [60:60] is the sub-list for extension type_name
+	60, // [60:60] is the sub-list for extension extendee
+	0,  // [0:60] is the sub-list for field type_name
 }
 
 func init() { file_api_cryptoalert_proto_init() }
@@ -937,14 +3162,15 @@ func file_api_cryptoalert_proto_init() {
 	if File_api_cryptoalert_proto != nil {
 		return
 	}
-	file_api_cryptoalert_proto_msgTypes[7].OneofWrappers = []any{}
+	file_api_cryptoalert_proto_msgTypes[17].OneofWrappers = []any{}
+	file_api_cryptoalert_proto_msgTypes[21].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_cryptoalert_proto_rawDesc), len(file_api_cryptoalert_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   13,
+			NumEnums:      10,
+			NumMessages:   26,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
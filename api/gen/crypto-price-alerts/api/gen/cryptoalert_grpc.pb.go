@@ -19,7 +19,10 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	CryptoMarketData_SubscribePrices_FullMethodName = "/cryptoalert.CryptoMarketData/SubscribePrices"
+	CryptoMarketData_SubscribePrices_FullMethodName  = "/cryptoalert.CryptoMarketData/SubscribePrices"
+	CryptoMarketData_SubscribeCandles_FullMethodName = "/cryptoalert.CryptoMarketData/SubscribeCandles"
+	CryptoMarketData_GetCandles_FullMethodName       = "/cryptoalert.CryptoMarketData/GetCandles"
+	CryptoMarketData_GetPriceHistory_FullMethodName  = "/cryptoalert.CryptoMarketData/GetPriceHistory"
 )
 
 // CryptoMarketDataClient is the client API for CryptoMarketData service.
@@ -30,6 +33,13 @@ const (
 type CryptoMarketDataClient interface {
 	// Subscribe to price updates for specified crypto symbols
 	SubscribePrices(ctx context.Context, in *PriceSubscriptionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PriceTick], error)
+	// Subscribe to OHLCV candles: a partial candle on every tick and the
+	// final candle when its interval closes
+	SubscribeCandles(ctx context.Context, in *CandleSubscriptionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Candle], error)
+	// Get the most recent candles for a symbol, oldest first
+	GetCandles(ctx context.Context, in *GetCandlesRequest, opts ...grpc.CallOption) (*GetCandlesResponse, error)
+	// Get recorded prices for a symbol over a time range
+	GetPriceHistory(ctx context.Context, in *GetPriceHistoryRequest, opts ...grpc.CallOption) (*GetPriceHistoryResponse, error)
 }
 
 type cryptoMarketDataClient struct {
@@ -59,6 +69,45 @@ func (c *cryptoMarketDataClient) SubscribePrices(ctx context.Context, in *PriceS
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type CryptoMarketData_SubscribePricesClient = grpc.ServerStreamingClient[PriceTick]
 
+func (c *cryptoMarketDataClient) SubscribeCandles(ctx context.Context, in *CandleSubscriptionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Candle], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CryptoMarketData_ServiceDesc.Streams[1], CryptoMarketData_SubscribeCandles_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CandleSubscriptionRequest, Candle]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CryptoMarketData_SubscribeCandlesClient = grpc.ServerStreamingClient[Candle]
+
+func (c *cryptoMarketDataClient) GetCandles(ctx context.Context, in *GetCandlesRequest, opts ...grpc.CallOption) (*GetCandlesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCandlesResponse)
+	err := c.cc.Invoke(ctx, CryptoMarketData_GetCandles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cryptoMarketDataClient) GetPriceHistory(ctx context.Context, in *GetPriceHistoryRequest, opts ...grpc.CallOption) (*GetPriceHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPriceHistoryResponse)
+	err := c.cc.Invoke(ctx, CryptoMarketData_GetPriceHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CryptoMarketDataServer is the server API for CryptoMarketData service.
 // All implementations must embed UnimplementedCryptoMarketDataServer
 // for forward compatibility.
@@ -67,6 +116,13 @@ type CryptoMarketData_SubscribePricesClient = grpc.ServerStreamingClient[PriceTi
 type CryptoMarketDataServer interface {
 	// Subscribe to price updates for specified crypto symbols
 	SubscribePrices(*PriceSubscriptionRequest, grpc.ServerStreamingServer[PriceTick]) error
+	// Subscribe to OHLCV candles: a partial candle on every tick and the
+	// final candle when its interval closes
+	SubscribeCandles(*CandleSubscriptionRequest, grpc.ServerStreamingServer[Candle]) error
+	// Get the most recent candles for a symbol, oldest first
+	GetCandles(context.Context, *GetCandlesRequest) (*GetCandlesResponse, error)
+	// Get recorded prices for a symbol over a time range
+	GetPriceHistory(context.Context, *GetPriceHistoryRequest) (*GetPriceHistoryResponse, error)
 	mustEmbedUnimplementedCryptoMarketDataServer()
 }
 
@@ -80,6 +136,15 @@ type UnimplementedCryptoMarketDataServer struct{}
 func (UnimplementedCryptoMarketDataServer) SubscribePrices(*PriceSubscriptionRequest, grpc.ServerStreamingServer[PriceTick]) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribePrices not implemented")
 }
+func (UnimplementedCryptoMarketDataServer) SubscribeCandles(*CandleSubscriptionRequest, grpc.ServerStreamingServer[Candle]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeCandles not implemented")
+}
+func (UnimplementedCryptoMarketDataServer) GetCandles(context.Context, *GetCandlesRequest) (*GetCandlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCandles not implemented")
+}
+func (UnimplementedCryptoMarketDataServer) GetPriceHistory(context.Context, *GetPriceHistoryRequest) (*GetPriceHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPriceHistory not implemented")
+}
 func (UnimplementedCryptoMarketDataServer) mustEmbedUnimplementedCryptoMarketDataServer() {}
 func (UnimplementedCryptoMarketDataServer) testEmbeddedByValue()                          {}
 
@@ -112,19 +177,80 @@ func _CryptoMarketData_SubscribePrices_Handler(srv interface{}, stream grpc.Serv
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type CryptoMarketData_SubscribePricesServer = grpc.ServerStreamingServer[PriceTick]
 
+func _CryptoMarketData_SubscribeCandles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CandleSubscriptionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CryptoMarketDataServer).SubscribeCandles(m, &grpc.GenericServerStream[CandleSubscriptionRequest, Candle]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CryptoMarketData_SubscribeCandlesServer = grpc.ServerStreamingServer[Candle]
+
+func _CryptoMarketData_GetCandles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCandlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoMarketDataServer).GetCandles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoMarketData_GetCandles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoMarketDataServer).GetCandles(ctx, req.(*GetCandlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CryptoMarketData_GetPriceHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPriceHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoMarketDataServer).GetPriceHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoMarketData_GetPriceHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoMarketDataServer).GetPriceHistory(ctx, req.(*GetPriceHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CryptoMarketData_ServiceDesc is the grpc.ServiceDesc for CryptoMarketData service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var CryptoMarketData_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "cryptoalert.CryptoMarketData",
 	HandlerType: (*CryptoMarketDataServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCandles",
+			Handler:    _CryptoMarketData_GetCandles_Handler,
+		},
+		{
+			MethodName: "GetPriceHistory",
+			Handler:    _CryptoMarketData_GetPriceHistory_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "SubscribePrices",
 			Handler:       _CryptoMarketData_SubscribePrices_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeCandles",
+			Handler:       _CryptoMarketData_SubscribeCandles_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "api/cryptoalert.proto",
 }
@@ -135,6 +261,7 @@ const (
 	CryptoAlertService_UpdateAlert_FullMethodName     = "/cryptoalert.CryptoAlertService/UpdateAlert"
 	CryptoAlertService_DeleteAlert_FullMethodName     = "/cryptoalert.CryptoAlertService/DeleteAlert"
 	CryptoAlertService_SubscribeAlerts_FullMethodName = "/cryptoalert.CryptoAlertService/SubscribeAlerts"
+	CryptoAlertService_ListTriggers_FullMethodName    = "/cryptoalert.CryptoAlertService/ListTriggers"
 )
 
 // CryptoAlertServiceClient is the client API for CryptoAlertService service.
@@ -145,7 +272,7 @@ const (
 type CryptoAlertServiceClient interface {
 	// Create a new crypto price alert
 	CreateAlert(ctx context.Context, in *CreateAlertRequest, opts ...grpc.CallOption) (*CreateAlertResponse, error)
-	// Get all alerts for a user
+	// Get all alerts owned by the caller; admins see every tenant's alerts
 	GetAlerts(ctx context.Context, in *GetAlertsRequest, opts ...grpc.CallOption) (*GetAlertsResponse, error)
 	// Update an existing alert
 	UpdateAlert(ctx context.Context, in *UpdateAlertRequest, opts ...grpc.CallOption) (*UpdateAlertResponse, error)
@@ -153,6 +280,8 @@ type CryptoAlertServiceClient interface {
 	DeleteAlert(ctx context.Context, in *DeleteAlertRequest, opts ...grpc.CallOption) (*DeleteAlertResponse, error)
 	// Subscribe to alert triggers
 	SubscribeAlerts(ctx context.Context, in *AlertSubscriptionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[AlertTrigger], error)
+	// List past triggers of the caller's alerts, newest first; admins see every tenant's
+	ListTriggers(ctx context.Context, in *ListTriggersRequest, opts ...grpc.CallOption) (*ListTriggersResponse, error)
 }
 
 type cryptoAlertServiceClient struct {
@@ -222,6 +351,16 @@ func (c *cryptoAlertServiceClient) SubscribeAlerts(ctx context.Context, in *Aler
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type CryptoAlertService_SubscribeAlertsClient = grpc.ServerStreamingClient[AlertTrigger]
 
+func (c *cryptoAlertServiceClient) ListTriggers(ctx context.Context, in *ListTriggersRequest, opts ...grpc.CallOption) (*ListTriggersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTriggersResponse)
+	err := c.cc.Invoke(ctx, CryptoAlertService_ListTriggers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CryptoAlertServiceServer is the server API for CryptoAlertService service.
 // All implementations must embed UnimplementedCryptoAlertServiceServer
 // for forward compatibility.
@@ -230,7 +369,7 @@ type CryptoAlertService_SubscribeAlertsClient = grpc.ServerStreamingClient[Alert
 type CryptoAlertServiceServer interface {
 	// Create a new crypto price alert
 	CreateAlert(context.Context, *CreateAlertRequest) (*CreateAlertResponse, error)
-	// Get all alerts for a user
+	// Get all alerts owned by the caller; admins see every tenant's alerts
 	GetAlerts(context.Context, *GetAlertsRequest) (*GetAlertsResponse, error)
 	// Update an existing alert
 	UpdateAlert(context.Context, *UpdateAlertRequest) (*UpdateAlertResponse, error)
@@ -238,6 +377,8 @@ type CryptoAlertServiceServer interface {
 	DeleteAlert(context.Context, *DeleteAlertRequest) (*DeleteAlertResponse, error)
 	// Subscribe to alert triggers
 	SubscribeAlerts(*AlertSubscriptionRequest, grpc.ServerStreamingServer[AlertTrigger]) error
+	// List past triggers of the caller's alerts, newest first; admins see every tenant's
+	ListTriggers(context.Context, *ListTriggersRequest) (*ListTriggersResponse, error)
 	mustEmbedUnimplementedCryptoAlertServiceServer()
 }
 
@@ -263,6 +404,9 @@ func (UnimplementedCryptoAlertServiceServer) DeleteAlert(context.Context, *Delet
 func (UnimplementedCryptoAlertServiceServer) SubscribeAlerts(*AlertSubscriptionRequest, grpc.ServerStreamingServer[AlertTrigger]) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeAlerts not implemented")
 }
+func (UnimplementedCryptoAlertServiceServer) ListTriggers(context.Context, *ListTriggersRequest) (*ListTriggersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTriggers not implemented")
+}
 func (UnimplementedCryptoAlertServiceServer) mustEmbedUnimplementedCryptoAlertServiceServer() {}
 func (UnimplementedCryptoAlertServiceServer) testEmbeddedByValue()                            {}
 
@@ -367,6 +511,24 @@ func _CryptoAlertService_SubscribeAlerts_Handler(srv interface{}, stream grpc.Se
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type CryptoAlertService_SubscribeAlertsServer = grpc.ServerStreamingServer[AlertTrigger]
 
+func _CryptoAlertService_ListTriggers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTriggersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CryptoAlertServiceServer).ListTriggers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CryptoAlertService_ListTriggers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CryptoAlertServiceServer).ListTriggers(ctx, req.(*ListTriggersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CryptoAlertService_ServiceDesc is the grpc.ServiceDesc for CryptoAlertService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -390,6 +552,10 @@ var CryptoAlertService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteAlert",
 			Handler:    _CryptoAlertService_DeleteAlert_Handler,
 		},
+		{
+			MethodName: "ListTriggers",
+			Handler:    _CryptoAlertService_ListTriggers_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"crypto-price-alerts/internal/alerts"
+	"crypto-price-alerts/internal/backtest"
+	"crypto-price-alerts/pkg/models"
+	"crypto-price-alerts/pkg/rules"
+)
+
+const usage = `Usage: backtest [flags] <tick file or recording directory>...
+
+Replays recorded ticks (.csv, .jsonl or columnar .json, optionally .gz, or a
+directory written by the server's tick recorder) through the alert engine and reports how often each alert would have fired.
+
+Flags:
+`
+
+// ruleList collects repeated -rule flags.
+type ruleList []string
+
+func (r *ruleList) String() string {
+	return strings.Join(*r, "; ")
+}
+
+func (r *ruleList) Set(value string) error {
+	*r = append(*r, value)
+	return nil
+}
+
+func main() {
+	var ruleFlags ruleList
+	alertsPath := flag.String("alerts", "", "JSON array of alerts to test, such as a server's alerts.snapshot.json")
+	flag.Var(&ruleFlags, "rule", "Rule expression to test; may be repeated")
+	cooldown := flag.Duration("cooldown", alerts.DefaultEngineConfig().Cooldown, "Minimum time between triggers of the same alert")
+	source := flag.String("source", "", "Tick source alerts follow by default; empty follows every tick")
+	from := flag.String("from", "", "Replay ticks from this RFC 3339 time")
+	to := flag.String("to", "", "Replay ticks before this RFC 3339 time")
+	listTriggers := flag.Bool("triggers", false, "List every trigger after the report")
+	verbose := flag.Bool("v", false, "Log engine activity")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 || (*alertsPath == "" && len(ruleFlags) == 0) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	alertSet, err := loadAlerts(*alertsPath, ruleFlags)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cfg := backtest.Config{Files: flag.Args(), Cooldown: *cooldown, Source: *source}
+	if cfg.From, err = parseTime(*from); err != nil {
+		log.Fatal(err)
+	}
+	if cfg.To, err = parseTime(*to); err != nil {
+		log.Fatal(err)
+	}
+
+	if !*verbose {
+		log.SetOutput(io.Discard)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	report, err := backtest.Run(ctx, cfg, alertSet)
+	log.SetOutput(os.Stderr)
+	if err != nil {
+		log.Fatalf("Backtest failed: %v", err)
+	}
+
+	printReport(report, *cooldown)
+	if *listTriggers {
+		printTriggers(report)
+	}
+}
+
+func loadAlerts(path string, ruleFlags []string) ([]*models.Alert, error) {
+	var alertSet []*models.Alert
+	if path != "" {
+		loaded, err := backtest.LoadAlerts(path)
+		if err != nil {
+			return nil, err
+		}
+		alertSet = loaded
+	}
+
+	for i, rule := range ruleFlags {
+		program, err := rules.Compile(rule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule %q: %v", rule, err)
+		}
+		alert := models.NewExpressionAlert(program, "")
+		alert.ID = fmt.Sprintf("rule-%d", i+1)
+		alertSet = append(alertSet, alert)
+	}
+
+	return alertSet, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: expected RFC 3339, e.g. 2025-01-01T00:00:00Z", value)
+	}
+	return t, nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func printReport(report *backtest.Report, cooldown time.Duration) {
+	if report.Ticks == 0 {
+		fmt.Println("No ticks replayed")
+	} else {
+		fmt.Printf("Replayed %d ticks from %s to %s (cooldown %v)\n\n",
+			report.Ticks, formatTime(report.Start), formatTime(report.End), cooldown)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ALERT\tRULE\tTRIGGERS\tFIRST\tLAST")
+	for _, entry := range report.Alerts {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n",
+			entry.Alert.ID, entry.Alert.Rule(), entry.Triggers, formatTime(entry.First), formatTime(entry.Last))
+	}
+	w.Flush()
+
+	fmt.Printf("\n%d trigger(s) from %d alert(s)\n", len(report.Triggers), len(report.Alerts))
+}
+
+func printTriggers(report *backtest.Report) {
+	fmt.Println()
+	for _, trigger := range report.Triggers {
+		fmt.Printf("%s  %s  %.2f  %s\n",
+			formatTime(trigger.Timestamp), trigger.Alert.ID, trigger.TriggeredPrice, trigger.Alert.Rule())
+	}
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -11,25 +13,76 @@ import (
 	"time"
 
 	pb "crypto-price-alerts/api/gen/crypto-price-alerts/api/gen"
+	"crypto-price-alerts/internal/auth"
+	"crypto-price-alerts/pkg/models"
+	"crypto-price-alerts/pkg/rules"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-const serverAddr = "127.0.0.1:9090"
+const defaultServerAddr = "127.0.0.1:9090"
+
+// currentUser is the identity the CLI sends; list-alerts shows the owner of
+// any alert that belongs to someone else (admins only).
+var currentUser string
 
 func main() {
-	fmt.Printf("Attempting to connect to server at %s...\n", serverAddr)
+	user := flag.String("user", envOrDefault("ALERT_USER", envOrDefault("USER", "default")), "User the CLI acts as [$ALERT_USER]")
+	role := flag.String("role", envOrDefault("ALERT_ROLE", string(auth.RoleUser)), "Role to request: user or admin [$ALERT_ROLE]")
+	serverAddr := flag.String("addr", envOrDefault("ALERT_SERVER", defaultServerAddr), "Server address [$ALERT_SERVER]")
+	useTLS := flag.Bool("tls", false, "Connect with TLS")
+	caCert := flag.String("ca-cert", os.Getenv("ALERT_CA_CERT"), "CA certificate to verify the server; implies -tls [$ALERT_CA_CERT]")
+	clientCert := flag.String("cert", os.Getenv("ALERT_CLIENT_CERT"), "Client certificate for mutual TLS; implies -tls [$ALERT_CLIENT_CERT]")
+	clientKey := flag.String("key", os.Getenv("ALERT_CLIENT_KEY"), "Client private key for mutual TLS [$ALERT_CLIENT_KEY]")
+	serverName := flag.String("server-name", "", "Override the server name checked against its certificate")
+	apiKey := flag.String("api-key", os.Getenv("ALERT_API_KEY"), "API key to authenticate with [$ALERT_API_KEY]")
+	token := flag.String("token", os.Getenv("ALERT_TOKEN"), "Bearer token to authenticate with [$ALERT_TOKEN]")
+	flag.Parse()
+
+	identity := auth.Identity{UserID: *user, Role: auth.Role(*role)}
+	currentUser = identity.UserID
+
+	secure := *useTLS || *caCert != "" || *clientCert != ""
+	dialOpts := []grpc.DialOption{grpc.WithBlock()}
+
+	if secure {
+		tlsConfig, err := auth.ClientTLSConfig(*caCert, *clientCert, *clientKey, *serverName)
+		if err != nil {
+			log.Fatalf("Failed to configure TLS: %v", err)
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	if *apiKey != "" || *token != "" {
+		if !secure {
+			fmt.Println("Warning: sending credentials without TLS")
+		}
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(auth.Credentials{
+			APIKey:   *apiKey,
+			Token:    *token,
+			Insecure: !secure,
+		}))
+	} else {
+		dialOpts = append(dialOpts,
+			grpc.WithUnaryInterceptor(auth.UnaryClientInterceptor(identity)),
+			grpc.WithStreamInterceptor(auth.StreamClientInterceptor(identity)),
+		)
+	}
+
+	fmt.Printf("Attempting to connect to server at %s...\n", *serverAddr)
 	
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	
-	conn, err := grpc.DialContext(ctx, serverAddr, 
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
-	)
+	conn, err := grpc.DialContext(ctx, *serverAddr, dialOpts...)
 	if err != nil {
-		log.Fatalf("Failed to connect to server at %s: %v\nMake sure the server is running with 'make run-server'", serverAddr, err)
+		log.Fatalf("Failed to connect to server at %s: %v\nMake sure the server is running with 'make run-server'", *serverAddr, err)
 	}
 	defer conn.Close()
 
@@ -37,20 +90,26 @@ func main() {
 	cryptoAlertServiceClient := pb.NewCryptoAlertServiceClient(conn)
 
 	fmt.Println("Crypto Price Alert CLI")
-	fmt.Println("Connected to server at", serverAddr)
+	fmt.Println("Connected to server at", *serverAddr)
+	if *apiKey == "" && *token == "" {
+		fmt.Printf("Acting as %s (%s)\n", identity.UserID, identity.Role)
+	}
 	fmt.Println()
 
 	scanner := bufio.NewScanner(os.Stdin)
 	
 	for {
 		fmt.Println("Available commands:")
-		fmt.Println("1. watch <symbols>     - Watch real-time prices (e.g., watch BTC,ETH)")
+		fmt.Println("1. watch <symbols> [source] - Watch real-time prices (e.g., watch BTC,ETH binance)")
 		fmt.Println("2. create-alert        - Create a new price alert")
 		fmt.Println("3. list-alerts         - List all alerts")
 		fmt.Println("4. delete-alert <id>   - Delete an alert")
-		fmt.Println("5. watch-alerts        - Watch for alert triggers")
-		fmt.Println("6. help                - Show this help")
-		fmt.Println("7. quit                - Exit the application")
+		fmt.Println("5. watch-alerts [filters] - Watch for alert triggers (e.g., watch-alerts symbols=BTC labels=desk severity=warning)")
+		fmt.Println("6. candles <symbol> [interval] [count] [source] - Show recent candles (e.g., candles BTC 5m 20)")
+		fmt.Println("7. price-history <symbol> [range] [resolution] - Show recorded prices (e.g., price-history BTC 24h 1h)")
+		fmt.Println("8. history [filters]   - Show past alert triggers (e.g., history symbol=BTC since=12h)")
+		fmt.Println("9. help                - Show this help")
+		fmt.Println("10. quit               - Exit the application")
 		fmt.Print("\nEnter command: ")
 
 		if !scanner.Scan() {
@@ -71,7 +130,11 @@ func main() {
 				continue
 			}
 			symbols := strings.Split(parts[1], ",")
-			watchPrices(cryptoMarketDataClient, symbols)
+			source := ""
+			if len(parts) > 2 {
+				source = parts[2]
+			}
+			watchPrices(cryptoMarketDataClient, symbols, source)
 
 		case "2", "create-alert":
 			createAlert(cryptoAlertServiceClient, scanner)
@@ -87,12 +150,45 @@ func main() {
 			deleteAlert(cryptoAlertServiceClient, parts[1])
 
 		case "5", "watch-alerts":
-			watchAlerts(cryptoAlertServiceClient)
+			req, err := parseAlertFilters(parts[1:])
+			if err != nil {
+				fmt.Println(err)
+				fmt.Println("Usage: watch-alerts [symbols=BTC,ETH] [ids=<id>,...] [labels=a,b] [severity=info|warning|critical] [resume=<sequence>]")
+				continue
+			}
+			if req.ResumeFromSequence == nil && lastSequence > 0 {
+				resume := lastSequence
+				req.ResumeFromSequence = &resume
+			}
+			watchAlerts(cryptoAlertServiceClient, req)
+
+		case "6", "candles":
+			if len(parts) < 2 {
+				fmt.Println("Usage: candles <symbol> [1m|5m|15m|1h|1d] [count] [source]")
+				continue
+			}
+			showCandles(cryptoMarketDataClient, parts[1:])
+
+		case "7", "price-history":
+			if len(parts) < 2 {
+				fmt.Println("Usage: price-history <symbol> [range, e.g. 1h] [raw|1m|5m|15m|1h|1d]")
+				continue
+			}
+			showPriceHistory(cryptoMarketDataClient, parts[1:])
+
+		case "8", "history":
+			req, err := parseHistoryFilters(parts[1:])
+			if err != nil {
+				fmt.Println(err)
+				fmt.Println("Usage: history [alert=<id>] [symbol=BTC] [since=24h | from=<RFC 3339>] [to=<RFC 3339>] [limit=20] [page=<token>]")
+				continue
+			}
+			showTriggerHistory(cryptoAlertServiceClient, req)
 
-		case "6", "help":
+		case "9", "help":
 			continue
 
-		case "7", "quit", "exit":
+		case "10", "quit", "exit":
 			fmt.Println("Goodbye!")
 			return
 
@@ -104,7 +200,7 @@ func main() {
 	}
 }
 
-func watchPrices(client pb.CryptoMarketDataClient, symbols []string) {
+func watchPrices(client pb.CryptoMarketDataClient, symbols []string, source string) {
 	fmt.Printf("📈 Watching prices for: %v (Press Ctrl+C to stop)\n", symbols)
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -112,6 +208,7 @@ func watchPrices(client pb.CryptoMarketDataClient, symbols []string) {
 
 	req := &pb.PriceSubscriptionRequest{
 		Symbols: symbols,
+		Source:  source,
 	}
 
 	stream, err := client.SubscribePrices(ctx, req)
@@ -128,7 +225,131 @@ func watchPrices(client pb.CryptoMarketDataClient, symbols []string) {
 		}
 
 		timestamp := tick.Timestamp.AsTime().Format("15:04:05")
-		fmt.Printf("[%s] %s: $%.2f\n", timestamp, tick.Symbol, tick.Price)
+		if tick.Source != "" {
+			fmt.Printf("[%s] %s: $%.2f (%s)\n", timestamp, tick.Symbol, tick.Price, tick.Source)
+		} else {
+			fmt.Printf("[%s] %s: $%.2f\n", timestamp, tick.Symbol, tick.Price)
+		}
+	}
+}
+
+var candleIntervals = map[string]pb.CandleInterval{
+	"1m":  pb.CandleInterval_CANDLE_INTERVAL_1M,
+	"5m":  pb.CandleInterval_CANDLE_INTERVAL_5M,
+	"15m": pb.CandleInterval_CANDLE_INTERVAL_15M,
+	"1h":  pb.CandleInterval_CANDLE_INTERVAL_1H,
+	"1d":  pb.CandleInterval_CANDLE_INTERVAL_1D,
+}
+
+func showCandles(client pb.CryptoMarketDataClient, args []string) {
+	req := &pb.GetCandlesRequest{
+		Symbol:         strings.ToUpper(args[0]),
+		Interval:       pb.CandleInterval_CANDLE_INTERVAL_1M,
+		Limit:          20,
+		IncludePartial: true,
+	}
+
+	if len(args) > 1 {
+		interval, ok := candleIntervals[strings.ToLower(args[1])]
+		if !ok {
+			fmt.Printf("Invalid interval %q (use 1m, 5m, 15m, 1h or 1d)\n", args[1])
+			return
+		}
+		req.Interval = interval
+	}
+	if len(args) > 2 {
+		count, err := strconv.ParseUint(args[2], 10, 32)
+		if err != nil {
+			fmt.Printf("Invalid count: %v\n", err)
+			return
+		}
+		req.Limit = uint32(count)
+	}
+	if len(args) > 3 {
+		req.Source = args[3]
+	}
+
+	resp, err := client.GetCandles(context.Background(), req)
+	if err != nil {
+		log.Printf("Error getting candles: %v", err)
+		return
+	}
+
+	if len(resp.Candles) == 0 {
+		fmt.Printf("No candles for %s yet\n", req.Symbol)
+		return
+	}
+
+	fmt.Printf("%-17s %12s %12s %12s %12s %7s\n", "Open time", "Open", "High", "Low", "Close", "Ticks")
+	for _, candle := range resp.Candles {
+		openTime := candle.OpenTime.AsTime().Local().Format("2006-01-02 15:04")
+		line := fmt.Sprintf("%-17s %12.2f %12.2f %12.2f %12.2f %7d", openTime,
+			candle.Open, candle.High, candle.Low, candle.Close, candle.TickCount)
+		if !candle.Closed {
+			line += " (in progress)"
+		}
+		fmt.Println(line)
+	}
+}
+
+var historyResolutions = map[string]pb.HistoryResolution{
+	"raw": pb.HistoryResolution_HISTORY_RESOLUTION_RAW,
+	"1m":  pb.HistoryResolution_HISTORY_RESOLUTION_1M,
+	"5m":  pb.HistoryResolution_HISTORY_RESOLUTION_5M,
+	"15m": pb.HistoryResolution_HISTORY_RESOLUTION_15M,
+	"1h":  pb.HistoryResolution_HISTORY_RESOLUTION_1H,
+	"1d":  pb.HistoryResolution_HISTORY_RESOLUTION_1D,
+}
+
+func showPriceHistory(client pb.CryptoMarketDataClient, args []string) {
+	span := time.Hour
+	if len(args) > 1 {
+		parsed, err := time.ParseDuration(args[1])
+		if err != nil || parsed <= 0 {
+			fmt.Printf("Invalid range %q (e.g., 30m, 24h)\n", args[1])
+			return
+		}
+		span = parsed
+	}
+
+	to := time.Now()
+	req := &pb.GetPriceHistoryRequest{
+		Symbol: strings.ToUpper(args[0]),
+		From:   timestamppb.New(to.Add(-span)),
+		To:     timestamppb.New(to),
+	}
+
+	if len(args) > 2 {
+		resolution, ok := historyResolutions[strings.ToLower(args[2])]
+		if !ok {
+			fmt.Printf("Invalid resolution %q (use raw, 1m, 5m, 15m, 1h or 1d)\n", args[2])
+			return
+		}
+		req.Resolution = resolution
+	}
+
+	resp, err := client.GetPriceHistory(context.Background(), req)
+	if err != nil {
+		log.Printf("Error getting price history: %v", err)
+		return
+	}
+
+	if len(resp.Points) == 0 {
+		fmt.Printf("No recorded prices for %s in the last %v\n", req.Symbol, span)
+		return
+	}
+
+	if resp.Resolution == pb.HistoryResolution_HISTORY_RESOLUTION_RAW {
+		for _, point := range resp.Points {
+			fmt.Printf("%s %12.2f\n", point.Timestamp.AsTime().Local().Format("2006-01-02 15:04:05"), point.Close)
+		}
+		return
+	}
+
+	fmt.Printf("%-17s %12s %12s %12s %12s %7s\n", "Time", "Open", "High", "Low", "Close", "Ticks")
+	for _, point := range resp.Points {
+		fmt.Printf("%-17s %12.2f %12.2f %12.2f %12.2f %7d\n", point.Timestamp.AsTime().Local().Format("2006-01-02 15:04"),
+			point.Open, point.High, point.Low, point.Close, point.TickCount)
 	}
 }
 
@@ -141,57 +362,96 @@ func createAlert(client pb.CryptoAlertServiceClient, scanner *bufio.Scanner) {
 	}
 	symbol := strings.TrimSpace(strings.ToUpper(scanner.Text()))
 
-	fmt.Println("Select comparator:")
-	fmt.Println("1. > (greater than)")
-	fmt.Println("2. >= (greater than or equal)")
-	fmt.Println("3. < (less than)")
-	fmt.Println("4. <= (less than or equal)")
-	fmt.Println("5. == (equal)")
+	fmt.Println("Select alert type:")
+	fmt.Println("1. Price threshold (e.g., BTC > $100000)")
+	fmt.Println("2. Percentage move (e.g., BTC moves 3% within 15m)")
+	fmt.Println("3. Compound condition over several symbols (e.g., BTC > 100000 AND ETH < 3000)")
+	fmt.Println("4. Rule expression (e.g., pct_change(BTC, 1h) > 5 && price(ETH) < sma(ETH, 50))")
+	fmt.Println("5. Technical indicator (moving average crossover, RSI, Bollinger bands)")
 	fmt.Print("Enter choice (1-5): ")
-	
+
 	if !scanner.Scan() {
 		return
 	}
-	
-	var comparator pb.Comparator
+
+	req := &pb.CreateAlertRequest{
+		Symbol: symbol,
+	}
+
 	switch strings.TrimSpace(scanner.Text()) {
 	case "1":
-		comparator = pb.Comparator_COMPARATOR_GT
+		req.Kind = pb.AlertKind_ALERT_KIND_THRESHOLD
+		if !promptThreshold(scanner, req) {
+			return
+		}
 	case "2":
-		comparator = pb.Comparator_COMPARATOR_GTE
+		req.Kind = pb.AlertKind_ALERT_KIND_PERCENT_MOVE
+		if !promptPercentMove(scanner, req) {
+			return
+		}
 	case "3":
-		comparator = pb.Comparator_COMPARATOR_LT
+		req.Kind = pb.AlertKind_ALERT_KIND_COMPOUND
+		if !promptCondition(scanner, req) {
+			return
+		}
 	case "4":
-		comparator = pb.Comparator_COMPARATOR_LTE
+		req.Kind = pb.AlertKind_ALERT_KIND_EXPRESSION
+		if !promptExpression(scanner, req) {
+			return
+		}
 	case "5":
-		comparator = pb.Comparator_COMPARATOR_EQ
+		if !promptIndicator(scanner, req) {
+			return
+		}
 	default:
 		fmt.Println("Invalid choice")
 		return
 	}
 
-	fmt.Print("Enter threshold price: $")
+	fmt.Print("Enter price source (optional, e.g., binance; blank for default): ")
 	if !scanner.Scan() {
 		return
 	}
-	
-	threshold, err := strconv.ParseFloat(strings.TrimSpace(scanner.Text()), 64)
+	req.Source = strings.ToLower(strings.TrimSpace(scanner.Text()))
+
+	fmt.Print("Enter labels (optional, comma-separated): ")
+	if !scanner.Scan() {
+		return
+	}
+	if labels := strings.TrimSpace(scanner.Text()); labels != "" {
+		req.Labels = strings.Split(labels, ",")
+	}
+
+	fmt.Print("Enter severity (info, warning, critical; default info): ")
+	if !scanner.Scan() {
+		return
+	}
+	severity, err := parseSeverity(scanner.Text())
 	if err != nil {
-		fmt.Printf("Invalid price: %v\n", err)
+		fmt.Println(err)
 		return
 	}
+	req.Severity = severity
 
 	fmt.Print("Enter note (optional): ")
 	if !scanner.Scan() {
 		return
 	}
-	note := strings.TrimSpace(scanner.Text())
+	req.Note = strings.TrimSpace(scanner.Text())
 
-	req := &pb.CreateAlertRequest{
-		Symbol:     symbol,
-		Comparator: comparator,
-		Threshold:  threshold,
-		Note:       note,
+	fmt.Print("Enter webhook URL (optional): ")
+	if !scanner.Scan() {
+		return
+	}
+	req.WebhookUrl = strings.TrimSpace(scanner.Text())
+
+	fmt.Print("Enter firing limits (optional, e.g., cooldown=5m expires=24h max=3 once): ")
+	if !scanner.Scan() {
+		return
+	}
+	if err := parseAlertLimits(strings.Fields(scanner.Text()), req); err != nil {
+		fmt.Println(err)
+		return
 	}
 
 	resp, err := client.CreateAlert(context.Background(), req)
@@ -202,11 +462,305 @@ func createAlert(client pb.CryptoAlertServiceClient, scanner *bufio.Scanner) {
 
 		fmt.Printf("Alert created successfully!\n")
 	fmt.Printf("ID: %s\n", resp.Alert.Id)
-	fmt.Printf("Rule: %s %s $%.2f\n", resp.Alert.Symbol, 
-		comparatorToString(resp.Alert.Comparator), resp.Alert.Threshold)
+	fmt.Printf("Rule: %s\n", ruleToString(resp.Alert))
 	if resp.Alert.Note != "" {
 		fmt.Printf("Note: %s\n", resp.Alert.Note)
 	}
+	if resp.WebhookSecret != "" {
+		fmt.Printf("Webhook secret: %s\n", resp.WebhookSecret)
+		fmt.Println("Save it now to verify the webhook's X-Alert-Signature; it is not shown again.")
+	}
+}
+
+func promptThreshold(scanner *bufio.Scanner, req *pb.CreateAlertRequest) bool {
+	fmt.Println("Select trigger mode:")
+	fmt.Println("1. Level (fire while the condition holds)")
+	fmt.Println("2. Cross above (fire when the price rises through the threshold)")
+	fmt.Println("3. Cross below (fire when the price falls through the threshold)")
+	fmt.Println("4. Cross either way")
+	fmt.Print("Enter choice (1-4): ")
+
+	if !scanner.Scan() {
+		return false
+	}
+
+	switch strings.TrimSpace(scanner.Text()) {
+	case "1":
+		req.Mode = pb.TriggerMode_TRIGGER_MODE_LEVEL
+		if !promptComparator(scanner, req) {
+			return false
+		}
+	case "2":
+		req.Mode = pb.TriggerMode_TRIGGER_MODE_CROSS_UP
+	case "3":
+		req.Mode = pb.TriggerMode_TRIGGER_MODE_CROSS_DOWN
+	case "4":
+		req.Mode = pb.TriggerMode_TRIGGER_MODE_CROSS_ANY
+	default:
+		fmt.Println("Invalid choice")
+		return false
+	}
+
+	fmt.Print("Enter threshold price: $")
+	if !scanner.Scan() {
+		return false
+	}
+
+	threshold, err := strconv.ParseFloat(strings.TrimSpace(scanner.Text()), 64)
+	if err != nil {
+		fmt.Printf("Invalid price: %v\n", err)
+		return false
+	}
+	req.Threshold = threshold
+
+	return true
+}
+
+func promptComparator(scanner *bufio.Scanner, req *pb.CreateAlertRequest) bool {
+	fmt.Println("Select comparator:")
+	fmt.Println("1. > (greater than)")
+	fmt.Println("2. >= (greater than or equal)")
+	fmt.Println("3. < (less than)")
+	fmt.Println("4. <= (less than or equal)")
+	fmt.Println("5. == (equal)")
+	fmt.Print("Enter choice (1-5): ")
+
+	if !scanner.Scan() {
+		return false
+	}
+
+	switch strings.TrimSpace(scanner.Text()) {
+	case "1":
+		req.Comparator = pb.Comparator_COMPARATOR_GT
+	case "2":
+		req.Comparator = pb.Comparator_COMPARATOR_GTE
+	case "3":
+		req.Comparator = pb.Comparator_COMPARATOR_LT
+	case "4":
+		req.Comparator = pb.Comparator_COMPARATOR_LTE
+	case "5":
+		req.Comparator = pb.Comparator_COMPARATOR_EQ
+	default:
+		fmt.Println("Invalid choice")
+		return false
+	}
+
+	return true
+}
+
+func promptPercentMove(scanner *bufio.Scanner, req *pb.CreateAlertRequest) bool {
+	fmt.Print("Enter move size in percent (e.g., 3): ")
+	if !scanner.Scan() {
+		return false
+	}
+
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(scanner.Text()), "%"), 64)
+	if err != nil {
+		fmt.Printf("Invalid percentage: %v\n", err)
+		return false
+	}
+	req.Threshold = percent
+
+	fmt.Print("Enter time window (e.g., 15m, 1h): ")
+	if !scanner.Scan() {
+		return false
+	}
+
+	window, err := time.ParseDuration(strings.TrimSpace(scanner.Text()))
+	if err != nil {
+		fmt.Printf("Invalid window: %v\n", err)
+		return false
+	}
+	req.Window = durationpb.New(window)
+
+	fmt.Println("Select direction:")
+	fmt.Println("1. Either direction")
+	fmt.Println("2. Up only")
+	fmt.Println("3. Down only")
+	fmt.Print("Enter choice (1-3): ")
+
+	if !scanner.Scan() {
+		return false
+	}
+
+	switch strings.TrimSpace(scanner.Text()) {
+	case "1":
+		req.Direction = pb.MoveDirection_MOVE_DIRECTION_EITHER
+	case "2":
+		req.Direction = pb.MoveDirection_MOVE_DIRECTION_UP
+	case "3":
+		req.Direction = pb.MoveDirection_MOVE_DIRECTION_DOWN
+	default:
+		fmt.Println("Invalid choice")
+		return false
+	}
+
+	return true
+}
+
+func promptCondition(scanner *bufio.Scanner, req *pb.CreateAlertRequest) bool {
+	fmt.Println("Combine comparisons with AND, OR, NOT and parentheses; SYM BETWEEN low AND high is a range.")
+	fmt.Print("Enter condition: ")
+	if !scanner.Scan() {
+		return false
+	}
+
+	condition, err := models.ParseCondition(scanner.Text())
+	if err != nil {
+		fmt.Printf("Invalid condition: %v\n", err)
+		return false
+	}
+
+	req.Symbol = ""
+	req.Condition = conditionToProto(condition)
+	return true
+}
+
+func promptExpression(scanner *bufio.Scanner, req *pb.CreateAlertRequest) bool {
+	fmt.Println("Available functions:")
+	for _, function := range rules.Functions() {
+		fmt.Printf("  %s\n", function)
+	}
+	fmt.Print("Enter rule: ")
+	if !scanner.Scan() {
+		return false
+	}
+	expression := scanner.Text()
+
+	if _, err := rules.Compile(expression); err != nil {
+		var ruleErr *rules.Error
+		if errors.As(err, &ruleErr) {
+			fmt.Printf("            %s^\n", strings.Repeat(" ", ruleErr.Col-1))
+		}
+		fmt.Printf("Invalid rule: %v\n", err)
+		return false
+	}
+
+	req.Symbol = ""
+	req.Expression = expression
+	return true
+}
+
+func promptIndicator(scanner *bufio.Scanner, req *pb.CreateAlertRequest) bool {
+	fmt.Println("Select indicator:")
+	fmt.Println("1. Moving average crossover (e.g., sma(50) crosses above sma(200))")
+	fmt.Println("2. RSI crossing a level (e.g., rsi(14) rises above 70)")
+	fmt.Println("3. Price leaving the Bollinger bands (e.g., bollinger(20, 2))")
+	fmt.Print("Enter choice (1-3): ")
+
+	if !scanner.Scan() {
+		return false
+	}
+
+	req.Indicator = &pb.Indicator{}
+	switch strings.TrimSpace(scanner.Text()) {
+	case "1":
+		req.Kind = pb.AlertKind_ALERT_KIND_MA_CROSSOVER
+		fmt.Print("Enter average (sma or ema; default sma): ")
+		if !scanner.Scan() {
+			return false
+		}
+		switch strings.ToLower(strings.TrimSpace(scanner.Text())) {
+		case "", "sma":
+			req.Indicator.Average = pb.MovingAverage_MOVING_AVERAGE_SMA
+		case "ema":
+			req.Indicator.Average = pb.MovingAverage_MOVING_AVERAGE_EMA
+		default:
+			fmt.Println("Invalid average")
+			return false
+		}
+		if !promptPeriod(scanner, "fast period in bars (e.g., 50)", &req.Indicator.Period) ||
+			!promptPeriod(scanner, "slow period in bars (e.g., 200)", &req.Indicator.SlowPeriod) {
+			return false
+		}
+	case "2":
+		req.Kind = pb.AlertKind_ALERT_KIND_RSI
+		if !promptPeriod(scanner, "period in bars (e.g., 14)", &req.Indicator.Period) {
+			return false
+		}
+		fmt.Print("Enter RSI level (e.g., 70): ")
+		if !scanner.Scan() {
+			return false
+		}
+		level, err := strconv.ParseFloat(strings.TrimSpace(scanner.Text()), 64)
+		if err != nil {
+			fmt.Printf("Invalid level: %v\n", err)
+			return false
+		}
+		req.Threshold = level
+	case "3":
+		req.Kind = pb.AlertKind_ALERT_KIND_BOLLINGER
+		if !promptPeriod(scanner, "period in bars (e.g., 20)", &req.Indicator.Period) {
+			return false
+		}
+		fmt.Print("Enter band width in standard deviations (default 2): ")
+		if !scanner.Scan() {
+			return false
+		}
+		if width := strings.TrimSpace(scanner.Text()); width != "" {
+			stdDevs, err := strconv.ParseFloat(width, 64)
+			if err != nil {
+				fmt.Printf("Invalid width: %v\n", err)
+				return false
+			}
+			req.Indicator.StdDevs = stdDevs
+		}
+	default:
+		fmt.Println("Invalid choice")
+		return false
+	}
+
+	fmt.Print("Enter bar interval (e.g., 1m, 1h; blank to use every tick): ")
+	if !scanner.Scan() {
+		return false
+	}
+	if value := strings.TrimSpace(scanner.Text()); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil {
+			fmt.Printf("Invalid interval: %v\n", err)
+			return false
+		}
+		req.Indicator.Interval = durationpb.New(interval)
+	}
+
+	fmt.Println("Select direction:")
+	fmt.Println("1. Either direction")
+	fmt.Println("2. Up only (crosses above, rises above, closes above the upper band)")
+	fmt.Println("3. Down only (crosses below, falls below, closes below the lower band)")
+	fmt.Print("Enter choice (1-3): ")
+
+	if !scanner.Scan() {
+		return false
+	}
+
+	switch strings.TrimSpace(scanner.Text()) {
+	case "1":
+		req.Direction = pb.MoveDirection_MOVE_DIRECTION_EITHER
+	case "2":
+		req.Direction = pb.MoveDirection_MOVE_DIRECTION_UP
+	case "3":
+		req.Direction = pb.MoveDirection_MOVE_DIRECTION_DOWN
+	default:
+		fmt.Println("Invalid choice")
+		return false
+	}
+
+	return true
+}
+
+func promptPeriod(scanner *bufio.Scanner, label string, period *uint32) bool {
+	fmt.Printf("Enter %s: ", label)
+	if !scanner.Scan() {
+		return false
+	}
+
+	value, err := strconv.ParseUint(strings.TrimSpace(scanner.Text()), 10, 32)
+	if err != nil {
+		fmt.Printf("Invalid period: %v\n", err)
+		return false
+	}
+	*period = uint32(value)
+	return true
 }
 
 func listAlerts(client pb.CryptoAlertServiceClient) {
@@ -233,13 +787,29 @@ func listAlerts(client pb.CryptoAlertServiceClient) {
 
 		fmt.Printf("%d. %s\n", i+1, status)
 		fmt.Printf("   ID: %s\n", alert.Id)
-		fmt.Printf("   Rule: %s %s $%.2f\n", alert.Symbol, 
-			comparatorToString(alert.Comparator), alert.Threshold)
+		fmt.Printf("   Rule: %s\n", ruleToString(alert))
+		fmt.Printf("   Severity: %s\n", severityToString(alert.Severity))
+
+		if len(alert.Labels) > 0 {
+			fmt.Printf("   Labels: %s\n", strings.Join(alert.Labels, ", "))
+		}
 		
 		if alert.Note != "" {
 			fmt.Printf("   Note: %s\n", alert.Note)
 		}
+
+		if alert.OwnerId != currentUser {
+			fmt.Printf("   Owner: %s\n", alert.OwnerId)
+		}
+
+		if alert.WebhookUrl != "" {
+			fmt.Printf("   Webhook: %s\n", alert.WebhookUrl)
+		}
 		
+		if limits := limitsToString(alert); limits != "" {
+			fmt.Printf("   Limits: %s\n", limits)
+		}
+
 		if alert.LastTrigger != nil {
 			lastTrigger := alert.LastTrigger.AsTime().Format("2006-01-02 15:04:05")
 			fmt.Printf("   Last triggered: %s\n", lastTrigger)
@@ -265,14 +835,16 @@ func deleteAlert(client pb.CryptoAlertServiceClient, alertID string) {
 	fmt.Println("Alert deleted successfully!")
 }
 
-func watchAlerts(client pb.CryptoAlertServiceClient) {
+// lastSequence is the newest trigger seen in this session; later
+// watch-alerts calls resume from it so nothing fired in between is missed.
+var lastSequence uint64
+
+func watchAlerts(client pb.CryptoAlertServiceClient, req *pb.AlertSubscriptionRequest) {
 	fmt.Println("Watching for alert triggers (Press Ctrl+C to stop)")
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	req := &pb.AlertSubscriptionRequest{}
-
 	stream, err := client.SubscribeAlerts(ctx, req)
 	if err != nil {
 		log.Printf("Error subscribing to alerts: %v", err)
@@ -286,14 +858,31 @@ func watchAlerts(client pb.CryptoAlertServiceClient) {
 			break
 		}
 
+		if trigger.Sequence > lastSequence {
+			lastSequence = trigger.Sequence
+		}
+
 		timestamp := trigger.Timestamp.AsTime().Format("15:04:05")
 		alert := trigger.Alert
-		
-		fmt.Printf("\n🚨 ALERT TRIGGERED! [%s]\n", timestamp)
+
+		switch trigger.Event {
+		case pb.TriggerEvent_TRIGGER_EVENT_FEED_STALE:
+			fmt.Printf("\n⚠️  FEED STALE [%s] #%d: %s\n", timestamp, trigger.Sequence, ruleToString(alert))
+			fmt.Printf("Last price: $%.2f\n%s\n", trigger.TriggeredPrice, alert.Note)
+			fmt.Println(strings.Repeat("-", 40))
+			continue
+		case pb.TriggerEvent_TRIGGER_EVENT_FEED_RECOVERED:
+			fmt.Printf("\n✅ FEED RECOVERED [%s] #%d: %s at $%.2f\n", timestamp, trigger.Sequence, alert.Symbol, trigger.TriggeredPrice)
+			fmt.Println(alert.Note)
+			fmt.Println(strings.Repeat("-", 40))
+			continue
+		}
+
+		fmt.Printf("\n🚨 ALERT TRIGGERED! [%s] #%d\n", timestamp, trigger.Sequence)
 		fmt.Printf("Symbol: %s\n", alert.Symbol)
-		fmt.Printf("Rule: %s %s $%.2f\n", alert.Symbol, 
-			comparatorToString(alert.Comparator), alert.Threshold)
+		fmt.Printf("Rule: %s\n", ruleToString(alert))
 		fmt.Printf("Triggered at: $%.2f\n", trigger.TriggeredPrice)
+		fmt.Printf("Severity: %s\n", severityToString(alert.Severity))
 		if alert.Note != "" {
 			fmt.Printf("Note: %s\n", alert.Note)
 		}
@@ -301,6 +890,248 @@ func watchAlerts(client pb.CryptoAlertServiceClient) {
 	}
 }
 
+func parseAlertFilters(args []string) (*pb.AlertSubscriptionRequest, error) {
+	req := &pb.AlertSubscriptionRequest{}
+
+	for _, arg := range args {
+		key, value, found := strings.Cut(arg, "=")
+		if !found || value == "" {
+			return nil, fmt.Errorf("invalid filter %q", arg)
+		}
+
+		switch key {
+		case "symbols":
+			req.Symbols = strings.Split(strings.ToUpper(value), ",")
+		case "ids":
+			req.AlertIds = strings.Split(value, ",")
+		case "labels":
+			req.Labels = strings.Split(value, ",")
+		case "severity":
+			severity, err := parseSeverity(value)
+			if err != nil {
+				return nil, err
+			}
+			req.MinSeverity = severity
+		case "resume":
+			sequence, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid resume sequence %q", value)
+			}
+			req.ResumeFromSequence = &sequence
+		default:
+			return nil, fmt.Errorf("unknown filter %q", key)
+		}
+	}
+
+	return req, nil
+}
+
+// parseAlertLimits reads cooldown=<duration>, expires=<duration from now or
+// RFC 3339 time>, max=<count> and once.
+func parseAlertLimits(args []string, req *pb.CreateAlertRequest) error {
+	for _, arg := range args {
+		if arg == "once" {
+			req.FireOnce = true
+			continue
+		}
+
+		key, value, found := strings.Cut(arg, "=")
+		if !found || value == "" {
+			return fmt.Errorf("invalid limit %q", arg)
+		}
+
+		switch key {
+		case "cooldown":
+			cooldown, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("invalid cooldown %q", value)
+			}
+			req.Cooldown = durationpb.New(cooldown)
+		case "expires":
+			expiresAt, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				after, durationErr := time.ParseDuration(value)
+				if durationErr != nil {
+					return fmt.Errorf("invalid expiry %q: expected a duration or an RFC 3339 time", value)
+				}
+				expiresAt = time.Now().Add(after)
+			}
+			req.ExpiresAt = timestamppb.New(expiresAt)
+		case "max":
+			maxFires, err := strconv.ParseInt(value, 10, 32)
+			if err != nil || maxFires < 0 {
+				return fmt.Errorf("invalid max fire count %q", value)
+			}
+			req.MaxFires = int32(maxFires)
+		default:
+			return fmt.Errorf("unknown limit %q", key)
+		}
+	}
+
+	return nil
+}
+
+func limitsToString(alert *pb.Alert) string {
+	var limits []string
+	if alert.Cooldown != nil {
+		limits = append(limits, fmt.Sprintf("cooldown %v", alert.Cooldown.AsDuration()))
+	}
+	if alert.ExpiresAt != nil {
+		limits = append(limits, "expires "+alert.ExpiresAt.AsTime().Local().Format("2006-01-02 15:04:05"))
+	}
+	switch {
+	case alert.FireOnce:
+		limits = append(limits, fmt.Sprintf("fires once (%d fired)", alert.FireCount))
+	case alert.MaxFires > 0:
+		limits = append(limits, fmt.Sprintf("fired %d of %d", alert.FireCount, alert.MaxFires))
+	}
+	return strings.Join(limits, ", ")
+}
+
+func parseHistoryFilters(args []string) (*pb.ListTriggersRequest, error) {
+	req := &pb.ListTriggersRequest{PageSize: 20}
+
+	for _, arg := range args {
+		key, value, found := strings.Cut(arg, "=")
+		if !found || value == "" {
+			return nil, fmt.Errorf("invalid filter %q", arg)
+		}
+
+		switch key {
+		case "alert":
+			req.AlertId = value
+		case "symbol":
+			req.Symbol = strings.ToUpper(value)
+		case "since":
+			since, err := time.ParseDuration(value)
+			if err != nil || since <= 0 {
+				return nil, fmt.Errorf("invalid duration %q (e.g., 30m, 24h)", value)
+			}
+			req.From = timestamppb.New(time.Now().Add(-since))
+		case "from", "to":
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid time %q: expected RFC 3339, e.g. 2025-01-01T00:00:00Z", value)
+			}
+			if key == "from" {
+				req.From = timestamppb.New(t)
+			} else {
+				req.To = timestamppb.New(t)
+			}
+		case "limit":
+			limit, err := strconv.ParseUint(value, 10, 32)
+			if err != nil || limit == 0 {
+				return nil, fmt.Errorf("invalid limit %q", value)
+			}
+			req.PageSize = uint32(limit)
+		case "page":
+			req.PageToken = value
+		default:
+			return nil, fmt.Errorf("unknown filter %q", key)
+		}
+	}
+
+	return req, nil
+}
+
+func showTriggerHistory(client pb.CryptoAlertServiceClient, req *pb.ListTriggersRequest) {
+	resp, err := client.ListTriggers(context.Background(), req)
+	if err != nil {
+		log.Printf("Error listing triggers: %v", err)
+		return
+	}
+
+	if len(resp.Triggers) == 0 {
+		fmt.Println("No triggers found")
+		return
+	}
+
+	fmt.Printf("%-19s %8s  %-36s %14s  %s\n", "Time", "Seq", "Alert", "Price", "Condition")
+	for _, record := range resp.Triggers {
+		trigger := record.Trigger
+		fmt.Printf("%-19s %8d  %-36s %14.2f  %s\n", trigger.Timestamp.AsTime().Local().Format("2006-01-02 15:04:05"),
+			trigger.Sequence, trigger.Alert.Id, trigger.TriggeredPrice, record.Condition)
+	}
+
+	if resp.NextPageToken != "" {
+		fmt.Printf("\nMore triggers: add page=%s to the same command\n", resp.NextPageToken)
+	}
+}
+
+func parseSeverity(value string) (pb.Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "info":
+		return pb.Severity_SEVERITY_INFO, nil
+	case "warning":
+		return pb.Severity_SEVERITY_WARNING, nil
+	case "critical":
+		return pb.Severity_SEVERITY_CRITICAL, nil
+	default:
+		return pb.Severity_SEVERITY_UNSPECIFIED, fmt.Errorf("invalid severity %q", value)
+	}
+}
+
+func severityToString(severity pb.Severity) string {
+	switch severity {
+	case pb.Severity_SEVERITY_WARNING:
+		return "warning"
+	case pb.Severity_SEVERITY_CRITICAL:
+		return "critical"
+	default:
+		return "info"
+	}
+}
+
+func ruleToString(alert *pb.Alert) string {
+	rule := baseRuleToString(alert)
+	if alert.Source != "" {
+		rule += " on " + alert.Source
+	}
+	return rule
+}
+
+func baseRuleToString(alert *pb.Alert) string {
+	if alert.Kind == pb.AlertKind_ALERT_KIND_COMPOUND {
+		return conditionFromProto(alert.Condition).String()
+	}
+
+	if alert.Kind == pb.AlertKind_ALERT_KIND_EXPRESSION {
+		return alert.Expression
+	}
+
+	switch alert.Kind {
+	case pb.AlertKind_ALERT_KIND_MA_CROSSOVER, pb.AlertKind_ALERT_KIND_RSI, pb.AlertKind_ALERT_KIND_BOLLINGER:
+		return indicatorRuleToString(alert)
+	}
+
+	if alert.Kind == pb.AlertKind_ALERT_KIND_FEED_SILENCE {
+		return fmt.Sprintf("%s silent for %v", alert.Symbol, alert.Window.AsDuration())
+	}
+
+	if alert.Kind == pb.AlertKind_ALERT_KIND_PERCENT_MOVE {
+		sign := "±"
+		switch alert.Direction {
+		case pb.MoveDirection_MOVE_DIRECTION_UP:
+			sign = "+"
+		case pb.MoveDirection_MOVE_DIRECTION_DOWN:
+			sign = "-"
+		}
+		return fmt.Sprintf("%s moves %s%.2f%% within %v", alert.Symbol, sign,
+			alert.Threshold, alert.Window.AsDuration())
+	}
+
+	switch alert.Mode {
+	case pb.TriggerMode_TRIGGER_MODE_CROSS_UP:
+		return fmt.Sprintf("%s crosses above $%.2f", alert.Symbol, alert.Threshold)
+	case pb.TriggerMode_TRIGGER_MODE_CROSS_DOWN:
+		return fmt.Sprintf("%s crosses below $%.2f", alert.Symbol, alert.Threshold)
+	case pb.TriggerMode_TRIGGER_MODE_CROSS_ANY:
+		return fmt.Sprintf("%s crosses $%.2f", alert.Symbol, alert.Threshold)
+	}
+
+	return fmt.Sprintf("%s %s $%.2f", alert.Symbol, comparatorToString(alert.Comparator), alert.Threshold)
+}
+
 func comparatorToString(comp pb.Comparator) string {
 	switch comp {
 	case pb.Comparator_COMPARATOR_GT:
@@ -317,3 +1148,92 @@ func comparatorToString(comp pb.Comparator) string {
 		return "unknown"
 	}
 }
+
+var conditionOps = map[models.ConditionOp]pb.ConditionOp{
+	models.ConditionCompare: pb.ConditionOp_CONDITION_OP_COMPARE,
+	models.ConditionAnd:     pb.ConditionOp_CONDITION_OP_AND,
+	models.ConditionOr:      pb.ConditionOp_CONDITION_OP_OR,
+	models.ConditionNot:     pb.ConditionOp_CONDITION_OP_NOT,
+}
+
+var comparators = map[models.Comparator]pb.Comparator{
+	models.ComparatorGT:  pb.Comparator_COMPARATOR_GT,
+	models.ComparatorGTE: pb.Comparator_COMPARATOR_GTE,
+	models.ComparatorLT:  pb.Comparator_COMPARATOR_LT,
+	models.ComparatorLTE: pb.Comparator_COMPARATOR_LTE,
+	models.ComparatorEQ:  pb.Comparator_COMPARATOR_EQ,
+}
+
+func conditionToProto(condition *models.Condition) *pb.Condition {
+	pbCondition := &pb.Condition{
+		Op:         conditionOps[condition.Op],
+		Symbol:     condition.Symbol,
+		Comparator: comparators[condition.Comparator],
+		Threshold:  condition.Threshold,
+	}
+	for _, child := range condition.Children {
+		pbCondition.Children = append(pbCondition.Children, conditionToProto(child))
+	}
+	return pbCondition
+}
+
+func conditionFromProto(pbCondition *pb.Condition) *models.Condition {
+	if pbCondition == nil {
+		return nil
+	}
+
+	condition := &models.Condition{Symbol: pbCondition.Symbol, Threshold: pbCondition.Threshold}
+	for op, pbOp := range conditionOps {
+		if pbOp == pbCondition.Op {
+			condition.Op = op
+		}
+	}
+	for comparator, pbComparator := range comparators {
+		if pbComparator == pbCondition.Comparator {
+			condition.Comparator = comparator
+		}
+	}
+	for _, child := range pbCondition.Children {
+		condition.Children = append(condition.Children, conditionFromProto(child))
+	}
+	return condition
+}
+
+func envOrDefault(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists && value != "" {
+		return value
+	}
+	return fallback
+}
+
+func indicatorRuleToString(alert *pb.Alert) string {
+	indicator := alert.Indicator
+	if indicator == nil {
+		return alert.Symbol + " indicator"
+	}
+
+	direction := models.DirectionEither
+	switch alert.Direction {
+	case pb.MoveDirection_MOVE_DIRECTION_UP:
+		direction = models.DirectionUp
+	case pb.MoveDirection_MOVE_DIRECTION_DOWN:
+		direction = models.DirectionDown
+	}
+
+	average := models.MovingAverageSMA
+	if indicator.Average == pb.MovingAverage_MOVING_AVERAGE_EMA {
+		average = models.MovingAverageEMA
+	}
+	interval := indicator.Interval.AsDuration()
+
+	var rule *models.Alert
+	switch alert.Kind {
+	case pb.AlertKind_ALERT_KIND_MA_CROSSOVER:
+		rule = models.NewMACrossoverAlert(alert.Symbol, average, int(indicator.Period), int(indicator.SlowPeriod), interval, direction, "")
+	case pb.AlertKind_ALERT_KIND_RSI:
+		rule = models.NewRSIAlert(alert.Symbol, int(indicator.Period), alert.Threshold, interval, direction, "")
+	default:
+		rule = models.NewBollingerAlert(alert.Symbol, int(indicator.Period), indicator.StdDevs, interval, direction, "")
+	}
+	return rule.Rule()
+}
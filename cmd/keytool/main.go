@@ -0,0 +1,157 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"crypto-price-alerts/internal/auth"
+)
+
+const usage = `Usage: keytool [-keys keys.json] <command> [flags]
+
+Commands:
+  add-api-key   -name <name> -user <user> [-role user|admin]
+  add-token-key -id <id>
+  issue-token   -user <user> [-role user|admin] [-ttl 24h] [-key-id <id>]
+`
+
+func main() {
+	keysPath := flag.String("keys", envOrDefault("AUTH_KEYS_FILE", "keys.json"), "Key file to read and update [$AUTH_KEYS_FILE]")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	keys, err := loadOrCreate(*keysPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	command, args := flag.Arg(0), flag.Args()[1:]
+	switch command {
+	case "add-api-key":
+		err = addAPIKey(keys, *keysPath, args)
+	case "add-token-key":
+		err = addTokenKey(keys, *keysPath, args)
+	case "issue-token":
+		err = issueToken(keys, args)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func loadOrCreate(path string) (*auth.KeyFile, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return &auth.KeyFile{}, nil
+	}
+	return auth.LoadKeyFile(path)
+}
+
+func addAPIKey(keys *auth.KeyFile, path string, args []string) error {
+	fs := flag.NewFlagSet("add-api-key", flag.ExitOnError)
+	name := fs.String("name", "", "Label for the key")
+	user := fs.String("user", "", "User the key acts as")
+	role := fs.String("role", string(auth.RoleUser), "Role: user or admin")
+	fs.Parse(args)
+
+	if *name == "" || *user == "" {
+		return fmt.Errorf("-name and -user are required")
+	}
+	parsedRole, err := auth.ParseRole(*role)
+	if err != nil {
+		return err
+	}
+
+	key, err := auth.GenerateAPIKey()
+	if err != nil {
+		return err
+	}
+
+	if err := keys.AddAPIKey(auth.APIKey{
+		Name:   *name,
+		User:   *user,
+		Role:   parsedRole,
+		SHA256: auth.HashAPIKey(key),
+	}); err != nil {
+		return err
+	}
+
+	if err := keys.Save(path); err != nil {
+		return err
+	}
+
+	// Only the hash is stored, so this is the one chance to see the key.
+	fmt.Println(key)
+	return nil
+}
+
+func addTokenKey(keys *auth.KeyFile, path string, args []string) error {
+	fs := flag.NewFlagSet("add-token-key", flag.ExitOnError)
+	id := fs.String("id", time.Now().Format("2006-01-02"), "Key ID embedded in issued tokens")
+	fs.Parse(args)
+
+	secret, err := auth.GenerateTokenSecret()
+	if err != nil {
+		return err
+	}
+
+	if err := keys.AddTokenKey(auth.TokenKey{ID: *id, Secret: secret}); err != nil {
+		return err
+	}
+
+	if err := keys.Save(path); err != nil {
+		return err
+	}
+
+	fmt.Printf("Added token key %s\n", *id)
+	return nil
+}
+
+func issueToken(keys *auth.KeyFile, args []string) error {
+	fs := flag.NewFlagSet("issue-token", flag.ExitOnError)
+	user := fs.String("user", "", "Token subject")
+	role := fs.String("role", string(auth.RoleUser), "Role: user or admin")
+	ttl := fs.Duration("ttl", 24*time.Hour, "Token lifetime")
+	keyID := fs.String("key-id", "", "Token key to sign with (default: newest)")
+	fs.Parse(args)
+
+	if *user == "" {
+		return fmt.Errorf("-user is required")
+	}
+	parsedRole, err := auth.ParseRole(*role)
+	if err != nil {
+		return err
+	}
+	if len(keys.TokenKeys) == 0 {
+		return fmt.Errorf("no token keys; run add-token-key first")
+	}
+	if *keyID == "" {
+		*keyID = keys.TokenKeys[len(keys.TokenKeys)-1].ID
+	}
+
+	token, err := keys.IssueToken(*keyID, auth.Identity{UserID: *user, Role: parsedRole}, *ttl, time.Now())
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(token)
+	return nil
+}
+
+func envOrDefault(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists && value != "" {
+		return value
+	}
+	return fallback
+}
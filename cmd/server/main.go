@@ -2,43 +2,117 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
 	pb "crypto-price-alerts/api/gen/crypto-price-alerts/api/gen"
 	"crypto-price-alerts/internal/alerts"
+	"crypto-price-alerts/internal/auth"
+	"crypto-price-alerts/internal/candles"
+	"crypto-price-alerts/internal/config"
 	"crypto-price-alerts/internal/datafeed"
+	"crypto-price-alerts/internal/health"
+	"crypto-price-alerts/internal/history"
+	"crypto-price-alerts/internal/notify"
 	grpchandlers "crypto-price-alerts/internal/grpc"
+	"crypto-price-alerts/internal/metrics"
 	"crypto-price-alerts/internal/pubsub"
+	"crypto-price-alerts/internal/triggerhistory"
+	"crypto-price-alerts/pkg/models"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
-const (
-	port = ":9090"
-	tickRate = 200 * time.Millisecond
-	alertCooldown = 30 * time.Second
-)
-
 func main() {
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
 	log.Println("Starting Crypto Price Alert Engine...")
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 
-	broker := pubsub.NewBroker()
-	alertStore := alerts.NewStore()
-	triggerBus := alerts.NewTriggerBus()
-	alertEngine := alerts.NewEngine(alertStore, triggerBus, alertCooldown)
+	broker := pubsub.NewBrokerWithConfig(cfg.BrokerConfig())
+	alertStore, err := newAlertStore(cfg.Store.Backend, cfg.Store.DataDir)
+	if err != nil {
+		log.Fatalf("Failed to open alert store: %v", err)
+	}
+	defer alertStore.Close()
+	triggerLog, err := newTriggerLog(cfg.Store.Backend, cfg.Store.DataDir, cfg.Triggers.LogSize)
+	if err != nil {
+		log.Fatalf("Failed to open trigger log: %v", err)
+	}
+	defer triggerLog.Close()
+	triggerBus := alerts.NewTriggerBusWithConfig(triggerLog, cfg.TriggerBusConfig())
+	alertEngine := alerts.NewEngineWithConfig(alertStore, triggerBus, cfg.EngineConfig())
+
+	var watchdog *alerts.Watchdog
+	if cfg.Watchdog.StaleAfter > 0 {
+		watchdog = alerts.NewWatchdog(triggerBus, cfg.WatchdogConfig())
+	}
+
+	deadLetters, err := newDeadLetterLog(cfg.Store.Backend, cfg.Store.DataDir)
+	if err != nil {
+		log.Fatalf("Failed to open webhook dead letter log: %v", err)
+	}
+	defer deadLetters.Close()
+	notifier := notify.NewNotifier(triggerBus, cfg.NotifierConfig(), deadLetters)
+
+	triggerHistory, err := triggerhistory.NewStore(triggerBus, cfg.TriggerHistoryConfig())
+	if err != nil {
+		log.Fatalf("Failed to open trigger history: %v", err)
+	}
 
-	symbols := []string{"BTC", "ETH", "ADA", "SOL", "DOT", "MATIC", "AVAX", "LINK"}
-	binanceFeed := datafeed.NewBinanceDataFeed(symbols)
+	feed, err := datafeed.New(cfg.Feed.Name, cfg.FeedConfig())
+	if err != nil {
+		log.Fatalf("Failed to create data feed: %v", err)
+	}
+
+	var tickRecorder *datafeed.Recorder
+	if cfg.Recorder.Dir != "" {
+		tickRecorder, err = datafeed.NewRecorder(cfg.RecorderConfig())
+		if err != nil {
+			log.Fatalf("Failed to open tick recorder: %v", err)
+		}
+	}
+
+	// With several venues, alerts and price streams follow the consolidated
+	// price unless they name a venue.
+	defaultSource := ""
+	if _, aggregated := feed.(*datafeed.AggregatorFeed); aggregated {
+		defaultSource = models.SourceConsolidated
+	}
+	alertEngine.SetDefaultSource(defaultSource)
+
+	candleAggregator := candles.NewAggregator(cfg.CandlesConfig())
+	candleAggregator.SetDefaultSource(defaultSource)
+
+	priceHistory, err := history.NewStore(cfg.HistoryConfig())
+	if err != nil {
+		log.Fatalf("Failed to open price history: %v", err)
+	}
+	priceHistory.SetDefaultSource(defaultSource)
+
+	checker := newHealthChecker(cfg, feed, broker, alertEngine, triggerBus)
 
 
 	log.Println("Starting services...")
@@ -51,38 +125,91 @@ func main() {
 		log.Fatalf("Failed to start trigger bus: %v", err)
 	}
 
+	if err := notifier.Start(ctx); err != nil {
+		log.Fatalf("Failed to start webhook notifier: %v", err)
+	}
+
+	if err := triggerHistory.Start(ctx); err != nil {
+		log.Fatalf("Failed to start trigger history: %v", err)
+	}
+
+	if watchdog != nil {
+		if err := watchdog.Start(ctx); err != nil {
+			log.Fatalf("Failed to start feed watchdog: %v", err)
+		}
+	}
+
 	if err := alertEngine.Start(ctx); err != nil {
 		log.Fatalf("Failed to start alert engine: %v", err)
 	}
 
-	if err := binanceFeed.Start(ctx); err != nil {
-		log.Fatalf("Failed to start Binance data feed: %v", err)
+	if err := candleAggregator.Start(ctx); err != nil {
+		log.Fatalf("Failed to start candle aggregator: %v", err)
+	}
+
+	if err := priceHistory.Start(ctx); err != nil {
+		log.Fatalf("Failed to start price history: %v", err)
+	}
+
+	if tickRecorder != nil {
+		if err := tickRecorder.Start(ctx); err != nil {
+			log.Fatalf("Failed to start tick recorder: %v", err)
+		}
+	}
+
+	if err := feed.Start(ctx); err != nil {
+		log.Fatalf("Failed to start %s data feed: %v", cfg.Feed.Name, err)
 	}
 
 	go func() {
-		for tick := range binanceFeed.TickChannel() {
+		for tick := range feed.TickChannel() {
+			if tickRecorder != nil {
+				tickRecorder.Record(tick)
+			}
+			metrics.TicksReceived.WithLabelValues(tick.Symbol, tick.Source).Inc()
+			checker.ObserveTick(tick)
+			if watchdog != nil {
+				watchdog.ObserveTick(tick)
+			}
 			broker.Publish(tick)
+			candleAggregator.ObserveTick(tick)
+			priceHistory.Record(tick)
 			alertEngine.ProcessTick(tick)
 		}
 	}()
 
-	log.Printf("Attempting to bind to port %s...", port)
-	lis, err := net.Listen("tcp", port)
+	log.Printf("Attempting to bind to %s...", cfg.Server.Address)
+	lis, err := net.Listen("tcp", cfg.Server.Address)
 	if err != nil {
-		log.Fatalf("Failed to listen on %s: %v", port, err)
+		log.Fatalf("Failed to listen on %s: %v", cfg.Server.Address, err)
 	}
 	log.Printf("Successfully bound to %s", lis.Addr().String())
 
-	grpcServer := grpc.NewServer()
+	serverOpts, err := securityOptions(cfg.Server.TLSCert, cfg.Server.TLSKey, cfg.Server.TLSClientCA, cfg.Server.AuthKeys)
+	if err != nil {
+		log.Fatalf("Failed to configure server security: %v", err)
+	}
+	grpcServer := grpc.NewServer(serverOpts...)
 
-	cryptoMarketDataServer := grpchandlers.NewCryptoMarketDataServer(broker)
-	cryptoAlertServiceServer := grpchandlers.NewCryptoAlertServiceServer(alertStore, triggerBus)
+	cryptoMarketDataServer := grpchandlers.NewCryptoMarketDataServer(broker, candleAggregator, priceHistory)
+	cryptoMarketDataServer.SetDefaultSource(defaultSource)
+	cryptoAlertServiceServer := grpchandlers.NewCryptoAlertServiceServer(alertStore, triggerBus, triggerHistory)
+	cryptoAlertServiceServer.SetWebhookHosts(cfg.Webhooks.AlertHosts)
+	cryptoAlertServiceServer.SetClock(alertEngine.Clock())
 
 	pb.RegisterCryptoMarketDataServer(grpcServer, cryptoMarketDataServer)
 	pb.RegisterCryptoAlertServiceServer(grpcServer, cryptoAlertServiceServer)
 
+	healthServer := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	go checker.SyncGRPC(ctx, healthServer, cfg.Health.CheckInterval,
+		pb.CryptoMarketData_ServiceDesc.ServiceName, pb.CryptoAlertService_ServiceDesc.ServiceName)
+
 	reflection.Register(grpcServer)
 
+	registerMetrics(cfg, broker, alertEngine, triggerBus, notifier, watchdog, candleAggregator, priceHistory, triggerHistory, tickRecorder)
+	httpServer := startHTTPServer(cfg.Server.HTTPAddress, checker)
+
 
 	go func() {
 		log.Printf("🚀 gRPC server starting on %s", lis.Addr().String())
@@ -92,7 +219,7 @@ func main() {
 	}()
 
 	go func() {
-		ticker := time.NewTicker(5 * time.Minute)
+		ticker := time.NewTicker(cfg.Engine.CleanupInterval)
 		defer ticker.Stop()
 
 		for {
@@ -101,14 +228,16 @@ func main() {
 				return
 			case <-ticker.C:
 				alertEngine.CleanupCooldowns()
+				alertEngine.DisableExpired()
 			}
 		}
 	}()
 
 	log.Printf("Server started successfully!")
-	log.Printf("Real-time Binance WebSocket connected!")
-	log.Printf("Available crypto symbols: %v", symbols)
-	log.Printf("Alert cooldown: %v", alertCooldown)
+	log.Printf("Data feed: %s (%s)", cfg.Feed.Name, feed.Status())
+	log.Printf("Available crypto symbols: %v", cfg.Feed.Symbols)
+	log.Printf("Alert cooldown: %v", cfg.Engine.Cooldown)
+	log.Printf("Alert storage: %s", cfg.Store.Backend)
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -116,12 +245,189 @@ func main() {
 
 	log.Println("Shutting down server...")
 
+	healthServer.Shutdown()
 	grpcServer.GracefulStop()
+	if httpServer != nil {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		httpServer.Shutdown(shutdownCtx)
+		shutdownCancel()
+	}
 	
-	binanceFeed.Stop()
+	feed.Stop()
+	if tickRecorder != nil {
+		tickRecorder.Stop()
+	}
 	alertEngine.Stop()
+	candleAggregator.Stop()
+	priceHistory.Stop()
+	if watchdog != nil {
+		watchdog.Stop()
+	}
+	notifier.Stop()
+	triggerHistory.Stop()
 	triggerBus.Stop()
 	broker.Stop()
 
 	log.Println("Server stopped")
 }
+
+// registerMetrics exports the components' queue depths, subscriber counts
+// and webhook statistics alongside the metrics they record themselves.
+func registerMetrics(cfg *config.Config, broker *pubsub.Broker, engine *alerts.Engine, triggerBus *alerts.TriggerBus, notifier *notify.Notifier, watchdog *alerts.Watchdog, candleAggregator *candles.Aggregator, priceHistory *history.Store, triggerHistory *triggerhistory.Store, tickRecorder *datafeed.Recorder) {
+	metrics.RegisterQueue(metrics.StageBroker, broker.QueuedTicks, cfg.Broker.TickBuffer)
+	metrics.RegisterQueue(metrics.StageEngine, func() int { return engine.GetStats().QueuedTicks }, cfg.Engine.TickBuffer)
+	metrics.RegisterQueue("triggers", func() int { return triggerBus.GetStats().QueuedTriggers }, cfg.Triggers.Buffer)
+
+	metrics.RegisterSubscribers("price", broker.GetSubscriberCount)
+	metrics.RegisterSubscribers("alert", triggerBus.GetSubscriberCount)
+	metrics.RegisterSubscribers("candle", candleAggregator.GetSubscriberCount)
+
+	metrics.RegisterCounter("webhook_deliveries_total", "Webhook requests delivered successfully.",
+		func() uint64 { return notifier.GetStats().Delivered })
+	metrics.RegisterCounter("webhook_retries_total", "Webhook requests retried after a failure.",
+		func() uint64 { return notifier.GetStats().Retries })
+	metrics.RegisterCounter("webhook_dead_letters_total", "Webhook deliveries abandoned after the last attempt.",
+		func() uint64 { return notifier.GetStats().DeadLettered })
+
+	metrics.RegisterGauge("history_points", "Ticks and bars held by the price history.",
+		func() float64 {
+			_, rawPoints, bars := priceHistory.Stats()
+			return float64(rawPoints + bars)
+		})
+
+	metrics.RegisterGauge("trigger_history_records", "Past triggers held for ListTriggers.",
+		func() float64 { return float64(triggerHistory.Len()) })
+
+	if watchdog != nil {
+		metrics.RegisterGauge("stale_symbols", "Symbols the watchdog currently reports as silent.",
+			func() float64 { return float64(len(watchdog.StaleSymbols())) })
+	}
+
+	if tickRecorder != nil {
+		metrics.RegisterQueue(metrics.StageRecorder, tickRecorder.QueuedTicks, cfg.RecorderConfig().Buffer)
+	}
+}
+
+// newHealthChecker makes the server ready only while the feed is connected,
+// symbols keep ticking and the pipeline components are running.
+func newHealthChecker(cfg *config.Config, feed datafeed.Feed, broker *pubsub.Broker, engine *alerts.Engine, triggerBus *alerts.TriggerBus) *health.Checker {
+	checker := health.NewChecker(cfg.Health.MaxTickAge)
+
+	checker.AddCheck("feed", func() error {
+		if status := feed.Status(); status != datafeed.FeedStatusConnected {
+			return fmt.Errorf("%s feed is %s", cfg.Feed.Name, status)
+		}
+		return nil
+	})
+	checker.AddCheck("broker", running(broker.IsRunning))
+	checker.AddCheck("engine", running(func() bool { return engine.GetStats().Running }))
+	checker.AddCheck("trigger_bus", running(func() bool { return triggerBus.GetStats().Running }))
+
+	return checker
+}
+
+func running(isRunning func() bool) health.Check {
+	return func() error {
+		if !isRunning() {
+			return fmt.Errorf("not running")
+		}
+		return nil
+	}
+}
+
+func startHTTPServer(addr string, checker *health.Checker) *http.Server {
+	if addr == "" {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	mux.Handle("/healthz", checker.LivenessHandler())
+	mux.Handle("/readyz", checker.ReadinessHandler())
+
+	server := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		log.Printf("HTTP server starting on %s (/metrics, /healthz, /readyz)", addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Failed to serve HTTP: %v", err)
+		}
+	}()
+
+	return server
+}
+
+func newAlertStore(backend, dataDir string) (alerts.Storage, error) {
+	switch backend {
+	case "memory":
+		return alerts.NewStore(), nil
+	case "file":
+		return alerts.NewFileStore(dataDir)
+	default:
+		return nil, fmt.Errorf("unknown store backend %q", backend)
+	}
+}
+
+// newTriggerLog keeps the trigger log next to the alerts when they are
+// persisted so that resume cursors stay valid across restarts.
+func newTriggerLog(backend, dataDir string, size int) (alerts.TriggerLog, error) {
+	switch backend {
+	case "file":
+		return alerts.NewFileTriggerLog(filepath.Join(dataDir, "triggers.log"), size)
+	default:
+		return alerts.NewMemoryTriggerLog(size), nil
+	}
+}
+
+// securityOptions enables TLS when a certificate is given and authenticates
+// callers against the key file when one is given. Without a key file the
+// server trusts the identity headers sent by clients.
+func securityOptions(certFile, keyFile, clientCAFile, keysFile string) ([]grpc.ServerOption, error) {
+	var opts []grpc.ServerOption
+
+	if certFile != "" {
+		tlsConfig, err := auth.ServerTLSConfig(certFile, keyFile, clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		log.Printf("TLS enabled (mutual TLS: %v)", clientCAFile != "")
+	} else if clientCAFile != "" {
+		return nil, fmt.Errorf("-tls-client-ca requires -tls-cert")
+	}
+
+	if keysFile == "" {
+		log.Println("Warning: Authentication disabled; trusting client identity headers")
+		return append(opts,
+			grpc.ChainUnaryInterceptor(auth.UnaryMetadataInterceptor()),
+			grpc.ChainStreamInterceptor(auth.StreamMetadataInterceptor()),
+		), nil
+	}
+
+	keys, err := auth.LoadKeyFile(keysFile)
+	if err != nil {
+		return nil, err
+	}
+	if certFile == "" {
+		log.Println("Warning: Authentication enabled without TLS; credentials are sent in plaintext")
+	}
+	log.Printf("Authentication enabled (%d API key(s), %d token key(s))", len(keys.APIKeys), len(keys.TokenKeys))
+
+	authenticator := auth.NewAuthenticator(keys)
+	// Load balancers and orchestrators probe health without credentials.
+	authenticator.AllowUnauthenticated(
+		healthpb.Health_Check_FullMethodName,
+		healthpb.Health_List_FullMethodName,
+		healthpb.Health_Watch_FullMethodName,
+	)
+	return append(opts,
+		grpc.ChainUnaryInterceptor(authenticator.UnaryInterceptor()),
+		grpc.ChainStreamInterceptor(authenticator.StreamInterceptor()),
+	), nil
+}
+
+func newDeadLetterLog(backend, dataDir string) (*notify.DeadLetterLog, error) {
+	if backend == "file" {
+		return notify.NewDeadLetterLog(filepath.Join(dataDir, "webhook_deadletters.log"), 0)
+	}
+	return notify.NewDeadLetterLog("", 0)
+}
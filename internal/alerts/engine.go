@@ -6,31 +6,88 @@ import (
 	"sync"
 	"time"
 
+	"crypto-price-alerts/internal/metrics"
+	"crypto-price-alerts/pkg/clock"
 	"crypto-price-alerts/pkg/models"
 )
 
 type Engine struct {
-	store       *Store
-	triggerBus  *TriggerBus
-	tickChan    chan *models.Tick
-	stopChan    chan struct{}
-	running     bool
-	mu          sync.RWMutex
+	store      Storage
+	triggerBus *TriggerBus
+	tickChan   chan *models.Tick
+	stopChan   chan struct{}
+	running    bool
+	mu         sync.RWMutex
+	// cooldownMap holds when each alert's current cooldown ends.
 	cooldownMap map[string]time.Time
 	cooldown    time.Duration
+	clock       clock.Clock
+	windows     map[string]*PriceWindow
+	lastPrices  map[string]float64
+	latest      map[string]float64
+	programs    map[string]*compiledRule
+	indicators  map[string]map[string]*indicatorSeries
+	defaultSrc  string
 }
 
-func NewEngine(store *Store, triggerBus *TriggerBus, cooldown time.Duration) *Engine {
+type EngineConfig struct {
+	// Cooldown is the minimum time between two triggers of the same alert,
+	// for alerts that do not set their own.
+	Cooldown time.Duration
+	// TickBuffer is how many ticks may queue for evaluation before new ones
+	// are dropped.
+	TickBuffer int
+	// Clock times triggers and cooldowns; nil uses the system clock.
+	Clock clock.Clock
+}
+
+func DefaultEngineConfig() EngineConfig {
+	return EngineConfig{
+		Cooldown:   30 * time.Second,
+		TickBuffer: 1000,
+	}
+}
+
+func NewEngine(store Storage, triggerBus *TriggerBus, cooldown time.Duration) *Engine {
+	cfg := DefaultEngineConfig()
+	cfg.Cooldown = cooldown
+	return NewEngineWithConfig(store, triggerBus, cfg)
+}
+
+func NewEngineWithConfig(store Storage, triggerBus *TriggerBus, cfg EngineConfig) *Engine {
+	if cfg.TickBuffer <= 0 {
+		cfg.TickBuffer = DefaultEngineConfig().TickBuffer
+	}
+
 	return &Engine{
 		store:       store,
 		triggerBus:  triggerBus,
-		tickChan:    make(chan *models.Tick, 1000),
+		tickChan:    make(chan *models.Tick, cfg.TickBuffer),
 		stopChan:    make(chan struct{}),
 		cooldownMap: make(map[string]time.Time),
-		cooldown:    cooldown,
+		cooldown:    cfg.Cooldown,
+		clock:       clock.OrReal(cfg.Clock),
+		windows:     make(map[string]*PriceWindow),
+		lastPrices:  make(map[string]float64),
+		latest:      make(map[string]float64),
+		programs:    make(map[string]*compiledRule),
+		indicators:  make(map[string]map[string]*indicatorSeries),
 	}
 }
 
+// SetDefaultSource sets which tick source alerts without an explicit Source
+// follow. With the default of "" they follow every tick.
+func (e *Engine) SetDefaultSource(source string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.defaultSrc = source
+}
+
+// Clock is the clock that times triggers, cooldowns and expiry.
+func (e *Engine) Clock() clock.Clock {
+	return e.clock
+}
+
 func (e *Engine) Start(ctx context.Context) error {
 	e.mu.Lock()
 	if e.running {
@@ -61,6 +118,7 @@ func (e *Engine) ProcessTick(tick *models.Tick) {
 	select {
 	case e.tickChan <- tick:
 	default:
+		metrics.TicksDropped.WithLabelValues(metrics.StageEngine).Inc()
 		log.Printf("Warning: Dropping tick for %s due to full channel", tick.Symbol)
 	}
 }
@@ -73,54 +131,257 @@ func (e *Engine) processTicks(ctx context.Context) {
 		case <-e.stopChan:
 			return
 		case tick := <-e.tickChan:
-			e.evaluateTick(tick)
+			e.EvaluateTick(tick)
 		}
 	}
 }
 
-func (e *Engine) evaluateTick(tick *models.Tick) {
-	alerts := e.store.GetEnabledBySymbol(tick.Symbol)
+// EvaluateTick evaluates a tick synchronously. Replays call it directly
+// instead of ProcessTick so that no tick is dropped.
+func (e *Engine) EvaluateTick(tick *models.Tick) {
+	start := time.Now()
+	defer func() { metrics.EvaluationDuration.Observe(time.Since(start).Seconds()) }()
+
+	e.recordLatest(tick)
+
+	alerts := e.matchingSource(e.store.GetEnabledBySymbol(tick.Symbol), tick.Source)
+	metrics.AlertsEvaluated.Add(float64(len(alerts)))
+
+	e.recordPrice(tick, alerts)
+	e.updateIndicators(tick, alerts)
+
+	now := e.clock.Now()
+	for _, alert := range alerts {
+		if e.shouldTriggerAlert(alert, tick, now) {
+			e.triggerAlert(alert, tick.Price, now)
+		}
+	}
+}
+
+func (e *Engine) matchingSource(alerts []*models.Alert, source string) []*models.Alert {
+	e.mu.RLock()
+	defaultSrc := e.defaultSrc
+	e.mu.RUnlock()
+
+	matching := alerts[:0]
+	for _, alert := range alerts {
+		switch {
+		case alert.Source != "":
+			if alert.Source == source {
+				matching = append(matching, alert)
+			}
+		case defaultSrc == "" || defaultSrc == source:
+			matching = append(matching, alert)
+		}
+	}
+
+	return matching
+}
+
+// historyKey names the prices of symbol from source; "" stands for every
+// source.
+func historyKey(symbol, source string) string {
+	return symbol + "@" + source
+}
+
+// tickHistoryKeys are the keys under which tick's price is recorded: its
+// own source, and every source.
+func tickHistoryKeys(tick *models.Tick) []string {
+	keys := []string{historyKey(tick.Symbol, tick.Source)}
+	if tick.Source != "" {
+		keys = append(keys, historyKey(tick.Symbol, ""))
+	}
+	return keys
+}
+
+// followedSource is the tick source alert is evaluated against.
+func (e *Engine) followedSource(alert *models.Alert) string {
+	if alert.Source != "" {
+		return alert.Source
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.defaultSrc
+}
+
+// recordLatest keeps the latest price of every symbol for compound alerts,
+// both per source and for whichever source ticked last.
+func (e *Engine) recordLatest(tick *models.Tick) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.latest[historyKey(tick.Symbol, tick.Source)] = tick.Price
+	e.latest[historyKey(tick.Symbol, "")] = tick.Price
+}
+
+// latestPrices looks up prices from the source alert follows.
+func (e *Engine) latestPrices(alert *models.Alert) models.PriceLookup {
+	source := e.followedSource(alert)
+
+	return func(symbol string) (float64, bool) {
+		e.mu.RLock()
+		defer e.mu.RUnlock()
+		price, ok := e.latest[historyKey(symbol, source)]
+		return price, ok
+	}
+}
+
+// historyNeed is how much of a symbol's price history alerts read: every
+// price within retention, and at least the last samples prices.
+type historyNeed struct {
+	retention time.Duration
+	samples   int
+}
 
+func (e *Engine) historyNeed(alert *models.Alert) historyNeed {
+	switch alert.Kind {
+	case models.AlertKindPercentMove:
+		return historyNeed{retention: alert.Window}
+	case models.AlertKindExpression:
+		if program := e.program(alert); program != nil {
+			return historyNeed{retention: program.Window(), samples: program.Samples()}
+		}
+	}
+	return historyNeed{}
+}
+
+// recordPrice keeps the symbol's price windows, one per followed source, as
+// long as the alerts on it need, and drops them when there are none.
+func (e *Engine) recordPrice(tick *models.Tick, alerts []*models.Alert) {
+	needs := make(map[string]historyNeed)
 	for _, alert := range alerts {
-		if e.shouldTriggerAlert(alert, tick.Price) {
-			e.triggerAlert(alert, tick.Price)
+		need := e.historyNeed(alert)
+		if need == (historyNeed{}) {
+			continue
+		}
+
+		key := historyKey(tick.Symbol, e.followedSource(alert))
+		merged := needs[key]
+		merged.retention = max(merged.retention, need.retention)
+		merged.samples = max(merged.samples, need.samples)
+		needs[key] = merged
+	}
+
+	for _, key := range tickHistoryKeys(tick) {
+		need, needed := needs[key]
+
+		e.mu.Lock()
+		window, exists := e.windows[key]
+		if !needed {
+			delete(e.windows, key)
+			e.mu.Unlock()
+			continue
+		}
+		if !exists {
+			window = NewPriceWindow()
+			e.windows[key] = window
 		}
+		e.mu.Unlock()
+
+		window.Add(tick.Price, tick.Timestamp)
+		window.PruneKeeping(tick.Timestamp, need.retention, need.samples)
 	}
 }
 
-func (e *Engine) shouldTriggerAlert(alert *models.Alert, price float64) bool {
-	if !alert.ShouldTrigger(price) {
+func (e *Engine) conditionMet(alert *models.Alert, tick *models.Tick) bool {
+	switch alert.Kind {
+	case models.AlertKindPercentMove:
+		key := historyKey(tick.Symbol, e.followedSource(alert))
+
+		e.mu.RLock()
+		window, exists := e.windows[key]
+		e.mu.RUnlock()
+
+		if !exists {
+			return false
+		}
+
+		risePct, fallPct := window.Move(tick.Timestamp, alert.Window)
+		return alert.ShouldTriggerMove(risePct, fallPct)
+	case models.AlertKindCompound:
+		return alert.ShouldTriggerCompound(e.latestPrices(alert))
+	case models.AlertKindExpression:
+		return e.evaluateExpression(alert, tick)
+	case models.AlertKindMACrossover, models.AlertKindRSI, models.AlertKindBollinger:
+		return e.indicatorFired(alert, tick)
+	default:
+		if alert.IsCrossing() {
+			previous, seen := e.swapLastPrice(alert.ID, tick.Price)
+			return seen && alert.ShouldTriggerCross(previous, tick.Price)
+		}
+		return alert.ShouldTrigger(tick.Price)
+	}
+}
+
+// swapLastPrice records price as the latest one seen by a crossing alert and
+// returns the one it replaces.
+func (e *Engine) swapLastPrice(alertID string, price float64) (float64, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	previous, seen := e.lastPrices[alertID]
+	e.lastPrices[alertID] = price
+	return previous, seen
+}
+
+func (e *Engine) shouldTriggerAlert(alert *models.Alert, tick *models.Tick, now time.Time) bool {
+	if alert.Expired(now) {
+		e.expire(alert)
 		return false
 	}
 
+	if alert.Exhausted() {
+		return false
+	}
+
+	if !e.conditionMet(alert, tick) {
+		return false
+	}
 
 	e.mu.RLock()
-	lastTrigger, exists := e.cooldownMap[alert.ID]
+	cooldownEnd, exists := e.cooldownMap[alert.ID]
 	e.mu.RUnlock()
 
-	if exists && time.Since(lastTrigger) < e.cooldown {
+	if exists && now.Before(cooldownEnd) {
 		return false
 	}
 
 	return true
 }
 
-func (e *Engine) triggerAlert(alert *models.Alert, triggeredPrice float64) {
+func (e *Engine) cooldownFor(alert *models.Alert) time.Duration {
+	if alert.Cooldown > 0 {
+		return alert.Cooldown
+	}
+	return e.cooldown
+}
+
+func (e *Engine) expire(alert *models.Alert) {
+	if _, err := e.store.Update(alert.ID, map[string]interface{}{"enabled": false}); err != nil {
+		if err != ErrAlertNotFound {
+			log.Printf("Error disabling expired alert %s: %v", alert.ID, err)
+		}
+		return
+	}
+
+	log.Printf("Alert expired: %s", alert.Rule())
+}
+
+func (e *Engine) triggerAlert(alert *models.Alert, triggeredPrice float64, now time.Time) {
 	e.mu.Lock()
-	e.cooldownMap[alert.ID] = time.Now()
+	e.cooldownMap[alert.ID] = now.Add(e.cooldownFor(alert))
 	e.mu.Unlock()
 
-
-	if err := e.store.MarkTriggered(alert.ID); err != nil {
+	if err := e.store.MarkTriggered(alert.ID, now); err != nil {
 		log.Printf("Error marking alert %s as triggered: %v", alert.ID, err)
 	}
 
-	trigger := models.NewAlertTrigger(alert, triggeredPrice)
+	trigger := models.NewAlertTrigger(alert, triggeredPrice, now)
 
 	e.triggerBus.Publish(trigger)
 
-	log.Printf("Alert triggered: %s %s %.2f (triggered at %.2f)",
-		alert.Symbol, alert.Comparator.String(), alert.Threshold, triggeredPrice)
+	log.Printf("Alert triggered: %s (triggered at %.2f)", alert.Rule(), triggeredPrice)
 }
 
 func (e *Engine) GetStats() EngineStats {
@@ -131,6 +392,9 @@ func (e *Engine) GetStats() EngineStats {
 		Running:         e.running,
 		CooldownEntries: len(e.cooldownMap),
 		QueuedTicks:     len(e.tickChan),
+		PriceWindows:    len(e.windows),
+		CrossingStates:  len(e.lastPrices),
+		IndicatorSeries: e.indicatorSeriesCount(),
 	}
 }
 
@@ -138,17 +402,44 @@ func (e *Engine) CleanupCooldowns() {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	cutoff := time.Now().Add(-e.cooldown * 2)
+	now := e.clock.Now()
 
-	for alertID, lastTrigger := range e.cooldownMap {
-		if lastTrigger.Before(cutoff) {
+	for alertID, cooldownEnd := range e.cooldownMap {
+		if !now.Before(cooldownEnd) {
 			delete(e.cooldownMap, alertID)
 		}
 	}
+
+	for alertID := range e.lastPrices {
+		if _, err := e.store.Get(alertID); err == ErrAlertNotFound {
+			delete(e.lastPrices, alertID)
+		}
+	}
+
+	for alertID := range e.programs {
+		if _, err := e.store.Get(alertID); err == ErrAlertNotFound {
+			delete(e.programs, alertID)
+		}
+	}
+}
+
+// DisableExpired disables every enabled alert whose expiry has passed,
+// including those on symbols that have not ticked since.
+func (e *Engine) DisableExpired() {
+	now := e.clock.Now()
+
+	for _, alert := range e.store.GetAll() {
+		if alert.Enabled && alert.Expired(now) {
+			e.expire(alert)
+		}
+	}
 }
 
 type EngineStats struct {
 	Running         bool `json:"running"`
 	CooldownEntries int  `json:"cooldown_entries"`
 	QueuedTicks     int  `json:"queued_ticks"`
+	PriceWindows    int  `json:"price_windows"`
+	CrossingStates  int  `json:"crossing_states"`
+	IndicatorSeries int  `json:"indicator_series"`
 }
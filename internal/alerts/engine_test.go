@@ -0,0 +1,309 @@
+package alerts
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"crypto-price-alerts/pkg/clock"
+	"crypto-price-alerts/pkg/models"
+	"crypto-price-alerts/pkg/rules"
+)
+
+func TestEngine_CrossingAlertFiresOncePerCrossing(t *testing.T) {
+	store := NewStore()
+	triggerBus := NewTriggerBus()
+	engine := NewEngine(store, triggerBus, 0)
+
+	alert := models.NewCrossingAlert("BTC", models.TriggerModeCrossUp, 100000.0, "")
+	store.Create(alert)
+
+	prices := []float64{99000, 100500, 101000, 102000, 99500, 100100}
+	for _, price := range prices {
+		engine.EvaluateTick(models.NewTick("BTC", price, time.Now()))
+	}
+
+	if got := triggerBus.GetStats().QueuedTriggers; got != 2 {
+		t.Errorf("Expected 2 triggers for 2 upward crossings, got %d", got)
+	}
+}
+
+func TestEngine_PercentMoveAlert(t *testing.T) {
+	store := NewStore()
+	triggerBus := NewTriggerBus()
+	engine := NewEngine(store, triggerBus, time.Hour)
+
+	alert := models.NewPercentMoveAlert("ETH", 3.0, 15*time.Minute, models.DirectionDown, "")
+	store.Create(alert)
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ticks := []struct {
+		offset time.Duration
+		price  float64
+	}{
+		{0, 4000},
+		{5 * time.Minute, 3950},
+		{20 * time.Minute, 3900},
+		{25 * time.Minute, 3780},
+	}
+
+	for _, tk := range ticks {
+		engine.EvaluateTick(&models.Tick{Symbol: "ETH", Price: tk.price, Timestamp: start.Add(tk.offset)})
+	}
+
+	if got := triggerBus.GetStats().QueuedTriggers; got != 1 {
+		t.Errorf("Expected 1 trigger for a 3%% drop within 15m, got %d", got)
+	}
+}
+
+func TestEngine_AlertSourceSelection(t *testing.T) {
+	store := NewStore()
+	triggerBus := NewTriggerBus()
+	engine := NewEngine(store, triggerBus, time.Hour)
+	engine.SetDefaultSource(models.SourceConsolidated)
+
+	consolidated := models.NewAlert("BTC", models.ComparatorGT, 100.0, "")
+	venue := models.NewAlert("BTC", models.ComparatorGT, 100.0, "")
+	venue.Source = "binance"
+	store.Create(consolidated)
+	store.Create(venue)
+
+	engine.EvaluateTick(&models.Tick{Symbol: "BTC", Price: 101, Source: "coinbase", Timestamp: time.Now()})
+	if got := triggerBus.GetStats().QueuedTriggers; got != 0 {
+		t.Fatalf("Expected no triggers from an unrelated venue, got %d", got)
+	}
+
+	engine.EvaluateTick(&models.Tick{Symbol: "BTC", Price: 101, Source: "binance", Timestamp: time.Now()})
+	if got := triggerBus.GetStats().QueuedTriggers; got != 1 {
+		t.Fatalf("Expected venue alert to trigger, got %d triggers", got)
+	}
+
+	engine.EvaluateTick(&models.Tick{Symbol: "BTC", Price: 101, Source: models.SourceConsolidated, Timestamp: time.Now()})
+	if got := triggerBus.GetStats().QueuedTriggers; got != 2 {
+		t.Fatalf("Expected consolidated alert to trigger, got %d triggers", got)
+	}
+}
+
+func TestEngine_CompoundAlertTracksEverySymbol(t *testing.T) {
+	store := NewStore()
+	triggerBus := NewTriggerBus()
+	engine := NewEngine(store, triggerBus, 0)
+
+	condition, err := models.ParseCondition("BTC > 100000 AND ETH < 3000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alert := models.NewCompoundAlert(condition, "")
+	store.Create(alert)
+
+	ticks := []struct {
+		symbol   string
+		price    float64
+		triggers int
+	}{
+		{"BTC", 101000, 0}, // ETH has no price yet
+		{"ETH", 3100, 0},
+		{"ETH", 2900, 1}, // an ETH tick re-evaluates the alert
+		{"SOL", 150, 1},  // unrelated symbol
+		{"BTC", 102000, 2},
+		{"BTC", 99000, 2},
+	}
+
+	for _, tk := range ticks {
+		engine.EvaluateTick(models.NewTick(tk.symbol, tk.price, time.Now()))
+		if got := triggerBus.GetStats().QueuedTriggers; got != tk.triggers {
+			t.Errorf("After %s at %.0f: expected %d triggers, got %d", tk.symbol, tk.price, tk.triggers, got)
+		}
+	}
+
+	replacement, _ := models.ParseCondition("SOL > 100")
+	store.Update(alert.ID, map[string]interface{}{"condition": replacement})
+	if len(store.GetBySymbol("ETH")) != 0 || len(store.GetBySymbol("SOL")) != 1 {
+		t.Errorf("Expected the alert to be re-indexed under its new symbols")
+	}
+}
+
+func TestEngine_ExpressionAlert(t *testing.T) {
+	store := NewStore()
+	triggerBus := NewTriggerBus()
+	engine := NewEngine(store, triggerBus, 0)
+
+	program, err := rules.Compile("pct_change(BTC, 10m) > 5 && price(ETH) < sma(ETH, 3)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Create(models.NewExpressionAlert(program, ""))
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ticks := []struct {
+		offset   time.Duration
+		symbol   string
+		price    float64
+		triggers int
+	}{
+		{0, "BTC", 100, 0},
+		{time.Minute, "ETH", 10, 0},
+		{2 * time.Minute, "ETH", 10, 0},
+		{3 * time.Minute, "BTC", 106, 0},  // ETH has only two samples
+		{4 * time.Minute, "ETH", 9, 1},    // 9 < sma 9.67
+		{20 * time.Minute, "BTC", 107, 1}, // BTC's 10m window only holds 107
+		{21 * time.Minute, "BTC", 113, 2},
+	}
+
+	for _, tk := range ticks {
+		engine.EvaluateTick(&models.Tick{Symbol: tk.symbol, Price: tk.price, Timestamp: start.Add(tk.offset)})
+		if got := triggerBus.GetStats().QueuedTriggers; got != tk.triggers {
+			t.Errorf("After %s at %.0f: expected %d triggers, got %d", tk.symbol, tk.price, tk.triggers, got)
+		}
+	}
+}
+
+func TestEngine_IndicatorAlerts(t *testing.T) {
+	store := NewStore()
+	triggerBus := NewTriggerBus()
+	engine := NewEngine(store, triggerBus, 0)
+
+	golden := models.NewMACrossoverAlert("BTC", models.MovingAverageSMA, 2, 3, 0, models.DirectionUp, "")
+	rsi := models.NewRSIAlert("BTC", 2, 70, time.Minute, models.DirectionUp, "")
+	bollinger := models.NewBollingerAlert("BTC", 3, 1.2, 0, models.DirectionDown, "")
+	for _, alert := range []*models.Alert{golden, rsi, bollinger} {
+		store.Create(alert)
+	}
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ticks := []struct {
+		offset time.Duration
+		price  float64
+		fired  []string
+	}{
+		{0, 100, nil},
+		{10 * time.Second, 101, nil},
+		{20 * time.Second, 100.5, nil},                 // fast SMA above slow; price inside the bands
+		{30 * time.Second, 90, []string{bollinger.ID}}, // below the lower band; fast SMA crosses below
+		{time.Minute, 95, nil},                         // closes the first RSI bar at 90
+		{70 * time.Second, 104, []string{golden.ID}},
+		{2 * time.Minute, 95, nil},                          // bar closes at 104
+		{3 * time.Minute, 106, nil},                         // bar closes at 95: RSI 60.9
+		{4 * time.Minute, 106, []string{golden.ID, rsi.ID}}, // bar closes at 106: RSI 80
+	}
+
+	var seen uint64
+	for _, tk := range ticks {
+		engine.EvaluateTick(&models.Tick{Symbol: "BTC", Price: tk.price, Timestamp: start.Add(tk.offset)})
+
+		var fired []string
+		for _, trigger := range triggerBus.TriggersSince(seen) {
+			seen = trigger.Sequence
+			fired = append(fired, trigger.Alert.ID)
+		}
+		if strings.Join(fired, ",") != strings.Join(tk.fired, ",") {
+			t.Errorf("At +%v (%.1f): fired %v, expected %v", tk.offset, tk.price, fired, tk.fired)
+		}
+	}
+
+	if got := engine.GetStats().IndicatorSeries; got != 3 {
+		t.Errorf("Expected 3 indicator series, got %d", got)
+	}
+}
+
+func TestEngine_CooldownFollowsClock(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+
+	store := NewStore()
+	triggerBus := NewTriggerBus()
+	engine := NewEngineWithConfig(store, triggerBus, EngineConfig{Cooldown: 30 * time.Second, Clock: fake})
+
+	alert := models.NewAlert("BTC", models.ComparatorGT, 100.0, "")
+	store.Create(alert)
+
+	steps := []struct {
+		advance  time.Duration
+		triggers int
+	}{
+		{0, 1},
+		{29 * time.Second, 1}, // cooling down
+		{time.Second, 2},
+		{10 * time.Second, 2},
+	}
+
+	for _, step := range steps {
+		fake.Advance(step.advance)
+		engine.EvaluateTick(models.NewTick("BTC", 101, fake.Now()))
+		if got := triggerBus.GetStats().QueuedTriggers; got != step.triggers {
+			t.Errorf("At %v: expected %d triggers, got %d", fake.Now().Sub(start), step.triggers, got)
+		}
+	}
+
+	if stored, _ := store.Get(alert.ID); stored.LastTrigger == nil || !stored.LastTrigger.Equal(start.Add(30*time.Second)) {
+		t.Errorf("Expected the last trigger at the clock's time, got %v", stored.LastTrigger)
+	}
+
+	fake.Advance(time.Minute)
+	engine.CleanupCooldowns()
+	if got := engine.GetStats().CooldownEntries; got != 0 {
+		t.Errorf("Expected the expired cooldown to be cleaned up, got %d entries", got)
+	}
+}
+
+func TestEngine_PerAlertLimits(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+
+	store := NewStore()
+	engine := NewEngineWithConfig(store, NewTriggerBus(), EngineConfig{Cooldown: time.Hour, Clock: fake})
+
+	quick := models.NewAlert("BTC", models.ComparatorGT, 100.0, "")
+	quick.Cooldown = 10 * time.Second
+	once := models.NewAlert("BTC", models.ComparatorGT, 100.0, "")
+	once.FireOnce = true
+	capped := models.NewAlert("BTC", models.ComparatorGT, 100.0, "")
+	capped.Cooldown = time.Second
+	capped.MaxFires = 2
+	expiresAt := start.Add(25 * time.Second)
+	expiring := models.NewAlert("ETH", models.ComparatorGT, 100.0, "")
+	expiring.Cooldown = time.Second
+	expiring.ExpiresAt = &expiresAt
+
+	for _, alert := range []*models.Alert{quick, once, capped, expiring} {
+		store.Create(alert)
+	}
+
+	for i := 0; i < 4; i++ {
+		engine.EvaluateTick(models.NewTick("BTC", 101, fake.Now()))
+		engine.EvaluateTick(models.NewTick("ETH", 101, fake.Now()))
+		fake.Advance(10 * time.Second)
+	}
+
+	expected := []struct {
+		alert   *models.Alert
+		fires   int
+		enabled bool
+	}{
+		{quick, 4, true},
+		{once, 1, false},
+		{capped, 2, false},
+		{expiring, 3, false},
+	}
+
+	for i, want := range expected {
+		stored, _ := store.Get(want.alert.ID)
+		if stored.FireCount != want.fires || stored.Enabled != want.enabled {
+			t.Errorf("Alert %d: fired %d times, enabled %v; expected %d, %v", i, stored.FireCount, stored.Enabled, want.fires, want.enabled)
+		}
+	}
+
+	rearmed, _ := store.Update(once.ID, map[string]interface{}{"enabled": true})
+	if rearmed.FireCount != 0 || !rearmed.Enabled {
+		t.Errorf("Expected re-enabling to re-arm the alert, got %d fires", rearmed.FireCount)
+	}
+
+	expiresAt = fake.Now()
+	silent := models.NewAlert("SOL", models.ComparatorGT, 100.0, "")
+	silent.ExpiresAt = &expiresAt
+	store.Create(silent)
+	engine.DisableExpired()
+	if stored, _ := store.Get(silent.ID); stored.Enabled {
+		t.Errorf("Expected DisableExpired to disable an alert without ticks")
+	}
+}
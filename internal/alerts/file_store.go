@@ -0,0 +1,359 @@
+package alerts
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"crypto-price-alerts/pkg/models"
+)
+
+const (
+	snapshotFileName     = "alerts.snapshot.json"
+	logFileName          = "alerts.log"
+	defaultSnapshotEvery = 1000
+)
+
+const (
+	opPut    = "put"
+	opDelete = "delete"
+)
+
+type logEntry struct {
+	Op    string        `json:"op"`
+	ID    string        `json:"id,omitempty"`
+	Alert *models.Alert `json:"alert,omitempty"`
+}
+
+// FileStore persists alerts as an append-only JSON log in dir, folded into a
+// snapshot every snapshotEvery writes. Reads are served from an in-memory Store.
+// Changes are logged before they are applied in memory, so a failed write
+// leaves both as they were.
+type FileStore struct {
+	mem           *Store
+	dir           string
+	logFile       *os.File
+	writeMu       sync.Mutex
+	opsSinceSnap  int
+	snapshotEvery int
+}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory %s: %v", dir, err)
+	}
+
+	fs := &FileStore{
+		mem:           NewStore(),
+		dir:           dir,
+		snapshotEvery: defaultSnapshotEvery,
+	}
+
+	if err := fs.loadSnapshot(); err != nil {
+		return nil, err
+	}
+
+	replayed, clean, err := fs.replayLog()
+	if err != nil {
+		return nil, err
+	}
+	fs.opsSinceSnap = replayed
+
+	logFile, err := os.OpenFile(fs.logPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open alert log: %v", err)
+	}
+	fs.logFile = logFile
+
+	// Entries after a corrupt line would never be replayed, so fold what was
+	// recovered into a fresh snapshot before appending anything new.
+	if !clean {
+		if err := fs.compact(); err != nil {
+			logFile.Close()
+			return nil, err
+		}
+	}
+
+	log.Printf("Loaded %d alert(s) from %s", fs.mem.Count(), dir)
+
+	return fs, nil
+}
+
+func (fs *FileStore) Create(alert *models.Alert) error {
+	fs.writeMu.Lock()
+	defer fs.writeMu.Unlock()
+
+	if _, err := fs.mem.Get(alert.ID); err == nil {
+		return ErrAlertExists
+	}
+
+	if err := fs.append(logEntry{Op: opPut, Alert: alert}); err != nil {
+		return err
+	}
+
+	if err := fs.mem.Create(alert); err != nil {
+		return err
+	}
+	fs.maybeCompact()
+
+	return nil
+}
+
+func (fs *FileStore) Get(id string) (*models.Alert, error) {
+	return fs.mem.Get(id)
+}
+
+func (fs *FileStore) Update(id string, updates map[string]interface{}) (*models.Alert, error) {
+	fs.writeMu.Lock()
+	defer fs.writeMu.Unlock()
+
+	alert, err := fs.mem.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply the updates to a copy first, to know what to log.
+	scratch := NewStore()
+	scratch.put(alert)
+	updated, err := scratch.Update(id, updates)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := fs.apply(updated); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (fs *FileStore) Delete(id string) error {
+	fs.writeMu.Lock()
+	defer fs.writeMu.Unlock()
+
+	if _, err := fs.mem.Get(id); err != nil {
+		return err
+	}
+
+	if err := fs.append(logEntry{Op: opDelete, ID: id}); err != nil {
+		return err
+	}
+
+	fs.mem.remove(id)
+	fs.maybeCompact()
+
+	return nil
+}
+
+func (fs *FileStore) GetAll() []*models.Alert {
+	return fs.mem.GetAll()
+}
+
+func (fs *FileStore) GetByOwner(ownerID string) []*models.Alert {
+	return fs.mem.GetByOwner(ownerID)
+}
+
+func (fs *FileStore) GetBySymbol(symbol string) []*models.Alert {
+	return fs.mem.GetBySymbol(symbol)
+}
+
+func (fs *FileStore) GetEnabledBySymbol(symbol string) []*models.Alert {
+	return fs.mem.GetEnabledBySymbol(symbol)
+}
+
+func (fs *FileStore) Count() int {
+	return fs.mem.Count()
+}
+
+func (fs *FileStore) CountBySymbol(symbol string) int {
+	return fs.mem.CountBySymbol(symbol)
+}
+
+func (fs *FileStore) GetActiveSymbols() []string {
+	return fs.mem.GetActiveSymbols()
+}
+
+func (fs *FileStore) MarkTriggered(id string, at time.Time) error {
+	fs.writeMu.Lock()
+	defer fs.writeMu.Unlock()
+
+	alert, err := fs.mem.Get(id)
+	if err != nil {
+		return err
+	}
+
+	alert.MarkTriggered(at)
+	return fs.apply(alert)
+}
+
+func (fs *FileStore) Close() error {
+	fs.writeMu.Lock()
+	defer fs.writeMu.Unlock()
+
+	if fs.logFile == nil {
+		return nil
+	}
+
+	err := fs.logFile.Close()
+	fs.logFile = nil
+	return err
+}
+
+// apply logs alert and then replaces the stored alert with it.
+func (fs *FileStore) apply(alert *models.Alert) error {
+	if err := fs.append(logEntry{Op: opPut, Alert: alert}); err != nil {
+		return err
+	}
+
+	stored := *alert
+	fs.mem.put(&stored)
+	fs.maybeCompact()
+
+	return nil
+}
+
+func (fs *FileStore) append(entry logEntry) error {
+	if fs.logFile == nil {
+		return fmt.Errorf("alert store is closed")
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert log entry: %v", err)
+	}
+	data = append(data, '\n')
+
+	if _, err := fs.logFile.Write(data); err != nil {
+		return fmt.Errorf("failed to write alert log: %v", err)
+	}
+
+	if err := fs.logFile.Sync(); err != nil {
+		return fmt.Errorf("failed to sync alert log: %v", err)
+	}
+
+	fs.opsSinceSnap++
+	return nil
+}
+
+// maybeCompact folds the log into a snapshot once it is long enough. It runs
+// after a change is applied in memory, so the snapshot includes it.
+func (fs *FileStore) maybeCompact() {
+	if fs.opsSinceSnap >= fs.snapshotEvery {
+		if err := fs.compact(); err != nil {
+			log.Printf("Error compacting alert log: %v", err)
+		}
+	}
+}
+
+func (fs *FileStore) compact() error {
+	alerts := fs.mem.GetAll()
+
+	data, err := json.Marshal(alerts)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %v", err)
+	}
+
+	tmpPath := fs.snapshotPath() + ".tmp"
+	tmpFile, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %v", err)
+	}
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to write snapshot: %v", err)
+	}
+
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to sync snapshot: %v", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to close snapshot: %v", err)
+	}
+
+	if err := os.Rename(tmpPath, fs.snapshotPath()); err != nil {
+		return fmt.Errorf("failed to install snapshot: %v", err)
+	}
+
+	if err := fs.logFile.Truncate(0); err != nil {
+		return fmt.Errorf("failed to truncate alert log: %v", err)
+	}
+
+	fs.opsSinceSnap = 0
+	return nil
+}
+
+func (fs *FileStore) loadSnapshot() error {
+	data, err := os.ReadFile(fs.snapshotPath())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot: %v", err)
+	}
+
+	var alerts []*models.Alert
+	if err := json.Unmarshal(data, &alerts); err != nil {
+		return fmt.Errorf("failed to decode snapshot: %v", err)
+	}
+
+	for _, alert := range alerts {
+		fs.mem.put(alert)
+	}
+
+	return nil
+}
+
+func (fs *FileStore) replayLog() (int, bool, error) {
+	file, err := os.Open(fs.logPath())
+	if os.IsNotExist(err) {
+		return 0, true, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to open alert log: %v", err)
+	}
+	defer file.Close()
+
+	replayed := 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	for scanner.Scan() {
+		var entry logEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			log.Printf("Warning: Ignoring corrupt alert log entry %d: %v", replayed+1, err)
+			return replayed, false, nil
+		}
+
+		switch entry.Op {
+		case opPut:
+			if entry.Alert != nil {
+				fs.mem.put(entry.Alert)
+			}
+		case opDelete:
+			fs.mem.remove(entry.ID)
+		}
+		replayed++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return replayed, false, fmt.Errorf("failed to read alert log: %v", err)
+	}
+
+	return replayed, true, nil
+}
+
+func (fs *FileStore) snapshotPath() string {
+	return filepath.Join(fs.dir, snapshotFileName)
+}
+
+func (fs *FileStore) logPath() string {
+	return filepath.Join(fs.dir, logFileName)
+}
@@ -0,0 +1,170 @@
+package alerts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"crypto-price-alerts/pkg/models"
+)
+
+func TestFileStore_SurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+
+	btc := models.NewAlert("BTC", models.ComparatorGT, 100000.0, "BTC breakout")
+	eth := models.NewAlert("ETH", models.ComparatorLT, 3000.0, "ETH dip")
+	sol := models.NewAlert("SOL", models.ComparatorGTE, 200.0, "")
+
+	for _, alert := range []*models.Alert{btc, eth, sol} {
+		if err := store.Create(alert); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	if err := store.MarkTriggered(btc.ID, time.Now()); err != nil {
+		t.Fatalf("MarkTriggered() error = %v", err)
+	}
+
+	if _, err := store.Update(eth.ID, map[string]interface{}{"symbol": "ADA", "threshold": 0.5}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if err := store.Delete(sol.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reopened, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore() reopen error = %v", err)
+	}
+	defer reopened.Close()
+
+	if reopened.Count() != 2 {
+		t.Errorf("Expected 2 alerts after restart, got %d", reopened.Count())
+	}
+
+	restored, err := reopened.Get(btc.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if restored.LastTrigger == nil {
+		t.Error("Expected LastTrigger to survive restart")
+	}
+
+	if got := len(reopened.GetEnabledBySymbol("ADA")); got != 1 {
+		t.Errorf("Expected 1 ADA alert in symbol index, got %d", got)
+	}
+	if got := len(reopened.GetBySymbol("ETH")); got != 0 {
+		t.Errorf("Expected ETH to be removed from symbol index, got %d", got)
+	}
+	if _, err := reopened.Get(sol.ID); err != ErrAlertNotFound {
+		t.Errorf("Expected deleted alert to stay deleted, got %v", err)
+	}
+}
+
+func TestFileStore_Compaction(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	store.snapshotEvery = 3
+
+	for i := 0; i < 5; i++ {
+		if err := store.Create(models.NewAlert("BTC", models.ComparatorGT, float64(i+1), "")); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	store.Close()
+
+	if _, err := os.Stat(filepath.Join(dir, snapshotFileName)); err != nil {
+		t.Fatalf("Expected snapshot to be written: %v", err)
+	}
+
+	reopened, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore() reopen error = %v", err)
+	}
+	defer reopened.Close()
+
+	if got := reopened.CountBySymbol("BTC"); got != 5 {
+		t.Errorf("Expected 5 BTC alerts after compaction, got %d", got)
+	}
+}
+
+func TestFileStore_RecoversFromTruncatedLog(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	alert := models.NewAlert("BTC", models.ComparatorGT, 100.0, "")
+	store.Create(alert)
+	store.Close()
+
+	logFile, err := os.OpenFile(filepath.Join(dir, logFileName), os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("Failed to open log: %v", err)
+	}
+	logFile.WriteString(`{"op":"put","alert":{"id":`)
+	logFile.Close()
+
+	reopened, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore() reopen error = %v", err)
+	}
+	defer reopened.Close()
+
+	if _, err := reopened.Get(alert.ID); err != nil {
+		t.Errorf("Expected alert before the corrupt entry to be restored, got %v", err)
+	}
+}
+
+func TestFileStore_FailedWriteLeavesMemoryUnchanged(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+
+	btc := models.NewAlert("BTC", models.ComparatorGT, 100000.0, "")
+	if err := store.Create(btc); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// Every write fails once the log is closed.
+	store.Close()
+
+	if err := store.Create(models.NewAlert("ETH", models.ComparatorLT, 3000.0, "")); err == nil || store.Count() != 1 {
+		t.Errorf("Expected a failed Create to add nothing, got error %v and %d alert(s)", err, store.Count())
+	}
+
+	if _, err := store.Update(btc.ID, map[string]interface{}{"threshold": 90000.0, "symbol": "ETH"}); err == nil {
+		t.Error("Expected Update to fail")
+	}
+	if alert, _ := store.Get(btc.ID); alert.Threshold != 100000.0 || len(store.GetBySymbol("ETH")) != 0 {
+		t.Errorf("Expected a failed Update to leave the alert unchanged, got %+v", alert)
+	}
+
+	if err := store.MarkTriggered(btc.ID, time.Now()); err == nil {
+		t.Error("Expected MarkTriggered to fail")
+	}
+	if alert, _ := store.Get(btc.ID); alert.LastTrigger != nil || alert.FireCount != 0 {
+		t.Errorf("Expected a failed MarkTriggered to leave the alert unchanged, got %+v", alert)
+	}
+
+	if err := store.Delete(btc.ID); err == nil || store.Count() != 1 {
+		t.Errorf("Expected a failed Delete to keep the alert, got error %v and %d alert(s)", err, store.Count())
+	}
+}
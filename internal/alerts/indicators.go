@@ -0,0 +1,151 @@
+package alerts
+
+import (
+	"fmt"
+	"time"
+
+	"crypto-price-alerts/pkg/indicator"
+	"crypto-price-alerts/pkg/models"
+)
+
+// indicatorSeries is one indicator on one symbol and source, shared by every
+// alert with the same parameters. Its signal is what those alerts compare
+// against their level; see models.Alert.ShouldTriggerIndicator.
+type indicatorSeries struct {
+	bars     *indicator.Bars
+	update   func(close float64) (signal float64, ok bool)
+	previous float64
+	current  float64
+	signals  int
+	// closed is set when the latest tick closed a bar and moved the signal.
+	closed bool
+}
+
+func newIndicatorSeries(alert *models.Alert) *indicatorSeries {
+	c := alert.Indicator
+	series := &indicatorSeries{bars: indicator.NewBars(c.Interval)}
+
+	switch alert.Kind {
+	case models.AlertKindMACrossover:
+		fast, slow := newMovingAverage(c.Average, c.Period), newMovingAverage(c.Average, c.SlowPeriod)
+		series.update = func(close float64) (float64, bool) {
+			fast.Update(close)
+			slow.Update(close)
+			fastValue, fastOK := fast.Value()
+			slowValue, slowOK := slow.Value()
+			return fastValue - slowValue, fastOK && slowOK
+		}
+	case models.AlertKindRSI:
+		rsi := indicator.NewRSI(c.Period)
+		series.update = func(close float64) (float64, bool) {
+			rsi.Update(close)
+			return rsi.Value()
+		}
+	case models.AlertKindBollinger:
+		bands := indicator.NewBollinger(c.Period, c.StdDevs)
+		series.update = func(close float64) (float64, bool) {
+			bands.Update(close)
+			return bands.PercentB(close)
+		}
+	}
+
+	return series
+}
+
+func newMovingAverage(average models.MovingAverage, period int) indicator.MovingAverage {
+	if average == models.MovingAverageEMA {
+		return indicator.NewEMA(period)
+	}
+	return indicator.NewSMA(period)
+}
+
+func (s *indicatorSeries) add(price float64, timestamp time.Time) {
+	s.closed = false
+
+	close, ok := s.bars.Add(price, timestamp)
+	if !ok {
+		return
+	}
+	signal, ok := s.update(close)
+	if !ok {
+		return
+	}
+
+	s.previous, s.current = s.current, signal
+	s.signals++
+	s.closed = s.signals >= 2
+}
+
+// indicatorKey identifies the series an indicator alert reads. Levels and
+// directions are left out so alerts that differ only in those share one.
+func indicatorKey(alert *models.Alert) string {
+	c := alert.Indicator
+	return fmt.Sprintf("%s/%s/%d/%d/%g/%v", alert.Kind, c.Average, c.Period, c.SlowPeriod, c.StdDevs, c.Interval)
+}
+
+// updateIndicators feeds the tick to every indicator series the alerts on
+// its symbol read, creating missing series and dropping unused ones.
+func (e *Engine) updateIndicators(tick *models.Tick, alerts []*models.Alert) {
+	needed := make(map[string]map[string]*models.Alert)
+	for _, alert := range alerts {
+		if !alert.IsIndicator() || alert.Indicator == nil {
+			continue
+		}
+
+		key := historyKey(tick.Symbol, e.followedSource(alert))
+		if needed[key] == nil {
+			needed[key] = make(map[string]*models.Alert)
+		}
+		needed[key][indicatorKey(alert)] = alert
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, key := range tickHistoryKeys(tick) {
+		specs := needed[key]
+		if len(specs) == 0 {
+			delete(e.indicators, key)
+			continue
+		}
+
+		series, exists := e.indicators[key]
+		if !exists {
+			series = make(map[string]*indicatorSeries)
+			e.indicators[key] = series
+		}
+
+		for spec := range series {
+			if _, ok := specs[spec]; !ok {
+				delete(series, spec)
+			}
+		}
+		for spec, alert := range specs {
+			if series[spec] == nil {
+				series[spec] = newIndicatorSeries(alert)
+			}
+			series[spec].add(tick.Price, tick.Timestamp)
+		}
+	}
+}
+
+func (e *Engine) indicatorFired(alert *models.Alert, tick *models.Tick) bool {
+	if alert.Indicator == nil {
+		return false
+	}
+	key := historyKey(tick.Symbol, e.followedSource(alert))
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	series := e.indicators[key][indicatorKey(alert)]
+	return series != nil && series.closed && alert.ShouldTriggerIndicator(series.previous, series.current)
+}
+
+func (e *Engine) indicatorSeriesCount() int {
+	count := 0
+	for _, series := range e.indicators {
+		count += len(series)
+	}
+	return count
+}
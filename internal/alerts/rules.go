@@ -0,0 +1,86 @@
+package alerts
+
+import (
+	"log"
+	"time"
+
+	"crypto-price-alerts/pkg/models"
+	"crypto-price-alerts/pkg/rules"
+)
+
+// compiledRule caches an expression alert's program. program is nil when
+// the expression no longer compiles, so the error is logged only once.
+type compiledRule struct {
+	expression string
+	program    *rules.Program
+}
+
+// program returns the compiled expression of alert, recompiling it when the
+// expression has been updated.
+func (e *Engine) program(alert *models.Alert) *rules.Program {
+	e.mu.RLock()
+	cached, exists := e.programs[alert.ID]
+	e.mu.RUnlock()
+
+	if exists && cached.expression == alert.Expression {
+		return cached.program
+	}
+
+	program, err := rules.Compile(alert.Expression)
+	if err != nil {
+		log.Printf("Error compiling rule of alert %s: %v", alert.ID, err)
+	}
+
+	e.mu.Lock()
+	e.programs[alert.ID] = &compiledRule{expression: alert.Expression, program: program}
+	e.mu.Unlock()
+
+	return program
+}
+
+func (e *Engine) evaluateExpression(alert *models.Alert, tick *models.Tick) bool {
+	program := e.program(alert)
+	if !alert.Enabled || program == nil {
+		return false
+	}
+
+	result, known := program.Eval(&historyEnv{engine: e, source: e.followedSource(alert), now: tick.Timestamp})
+	return known && result
+}
+
+// historyEnv serves the rules built-in functions from the engine's latest
+// prices and price windows for one source.
+type historyEnv struct {
+	engine *Engine
+	source string
+	now    time.Time
+}
+
+func (h *historyEnv) Price(symbol string) (float64, bool) {
+	h.engine.mu.RLock()
+	defer h.engine.mu.RUnlock()
+
+	price, ok := h.engine.latest[historyKey(symbol, h.source)]
+	return price, ok
+}
+
+func (h *historyEnv) Window(symbol string, window time.Duration) []float64 {
+	if w := h.window(symbol); w != nil {
+		return w.Since(h.now, window)
+	}
+	return nil
+}
+
+func (h *historyEnv) Samples(symbol string, n int) []float64 {
+	if w := h.window(symbol); w != nil {
+		return w.Last(n)
+	}
+	return nil
+}
+
+func (h *historyEnv) window(symbol string) *PriceWindow {
+	h.engine.mu.RLock()
+	defer h.engine.mu.RUnlock()
+
+	return h.engine.windows[historyKey(symbol, h.source)]
+}
@@ -2,9 +2,12 @@ package alerts
 
 import (
 	"errors"
+	"slices"
 	"sync"
+	"time"
 
 	"crypto-price-alerts/pkg/models"
+	"crypto-price-alerts/pkg/rules"
 )
 
 var (
@@ -12,6 +15,22 @@ var (
 	ErrAlertExists   = errors.New("alert already exists")
 )
 
+type Storage interface {
+	Create(alert *models.Alert) error
+	Get(id string) (*models.Alert, error)
+	Update(id string, updates map[string]interface{}) (*models.Alert, error)
+	Delete(id string) error
+	GetAll() []*models.Alert
+	GetByOwner(ownerID string) []*models.Alert
+	GetBySymbol(symbol string) []*models.Alert
+	GetEnabledBySymbol(symbol string) []*models.Alert
+	Count() int
+	CountBySymbol(symbol string) int
+	GetActiveSymbols() []string
+	MarkTriggered(id string, at time.Time) error
+	Close() error
+}
+
 type Store struct {
 	alerts map[string]*models.Alert
 	symbolIndex map[string][]string
@@ -35,7 +54,7 @@ func (s *Store) Create(alert *models.Alert) error {
 
 	s.alerts[alert.ID] = alert
 
-	s.addToSymbolIndex(alert.Symbol, alert.ID)
+	s.addToSymbolIndex(alert.Symbols(), alert.ID)
 
 	return nil
 }
@@ -62,7 +81,7 @@ func (s *Store) Update(id string, updates map[string]interface{}) (*models.Alert
 		return nil, ErrAlertNotFound
 	}
 
-	oldSymbol := alert.Symbol
+	oldSymbols := alert.Symbols()
 
 	for field, value := range updates {
 		switch field {
@@ -78,20 +97,87 @@ func (s *Store) Update(id string, updates map[string]interface{}) (*models.Alert
 			if threshold, ok := value.(float64); ok {
 				alert.Threshold = threshold
 			}
+		case "source":
+			if source, ok := value.(string); ok {
+				alert.Source = source
+			}
+		case "mode":
+			if mode, ok := value.(models.TriggerMode); ok {
+				alert.Mode = mode
+			}
+		case "direction":
+			if direction, ok := value.(models.Direction); ok {
+				alert.Direction = direction
+			}
+		case "window":
+			if window, ok := value.(time.Duration); ok {
+				alert.Window = window
+			}
+		case "condition":
+			if condition, ok := value.(*models.Condition); ok {
+				alert.Condition = condition
+			}
+		case "expression":
+			if program, ok := value.(*rules.Program); ok {
+				alert.SetExpression(program)
+			}
+		case "indicator":
+			if indicator, ok := value.(*models.IndicatorConfig); ok {
+				alert.Indicator = indicator
+			}
 		case "note":
 			if note, ok := value.(string); ok {
 				alert.Note = note
 			}
+		case "labels":
+			if labels, ok := value.([]string); ok {
+				alert.Labels = labels
+			}
+		case "severity":
+			if severity, ok := value.(models.Severity); ok {
+				alert.Severity = severity
+			}
+		case "webhook_url":
+			if webhookURL, ok := value.(string); ok {
+				alert.WebhookURL = webhookURL
+				if webhookURL == "" {
+					alert.WebhookSecret = ""
+				}
+			}
+		case "webhook_secret":
+			if secret, ok := value.(string); ok {
+				alert.WebhookSecret = secret
+			}
+		case "cooldown":
+			if cooldown, ok := value.(time.Duration); ok {
+				alert.Cooldown = cooldown
+			}
+		case "expires_at":
+			if expiresAt, ok := value.(*time.Time); ok {
+				alert.ExpiresAt = expiresAt
+			}
+		case "fire_once":
+			if fireOnce, ok := value.(bool); ok {
+				alert.FireOnce = fireOnce
+			}
+		case "max_fires":
+			if maxFires, ok := value.(int); ok {
+				alert.MaxFires = maxFires
+			}
 		case "enabled":
 			if enabled, ok := value.(bool); ok {
+				// Re-enabling an alert re-arms its fire limit.
+				if enabled && !alert.Enabled {
+					alert.FireCount = 0
+				}
 				alert.Enabled = enabled
 			}
 		}
 	}
 
-	if oldSymbol != alert.Symbol {
-		s.removeFromSymbolIndex(oldSymbol, id)
-		s.addToSymbolIndex(alert.Symbol, id)
+	if newSymbols := alert.Symbols(); !slices.Equal(oldSymbols, newSymbols) {
+		s.removeFromSymbolIndex(oldSymbols, id)
+		s.addToSymbolIndex(newSymbols, id)
 	}
 
 	alertCopy := *alert
@@ -107,7 +193,7 @@ func (s *Store) Delete(id string) error {
 		return ErrAlertNotFound
 	}
 
-	s.removeFromSymbolIndex(alert.Symbol, id)
+	s.removeFromSymbolIndex(alert.Symbols(), id)
 
 	delete(s.alerts, id)
 
@@ -127,6 +213,21 @@ func (s *Store) GetAll() []*models.Alert {
 	return alerts
 }
 
+func (s *Store) GetByOwner(ownerID string) []*models.Alert {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	alerts := make([]*models.Alert, 0)
+	for _, alert := range s.alerts {
+		if alert.OwnerID == ownerID {
+			alertCopy := *alert
+			alerts = append(alerts, &alertCopy)
+		}
+	}
+
+	return alerts
+}
+
 func (s *Store) GetBySymbol(symbol string) []*models.Alert {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -197,7 +298,7 @@ func (s *Store) GetActiveSymbols() []string {
 	return symbols
 }
 
-func (s *Store) MarkTriggered(id string) error {
+func (s *Store) MarkTriggered(id string, at time.Time) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -206,11 +307,51 @@ func (s *Store) MarkTriggered(id string) error {
 		return ErrAlertNotFound
 	}
 
-	alert.MarkTriggered()
+	alert.MarkTriggered(at)
 	return nil
 }
 
-func (s *Store) addToSymbolIndex(symbol, alertID string) {
+func (s *Store) Close() error {
+	return nil
+}
+
+func (s *Store) put(alert *models.Alert) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, exists := s.alerts[alert.ID]; exists {
+		s.removeFromSymbolIndex(existing.Symbols(), alert.ID)
+	}
+
+	s.alerts[alert.ID] = alert
+	s.addToSymbolIndex(alert.Symbols(), alert.ID)
+}
+
+func (s *Store) remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if alert, exists := s.alerts[id]; exists {
+		s.removeFromSymbolIndex(alert.Symbols(), id)
+		delete(s.alerts, id)
+	}
+}
+
+// addToSymbolIndex indexes alertID under each of symbols, so a compound
+// alert is found from a tick on any symbol it references.
+func (s *Store) addToSymbolIndex(symbols []string, alertID string) {
+	for _, symbol := range symbols {
+		s.indexSymbol(symbol, alertID)
+	}
+}
+
+func (s *Store) removeFromSymbolIndex(symbols []string, alertID string) {
+	for _, symbol := range symbols {
+		s.unindexSymbol(symbol, alertID)
+	}
+}
+
+func (s *Store) indexSymbol(symbol, alertID string) {
 	if alertIDs, exists := s.symbolIndex[symbol]; exists {
 		for _, id := range alertIDs {
 			if id == alertID {
@@ -223,7 +364,7 @@ func (s *Store) addToSymbolIndex(symbol, alertID string) {
 	}
 }
 
-func (s *Store) removeFromSymbolIndex(symbol, alertID string) {
+func (s *Store) unindexSymbol(symbol, alertID string) {
 	alertIDs, exists := s.symbolIndex[symbol]
 	if !exists {
 		return
@@ -2,24 +2,108 @@ package alerts
 
 import (
 	"context"
+	"log"
 	"sync"
+	"sync/atomic"
 
+	"crypto-price-alerts/internal/metrics"
 	"crypto-price-alerts/pkg/models"
 )
 
+// TriggerFilter narrows the triggers a subscriber receives. Empty sets match
+// everything; a trigger must pass every non-empty set.
+type TriggerFilter struct {
+	// OwnerID restricts triggers to one owner's alerts when set. System
+	// events belong to no owner and reach everyone.
+	OwnerID     string
+	Symbols     map[string]bool
+	AlertIDs    map[string]bool
+	Labels      map[string]bool
+	MinSeverity models.Severity
+}
+
+func NewTriggerFilter(symbols, alertIDs, labels []string, minSeverity models.Severity) *TriggerFilter {
+	return &TriggerFilter{
+		Symbols:     toSet(symbols),
+		AlertIDs:    toSet(alertIDs),
+		Labels:      toSet(labels),
+		MinSeverity: minSeverity,
+	}
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		if value != "" {
+			set[value] = true
+		}
+	}
+	return set
+}
+
+func (f *TriggerFilter) Matches(trigger *models.AlertTrigger) bool {
+	if f == nil {
+		return true
+	}
+
+	alert := trigger.Alert
+
+	if f.OwnerID != "" && alert.OwnerID != f.OwnerID && !trigger.IsSystem() {
+		return false
+	}
+
+	if len(f.Symbols) > 0 {
+		matched := false
+		for _, symbol := range alert.Symbols() {
+			if f.Symbols[symbol] {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(f.AlertIDs) > 0 && !f.AlertIDs[alert.ID] {
+		return false
+	}
+
+	if len(f.Labels) > 0 {
+		matched := false
+		for _, label := range alert.Labels {
+			if f.Labels[label] {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return alert.Severity >= f.MinSeverity
+}
+
 type TriggerSubscriber struct {
 	ID          string
 	TriggerChan chan *models.AlertTrigger
+	filter      *TriggerFilter
 	ctx         context.Context
 	cancel      context.CancelFunc
 }
 
 func NewTriggerSubscriber(id string, bufferSize int) *TriggerSubscriber {
+	return NewFilteredTriggerSubscriber(id, bufferSize, nil)
+}
+
+func NewFilteredTriggerSubscriber(id string, bufferSize int, filter *TriggerFilter) *TriggerSubscriber {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &TriggerSubscriber{
 		ID:          id,
 		TriggerChan: make(chan *models.AlertTrigger, bufferSize),
+		filter:      filter,
 		ctx:         ctx,
 		cancel:      cancel,
 	}
@@ -31,19 +115,69 @@ func (ts *TriggerSubscriber) Close() {
 }
 
 type TriggerBus struct {
-	subscribers  map[string]*TriggerSubscriber
-	mu           sync.RWMutex
-	triggerChan  chan *models.AlertTrigger
-	stopChan     chan struct{}
-	running      bool
+	subscribers map[string]*TriggerSubscriber
+	mu          sync.RWMutex
+	triggerChan chan *models.AlertTrigger
+	stopChan    chan struct{}
+	running     bool
+	stopped     bool
+	triggerLog  TriggerLog
+	sequence    atomic.Uint64
+	dropped     atomic.Uint64
+	subBuffer   int
+	wg          sync.WaitGroup
+
+	// publishMu numbers triggers and records them in the log; queueMu keeps
+	// them queued in that order. Publish holds only queueMu while it waits
+	// for room in the queue, so readers of the sequence never wait on it.
+	publishMu sync.Mutex
+	queueMu   sync.Mutex
+}
+
+type TriggerBusConfig struct {
+	// Buffer is how many published triggers may wait for fan-out before
+	// Publish blocks.
+	Buffer int
+	// SubscriberBuffer is the per-subscriber buffer used when Subscribe is
+	// called with a non-positive size.
+	SubscriberBuffer int
+}
+
+func DefaultTriggerBusConfig() TriggerBusConfig {
+	return TriggerBusConfig{
+		Buffer:           1000,
+		SubscriberBuffer: 100,
+	}
 }
 
 func NewTriggerBus() *TriggerBus {
-	return &TriggerBus{
+	return NewTriggerBusWithLog(NewMemoryTriggerLog(defaultTriggerLogSize))
+}
+
+// NewTriggerBusWithLog creates a bus that records every published trigger in
+// triggerLog and continues numbering from its last sequence.
+func NewTriggerBusWithLog(triggerLog TriggerLog) *TriggerBus {
+	return NewTriggerBusWithConfig(triggerLog, DefaultTriggerBusConfig())
+}
+
+func NewTriggerBusWithConfig(triggerLog TriggerLog, cfg TriggerBusConfig) *TriggerBus {
+	defaults := DefaultTriggerBusConfig()
+	if cfg.Buffer <= 0 {
+		cfg.Buffer = defaults.Buffer
+	}
+	if cfg.SubscriberBuffer <= 0 {
+		cfg.SubscriberBuffer = defaults.SubscriberBuffer
+	}
+
+	tb := &TriggerBus{
 		subscribers: make(map[string]*TriggerSubscriber),
-		triggerChan: make(chan *models.AlertTrigger, 1000),
+		triggerChan: make(chan *models.AlertTrigger, cfg.Buffer),
 		stopChan:    make(chan struct{}),
+		triggerLog:  triggerLog,
+		subBuffer:   cfg.SubscriberBuffer,
 	}
+	tb.sequence.Store(triggerLog.LastSequence())
+	return tb
 }
 
 func (tb *TriggerBus) Start(ctx context.Context) error {
@@ -55,37 +189,58 @@ func (tb *TriggerBus) Start(ctx context.Context) error {
 	tb.running = true
 	tb.mu.Unlock()
 
+	tb.wg.Add(1)
 	go tb.distributeTriggers(ctx)
 	return nil
 }
 
 func (tb *TriggerBus) Stop() {
 	tb.mu.Lock()
-	defer tb.mu.Unlock()
 
 	if !tb.running {
+		tb.mu.Unlock()
 		return
 	}
 
 	tb.running = false
 	close(tb.stopChan)
+	tb.mu.Unlock()
 
+	// No fan-out may be in flight once the subscriber channels are closed.
+	tb.wg.Wait()
+
+	tb.mu.Lock()
 	for _, subscriber := range tb.subscribers {
 		subscriber.Close()
 	}
+	tb.subscribers = make(map[string]*TriggerSubscriber)
+	tb.mu.Unlock()
 
+	// A blocked Publish returns once stopChan is closed, so it is safe to
+	// close the channel after taking the queue lock.
+	tb.queueMu.Lock()
+	tb.stopped = true
 	close(tb.triggerChan)
+	tb.queueMu.Unlock()
 }
 
 func (tb *TriggerBus) Subscribe(subscriberID string, bufferSize int) *TriggerSubscriber {
+	return tb.SubscribeWithFilter(subscriberID, bufferSize, nil)
+}
+
+func (tb *TriggerBus) SubscribeWithFilter(subscriberID string, bufferSize int, filter *TriggerFilter) *TriggerSubscriber {
 	tb.mu.Lock()
 	defer tb.mu.Unlock()
 
+	if bufferSize <= 0 {
+		bufferSize = tb.subBuffer
+	}
+
 	if existing, exists := tb.subscribers[subscriberID]; exists {
 		existing.Close()
 	}
 
-	subscriber := NewTriggerSubscriber(subscriberID, bufferSize)
+	subscriber := NewFilteredTriggerSubscriber(subscriberID, bufferSize, filter)
 	tb.subscribers[subscriberID] = subscriber
 
 	return subscriber
@@ -101,13 +256,41 @@ func (tb *TriggerBus) Unsubscribe(subscriberID string) {
 	}
 }
 
+// Publish numbers the trigger, records it in the trigger log and queues it
+// for fan-out, waiting for room in the queue rather than dropping it.
 func (tb *TriggerBus) Publish(trigger *models.AlertTrigger) {
+	tb.publishMu.Lock()
+	trigger.Sequence = tb.sequence.Load() + 1
+	metrics.TriggersFired.WithLabelValues(trigger.Alert.Symbol).Inc()
+
+	if err := tb.triggerLog.Append(trigger); err != nil {
+		log.Printf("Error recording trigger %d: %v", trigger.Sequence, err)
+	}
+	tb.sequence.Store(trigger.Sequence)
+
+	tb.queueMu.Lock()
+	tb.publishMu.Unlock()
+	defer tb.queueMu.Unlock()
+
+	if tb.stopped {
+		return
+	}
+
 	select {
 	case tb.triggerChan <- trigger:
-	default:
+	case <-tb.stopChan:
 	}
 }
 
+// TriggersSince returns the retained triggers after sequence, oldest first.
+func (tb *TriggerBus) TriggersSince(sequence uint64) []*models.AlertTrigger {
+	return tb.triggerLog.Since(sequence)
+}
+
+func (tb *TriggerBus) LastSequence() uint64 {
+	return tb.sequence.Load()
+}
+
 func (tb *TriggerBus) GetSubscriberCount() int {
 	tb.mu.RLock()
 	defer tb.mu.RUnlock()
@@ -115,6 +298,8 @@ func (tb *TriggerBus) GetSubscriberCount() int {
 }
 
 func (tb *TriggerBus) distributeTriggers(ctx context.Context) {
+	defer tb.wg.Done()
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -131,11 +316,23 @@ func (tb *TriggerBus) fanOutTrigger(trigger *models.AlertTrigger) {
 	tb.mu.RLock()
 	defer tb.mu.RUnlock()
 
+	if !tb.running {
+		return
+	}
+
 	for _, subscriber := range tb.subscribers {
+		if !subscriber.filter.Matches(trigger) {
+			continue
+		}
+
+		// Slow subscribers skip the trigger here and recover it from the
+		// trigger log when they notice the gap in sequence numbers.
 		select {
 		case subscriber.TriggerChan <- trigger:
 		case <-subscriber.ctx.Done():
 		default:
+			tb.dropped.Add(1)
+			metrics.TriggerDeliveriesDropped.Inc()
 		}
 	}
 }
@@ -148,11 +345,15 @@ func (tb *TriggerBus) GetStats() TriggerBusStats {
 		Running:           tb.running,
 		SubscriberCount:   len(tb.subscribers),
 		QueuedTriggers:    len(tb.triggerChan),
+		LastSequence:      tb.LastSequence(),
+		DroppedDeliveries: tb.dropped.Load(),
 	}
 }
 
 type TriggerBusStats struct {
-	Running         bool `json:"running"`
-	SubscriberCount int  `json:"subscriber_count"`
-	QueuedTriggers  int  `json:"queued_triggers"`
+	Running           bool   `json:"running"`
+	SubscriberCount   int    `json:"subscriber_count"`
+	QueuedTriggers    int    `json:"queued_triggers"`
+	LastSequence      uint64 `json:"last_sequence"`
+	DroppedDeliveries uint64 `json:"dropped_deliveries"`
 }
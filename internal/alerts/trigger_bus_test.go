@@ -0,0 +1,166 @@
+package alerts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"crypto-price-alerts/pkg/models"
+)
+
+func TestTriggerFilter_Matches(t *testing.T) {
+	alert := &models.Alert{
+		ID:       "alert-1",
+		OwnerID:  "alice",
+		Symbol:   "BTC",
+		Labels:   []string{"desk", "swing"},
+		Severity: models.SeverityWarning,
+	}
+	trigger := models.NewAlertTrigger(alert, 100.0, time.Now())
+
+	tests := []struct {
+		name     string
+		filter   *TriggerFilter
+		expected bool
+	}{
+		{"nil filter", nil, true},
+		{"empty filter", NewTriggerFilter(nil, nil, nil, models.SeverityInfo), true},
+		{"matching symbol", NewTriggerFilter([]string{"ETH", "BTC"}, nil, nil, models.SeverityInfo), true},
+		{"other symbol", NewTriggerFilter([]string{"ETH"}, nil, nil, models.SeverityInfo), false},
+		{"matching alert ID", NewTriggerFilter(nil, []string{"alert-1"}, nil, models.SeverityInfo), true},
+		{"other alert ID", NewTriggerFilter(nil, []string{"alert-2"}, nil, models.SeverityInfo), false},
+		{"any matching label", NewTriggerFilter(nil, nil, []string{"ops", "swing"}, models.SeverityInfo), true},
+		{"no matching label", NewTriggerFilter(nil, nil, []string{"ops"}, models.SeverityInfo), false},
+		{"severity at minimum", NewTriggerFilter(nil, nil, nil, models.SeverityWarning), true},
+		{"severity below minimum", NewTriggerFilter(nil, nil, nil, models.SeverityCritical), false},
+		{"matching owner", &TriggerFilter{OwnerID: "alice"}, true},
+		{"other owner", &TriggerFilter{OwnerID: "bob"}, false},
+		{"all filters must pass", NewTriggerFilter([]string{"BTC"}, nil, []string{"ops"}, models.SeverityInfo), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.filter.Matches(trigger); result != tt.expected {
+				t.Errorf("Matches() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTriggerBus_FiltersBeforeDelivery(t *testing.T) {
+	bus := NewTriggerBus()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus.Start(ctx)
+	defer bus.Stop()
+
+	btcOnly := bus.SubscribeWithFilter("btc", 10, NewTriggerFilter([]string{"BTC"}, nil, nil, models.SeverityInfo))
+	everything := bus.Subscribe("all", 10)
+
+	bus.Publish(models.NewAlertTrigger(&models.Alert{ID: "1", Symbol: "ETH"}, 1.0, time.Now()))
+	bus.Publish(models.NewAlertTrigger(&models.Alert{ID: "2", Symbol: "BTC"}, 2.0, time.Now()))
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-everything.TriggerChan:
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for unfiltered trigger")
+		}
+	}
+
+	select {
+	case trigger := <-btcOnly.TriggerChan:
+		if trigger.Alert.Symbol != "BTC" {
+			t.Errorf("Expected BTC trigger, got %s", trigger.Alert.Symbol)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for filtered trigger")
+	}
+
+	if len(btcOnly.TriggerChan) != 0 {
+		t.Errorf("Expected filtered subscriber to receive only one trigger, %d queued", len(btcOnly.TriggerChan))
+	}
+}
+
+func TestTriggerBus_SequencesAndReplay(t *testing.T) {
+	bus := NewTriggerBus()
+
+	for i := 0; i < 3; i++ {
+		bus.Publish(models.NewAlertTrigger(&models.Alert{ID: "1", Symbol: "BTC"}, float64(i), time.Now()))
+	}
+
+	if bus.LastSequence() != 3 {
+		t.Fatalf("Expected last sequence 3, got %d", bus.LastSequence())
+	}
+
+	missed := bus.TriggersSince(1)
+	if len(missed) != 2 || missed[0].Sequence != 2 || missed[1].Sequence != 3 {
+		t.Errorf("Expected triggers 2 and 3 after sequence 1, got %v", sequences(missed))
+	}
+}
+
+func TestTriggerBus_StopWhilePublishing(t *testing.T) {
+	for run := 0; run < 20; run++ {
+		bus := NewTriggerBus()
+		bus.Start(context.Background())
+
+		subscriber := bus.Subscribe("slow", 1)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 500; i++ {
+				bus.Publish(models.NewAlertTrigger(&models.Alert{ID: "1", Symbol: "BTC"}, float64(i), time.Now()))
+			}
+		}()
+
+		time.Sleep(time.Millisecond)
+		bus.Stop()
+		<-done
+
+		// Draining ends because Stop closed the channel.
+		for range subscriber.TriggerChan {
+		}
+
+		bus.Unsubscribe("slow")
+		if count := bus.GetSubscriberCount(); count != 0 {
+			t.Fatalf("Expected no subscribers after Stop, got %d", count)
+		}
+	}
+}
+
+func TestTriggerBus_StatsWhilePublishBlocks(t *testing.T) {
+	bus := NewTriggerBusWithConfig(NewMemoryTriggerLog(10), TriggerBusConfig{Buffer: 1})
+
+	// Nothing drains the queue yet, so the second Publish waits for room.
+	published := make(chan struct{})
+	go func() {
+		defer close(published)
+		for i := 0; i < 2; i++ {
+			bus.Publish(models.NewAlertTrigger(&models.Alert{ID: "1", Symbol: "BTC"}, float64(i), time.Now()))
+		}
+	}()
+
+	stats := make(chan TriggerBusStats)
+	go func() {
+		for bus.LastSequence() < 2 {
+			time.Sleep(time.Millisecond)
+		}
+		stats <- bus.GetStats()
+	}()
+
+	select {
+	case s := <-stats:
+		if s.LastSequence != 2 || s.QueuedTriggers != 1 {
+			t.Errorf("GetStats() = %+v, expected sequence 2 with 1 queued", s)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("GetStats blocked behind a waiting Publish")
+	}
+
+	bus.Start(context.Background())
+	<-published
+	bus.Stop()
+}
@@ -0,0 +1,278 @@
+package alerts
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+
+	"crypto-price-alerts/pkg/models"
+)
+
+const defaultTriggerLogSize = 10000
+
+// TriggerLog keeps the most recent triggers in sequence order so that
+// subscribers can catch up on triggers they missed.
+type TriggerLog interface {
+	Append(trigger *models.AlertTrigger) error
+	// Since returns the retained triggers with a sequence greater than
+	// sequence, oldest first.
+	Since(sequence uint64) []*models.AlertTrigger
+	LastSequence() uint64
+	Close() error
+}
+
+type MemoryTriggerLog struct {
+	entries []*models.AlertTrigger
+	start   int
+	count   int
+	mu      sync.RWMutex
+}
+
+func NewMemoryTriggerLog(capacity int) *MemoryTriggerLog {
+	if capacity <= 0 {
+		capacity = defaultTriggerLogSize
+	}
+
+	return &MemoryTriggerLog{
+		entries: make([]*models.AlertTrigger, capacity),
+	}
+}
+
+func (l *MemoryTriggerLog) Append(trigger *models.AlertTrigger) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	capacity := len(l.entries)
+	if l.count < capacity {
+		l.entries[(l.start+l.count)%capacity] = trigger
+		l.count++
+	} else {
+		l.entries[l.start] = trigger
+		l.start = (l.start + 1) % capacity
+	}
+
+	return nil
+}
+
+func (l *MemoryTriggerLog) Since(sequence uint64) []*models.AlertTrigger {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	capacity := len(l.entries)
+	first := sort.Search(l.count, func(i int) bool {
+		return l.entries[(l.start+i)%capacity].Sequence > sequence
+	})
+
+	triggers := make([]*models.AlertTrigger, 0, l.count-first)
+	for i := first; i < l.count; i++ {
+		triggers = append(triggers, l.entries[(l.start+i)%capacity])
+	}
+
+	return triggers
+}
+
+func (l *MemoryTriggerLog) LastSequence() uint64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.count == 0 {
+		return 0
+	}
+
+	return l.entries[(l.start+l.count-1)%len(l.entries)].Sequence
+}
+
+func (l *MemoryTriggerLog) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.count
+}
+
+func (l *MemoryTriggerLog) Close() error {
+	return nil
+}
+
+// FileTriggerLog mirrors a MemoryTriggerLog to a JSON-lines file so that
+// sequence numbers and recent triggers survive a restart. The file is
+// rewritten down to the retained triggers once it holds twice the capacity.
+type FileTriggerLog struct {
+	mem      *MemoryTriggerLog
+	path     string
+	file     *os.File
+	capacity int
+	lines    int
+	mu       sync.Mutex
+}
+
+func NewFileTriggerLog(path string, capacity int) (*FileTriggerLog, error) {
+	if capacity <= 0 {
+		capacity = defaultTriggerLogSize
+	}
+
+	tl := &FileTriggerLog{
+		mem:      NewMemoryTriggerLog(capacity),
+		path:     path,
+		capacity: capacity,
+	}
+
+	if err := tl.load(); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trigger log: %v", err)
+	}
+	tl.file = file
+
+	if tl.lines >= 2*capacity {
+		if err := tl.rewrite(); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+
+	return tl, nil
+}
+
+func (tl *FileTriggerLog) Append(trigger *models.AlertTrigger) error {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	tl.mem.Append(trigger)
+
+	if tl.file == nil {
+		return fmt.Errorf("trigger log is closed")
+	}
+
+	data, err := json.Marshal(trigger)
+	if err != nil {
+		return fmt.Errorf("failed to encode trigger: %v", err)
+	}
+	data = append(data, '\n')
+
+	if _, err := tl.file.Write(data); err != nil {
+		return fmt.Errorf("failed to write trigger log: %v", err)
+	}
+
+	if err := tl.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync trigger log: %v", err)
+	}
+
+	tl.lines++
+	if tl.lines >= 2*tl.capacity {
+		if err := tl.rewrite(); err != nil {
+			log.Printf("Error compacting trigger log: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func (tl *FileTriggerLog) Since(sequence uint64) []*models.AlertTrigger {
+	return tl.mem.Since(sequence)
+}
+
+func (tl *FileTriggerLog) LastSequence() uint64 {
+	return tl.mem.LastSequence()
+}
+
+func (tl *FileTriggerLog) Close() error {
+	tl.mu.Lock()
+	defer tl.mu.Unlock()
+
+	if tl.file == nil {
+		return nil
+	}
+
+	err := tl.file.Close()
+	tl.file = nil
+	return err
+}
+
+func (tl *FileTriggerLog) load() error {
+	file, err := os.Open(tl.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open trigger log: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	var last uint64
+	for scanner.Scan() {
+		tl.lines++
+
+		var trigger models.AlertTrigger
+		if err := json.Unmarshal(scanner.Bytes(), &trigger); err != nil {
+			log.Printf("Warning: Ignoring corrupt trigger log entry %d: %v", tl.lines, err)
+			continue
+		}
+
+		if trigger.Sequence <= last {
+			continue
+		}
+		last = trigger.Sequence
+
+		tl.mem.Append(&trigger)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read trigger log: %v", err)
+	}
+
+	return nil
+}
+
+func (tl *FileTriggerLog) rewrite() error {
+	triggers := tl.mem.Since(0)
+
+	tmpPath := tl.path + ".tmp"
+	tmpFile, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create trigger log: %v", err)
+	}
+
+	writer := bufio.NewWriter(tmpFile)
+	encoder := json.NewEncoder(writer)
+	for _, trigger := range triggers {
+		if err := encoder.Encode(trigger); err != nil {
+			tmpFile.Close()
+			return fmt.Errorf("failed to encode trigger: %v", err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to write trigger log: %v", err)
+	}
+
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to sync trigger log: %v", err)
+	}
+	tmpFile.Close()
+
+	if err := os.Rename(tmpPath, tl.path); err != nil {
+		return fmt.Errorf("failed to install trigger log: %v", err)
+	}
+
+	file, err := os.OpenFile(tl.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to reopen trigger log: %v", err)
+	}
+
+	tl.file.Close()
+	tl.file = file
+	tl.lines = len(triggers)
+
+	return nil
+}
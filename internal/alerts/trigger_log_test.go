@@ -0,0 +1,104 @@
+package alerts
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"crypto-price-alerts/pkg/models"
+)
+
+func sequencedTrigger(sequence uint64) *models.AlertTrigger {
+	trigger := models.NewAlertTrigger(&models.Alert{ID: "alert-1", Symbol: "BTC"}, float64(sequence), time.Now())
+	trigger.Sequence = sequence
+	return trigger
+}
+
+func sequences(triggers []*models.AlertTrigger) []uint64 {
+	result := make([]uint64, len(triggers))
+	for i, trigger := range triggers {
+		result[i] = trigger.Sequence
+	}
+	return result
+}
+
+func equalSequences(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMemoryTriggerLog_Since(t *testing.T) {
+	tl := NewMemoryTriggerLog(3)
+	for seq := uint64(1); seq <= 5; seq++ {
+		tl.Append(sequencedTrigger(seq))
+	}
+
+	tests := []struct {
+		name     string
+		since    uint64
+		expected []uint64
+	}{
+		{"from start keeps only retained", 0, []uint64{3, 4, 5}},
+		{"evicted cursor", 1, []uint64{3, 4, 5}},
+		{"mid cursor", 3, []uint64{4, 5}},
+		{"caught up", 5, []uint64{}},
+		{"ahead of log", 9, []uint64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := sequences(tl.Since(tt.since)); !equalSequences(result, tt.expected) {
+				t.Errorf("Since(%d) = %v, expected %v", tt.since, result, tt.expected)
+			}
+		})
+	}
+
+	if tl.Len() != 3 {
+		t.Errorf("Expected 3 retained triggers, got %d", tl.Len())
+	}
+	if tl.LastSequence() != 5 {
+		t.Errorf("Expected last sequence 5, got %d", tl.LastSequence())
+	}
+}
+
+func TestFileTriggerLog_SurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "triggers.log")
+
+	tl, err := NewFileTriggerLog(path, 4)
+	if err != nil {
+		t.Fatalf("NewFileTriggerLog() error = %v", err)
+	}
+	// Enough appends to force a rewrite of the file.
+	for seq := uint64(1); seq <= 10; seq++ {
+		if err := tl.Append(sequencedTrigger(seq)); err != nil {
+			t.Fatalf("Append(%d) error = %v", seq, err)
+		}
+	}
+	tl.Close()
+
+	reopened, err := NewFileTriggerLog(path, 4)
+	if err != nil {
+		t.Fatalf("NewFileTriggerLog() reopen error = %v", err)
+	}
+	defer reopened.Close()
+
+	if reopened.LastSequence() != 10 {
+		t.Errorf("Expected last sequence 10 after reopen, got %d", reopened.LastSequence())
+	}
+	if result := sequences(reopened.Since(0)); !equalSequences(result, []uint64{7, 8, 9, 10}) {
+		t.Errorf("Since(0) after reopen = %v, expected [7 8 9 10]", result)
+	}
+
+	bus := NewTriggerBusWithLog(reopened)
+	bus.Publish(models.NewAlertTrigger(&models.Alert{ID: "alert-1", Symbol: "BTC"}, 1.0, time.Now()))
+	if bus.LastSequence() != 11 {
+		t.Errorf("Expected bus to continue at sequence 11, got %d", bus.LastSequence())
+	}
+}
@@ -0,0 +1,205 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"crypto-price-alerts/pkg/clock"
+	"crypto-price-alerts/pkg/models"
+)
+
+type WatchdogConfig struct {
+	// StaleAfter is how long a symbol may go without a tick before a
+	// feed-stale event is published.
+	StaleAfter time.Duration
+	// CheckInterval is how often silence is checked for.
+	CheckInterval time.Duration
+	// Symbols are watched from Start, so a symbol that never ticks is
+	// reported too. Other symbols are watched from their first tick.
+	Symbols []string
+	// Clock times ticks and silences; nil uses the system clock.
+	Clock clock.Clock
+}
+
+func DefaultWatchdogConfig() WatchdogConfig {
+	return WatchdogConfig{
+		StaleAfter:    time.Minute,
+		CheckInterval: 5 * time.Second,
+	}
+}
+
+type symbolActivity struct {
+	lastTick  time.Time
+	lastPrice float64
+	stale     bool
+}
+
+// Watchdog publishes a system event on the TriggerBus when a symbol stops
+// ticking, and another when its ticks resume, so a silent feed cannot pass
+// for a quiet market.
+type Watchdog struct {
+	cfg        WatchdogConfig
+	triggerBus *TriggerBus
+	symbols    map[string]*symbolActivity
+	running    bool
+	stopChan   chan struct{}
+	mu         sync.Mutex
+	wg         sync.WaitGroup
+	clock      clock.Clock
+}
+
+func NewWatchdog(triggerBus *TriggerBus, cfg WatchdogConfig) *Watchdog {
+	defaults := DefaultWatchdogConfig()
+	if cfg.StaleAfter <= 0 {
+		cfg.StaleAfter = defaults.StaleAfter
+	}
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = defaults.CheckInterval
+	}
+
+	return &Watchdog{
+		cfg:        cfg,
+		triggerBus: triggerBus,
+		symbols:    make(map[string]*symbolActivity),
+		stopChan:   make(chan struct{}),
+		clock:      clock.OrReal(cfg.Clock),
+	}
+}
+
+func (w *Watchdog) Start(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.running {
+		return nil
+	}
+	w.running = true
+
+	now := w.clock.Now()
+	for _, symbol := range w.cfg.Symbols {
+		if _, exists := w.symbols[symbol]; !exists {
+			w.symbols[symbol] = &symbolActivity{lastTick: now}
+		}
+	}
+
+	w.wg.Add(1)
+	go w.run(ctx)
+
+	log.Printf("Feed watchdog started (stale after %v)", w.cfg.StaleAfter)
+	return nil
+}
+
+func (w *Watchdog) Stop() {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return
+	}
+	w.running = false
+	close(w.stopChan)
+	w.mu.Unlock()
+
+	w.wg.Wait()
+}
+
+// ObserveTick records a tick and publishes a recovery event if its symbol
+// had been reported stale.
+func (w *Watchdog) ObserveTick(tick *models.Tick) {
+	now := w.clock.Now()
+
+	w.mu.Lock()
+	activity, exists := w.symbols[tick.Symbol]
+	if !exists {
+		activity = &symbolActivity{}
+		w.symbols[tick.Symbol] = activity
+	}
+	silence := now.Sub(activity.lastTick)
+	recovered := activity.stale
+	activity.lastTick = now
+	activity.lastPrice = tick.Price
+	activity.stale = false
+	w.mu.Unlock()
+
+	if recovered {
+		log.Printf("Feed recovered: %s ticking again after %v", tick.Symbol, silence.Truncate(time.Second))
+		w.publish(models.TriggerEventFeedRecovered, tick.Symbol, tick.Price,
+			fmt.Sprintf("Ticks resumed after %v of silence", silence.Truncate(time.Second)))
+	}
+}
+
+// StaleSymbols returns the symbols currently reported stale.
+func (w *Watchdog) StaleSymbols() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var stale []string
+	for symbol, activity := range w.symbols {
+		if activity.stale {
+			stale = append(stale, symbol)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+func (w *Watchdog) run(ctx context.Context) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopChan:
+			return
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+// check publishes one feed-stale event per symbol that has gone silent
+// since the last check.
+func (w *Watchdog) check() {
+	now := w.clock.Now()
+
+	type staleSymbol struct {
+		symbol    string
+		lastTick  time.Time
+		lastPrice float64
+	}
+	var newlyStale []staleSymbol
+
+	w.mu.Lock()
+	for symbol, activity := range w.symbols {
+		if !activity.stale && now.Sub(activity.lastTick) > w.cfg.StaleAfter {
+			activity.stale = true
+			newlyStale = append(newlyStale, staleSymbol{symbol, activity.lastTick, activity.lastPrice})
+		}
+	}
+	w.mu.Unlock()
+
+	sort.Slice(newlyStale, func(i, j int) bool { return newlyStale[i].symbol < newlyStale[j].symbol })
+
+	for _, s := range newlyStale {
+		log.Printf("Feed stale: no %s ticks for %v", s.symbol, now.Sub(s.lastTick).Truncate(time.Second))
+		w.publish(models.TriggerEventFeedStale, s.symbol, s.lastPrice,
+			fmt.Sprintf("No ticks since %s", s.lastTick.Format(time.RFC3339)))
+	}
+}
+
+func (w *Watchdog) publish(event models.TriggerEvent, symbol string, price float64, note string) {
+	alert := models.NewFeedSilenceAlert(symbol, w.cfg.StaleAfter)
+	alert.Note = note
+
+	trigger := models.NewAlertTrigger(alert, price, w.clock.Now())
+	trigger.Event = event
+
+	w.triggerBus.Publish(trigger)
+}
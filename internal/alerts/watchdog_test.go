@@ -0,0 +1,84 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+
+	"crypto-price-alerts/pkg/clock"
+	"crypto-price-alerts/pkg/models"
+)
+
+func TestWatchdog_StaleAndRecovered(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+
+	bus := NewTriggerBusWithLog(NewMemoryTriggerLog(100))
+	watchdog := NewWatchdog(bus, WatchdogConfig{StaleAfter: time.Minute, Symbols: []string{"BTC", "ETH"}, Clock: fake})
+
+	watchdog.Start(t.Context())
+	defer watchdog.Stop()
+
+	steps := []struct {
+		name   string
+		at     time.Duration
+		tick   string
+		events []string // "event symbol" published by this step
+	}{
+		{"BTC ticks", 30 * time.Second, "BTC", nil},
+		{"ETH never ticked", 61 * time.Second, "", []string{"feed_stale ETH"}},
+		{"stale is reported once", 80 * time.Second, "", nil},
+		{"BTC goes quiet", 91 * time.Second, "", []string{"feed_stale BTC"}},
+		{"BTC resumes", 2 * time.Minute, "BTC", []string{"feed_recovered BTC"}},
+		{"BTC keeps ticking", 2*time.Minute + time.Second, "BTC", nil},
+	}
+
+	var seen uint64
+	for _, step := range steps {
+		fake.Set(start.Add(step.at))
+		if step.tick != "" {
+			watchdog.ObserveTick(models.NewTick(step.tick, 50000, fake.Now()))
+		} else {
+			watchdog.check()
+		}
+
+		var events []string
+		for _, trigger := range bus.TriggersSince(seen) {
+			seen = trigger.Sequence
+			events = append(events, trigger.Event.String()+" "+trigger.Alert.Symbol)
+
+			if !trigger.IsSystem() || !trigger.Alert.HasLabel(models.SystemLabel) {
+				t.Errorf("%s: expected a system event, got %+v", step.name, trigger)
+			}
+		}
+
+		if len(events) != len(step.events) {
+			t.Errorf("%s: events = %v, expected %v", step.name, events, step.events)
+			continue
+		}
+		for i := range events {
+			if events[i] != step.events[i] {
+				t.Errorf("%s: events = %v, expected %v", step.name, events, step.events)
+			}
+		}
+	}
+
+	if stale := watchdog.StaleSymbols(); len(stale) != 1 || stale[0] != "ETH" {
+		t.Errorf("StaleSymbols() = %v, expected [ETH]", stale)
+	}
+}
+
+func TestTriggerFilter_SystemEventsReachEveryOwner(t *testing.T) {
+	filter := &TriggerFilter{OwnerID: "alice"}
+
+	system := models.NewAlertTrigger(models.NewFeedSilenceAlert("BTC", time.Minute), 0, time.Now())
+	system.Event = models.TriggerEventFeedStale
+	if !filter.Matches(system) {
+		t.Error("Expected system events to match an owner filter")
+	}
+
+	other := models.NewAlert("BTC", models.ComparatorGT, 1, "")
+	other.OwnerID = "bob"
+	if filter.Matches(models.NewAlertTrigger(other, 2, time.Now())) {
+		t.Error("Expected another owner's trigger to be filtered out")
+	}
+}
@@ -0,0 +1,132 @@
+package alerts
+
+import (
+	"sync"
+	"time"
+)
+
+type pricePoint struct {
+	price     float64
+	timestamp time.Time
+}
+
+// PriceWindow keeps the recent prices of one symbol, oldest first.
+type PriceWindow struct {
+	points []pricePoint
+	mu     sync.RWMutex
+}
+
+func NewPriceWindow() *PriceWindow {
+	return &PriceWindow{}
+}
+
+func (w *PriceWindow) Add(price float64, timestamp time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.points = append(w.points, pricePoint{price: price, timestamp: timestamp})
+}
+
+// Prune drops every point older than retention relative to now, always
+// keeping the most recent point.
+func (w *PriceWindow) Prune(now time.Time, retention time.Duration) {
+	w.PruneKeeping(now, retention, 1)
+}
+
+// PruneKeeping is Prune, but keeps at least the keep most recent points
+// however old they are.
+func (w *PriceWindow) PruneKeeping(now time.Time, retention time.Duration, keep int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if keep < 1 {
+		keep = 1
+	}
+
+	cutoff := now.Add(-retention)
+	drop := 0
+	for drop < len(w.points)-keep && w.points[drop].timestamp.Before(cutoff) {
+		drop++
+	}
+
+	if drop > 0 {
+		w.points = append(w.points[:0], w.points[drop:]...)
+	}
+}
+
+// Move returns how far the latest price has risen from the lowest price and
+// fallen from the highest price seen within window of now, in percent.
+func (w *PriceWindow) Move(now time.Time, window time.Duration) (risePct, fallPct float64) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if len(w.points) == 0 {
+		return 0, 0
+	}
+
+	latest := w.points[len(w.points)-1].price
+	low, high := latest, latest
+	cutoff := now.Add(-window)
+
+	for i := len(w.points) - 1; i >= 0; i-- {
+		point := w.points[i]
+		if point.timestamp.Before(cutoff) {
+			break
+		}
+		if point.price < low {
+			low = point.price
+		}
+		if point.price > high {
+			high = point.price
+		}
+	}
+
+	if low > 0 {
+		risePct = (latest - low) / low * 100
+	}
+	if high > 0 {
+		fallPct = (high - latest) / high * 100
+	}
+
+	return risePct, fallPct
+}
+
+// Since returns the prices within window of now, oldest first, always
+// including the most recent one.
+func (w *PriceWindow) Since(now time.Time, window time.Duration) []float64 {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	cutoff := now.Add(-window)
+	start := len(w.points) - 1
+	for start > 0 && !w.points[start-1].timestamp.Before(cutoff) {
+		start--
+	}
+	return w.prices(start)
+}
+
+// Last returns up to the n most recent prices, oldest first.
+func (w *PriceWindow) Last(n int) []float64 {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.prices(max(len(w.points)-n, 0))
+}
+
+func (w *PriceWindow) prices(start int) []float64 {
+	if start < 0 || start >= len(w.points) {
+		return nil
+	}
+
+	prices := make([]float64, 0, len(w.points)-start)
+	for _, point := range w.points[start:] {
+		prices = append(prices, point.price)
+	}
+	return prices
+}
+
+func (w *PriceWindow) Len() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return len(w.points)
+}
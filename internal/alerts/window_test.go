@@ -0,0 +1,75 @@
+package alerts
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPriceWindow_Move(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	window := NewPriceWindow()
+
+	window.Add(100.0, start)
+	window.Add(90.0, start.Add(5*time.Minute))
+	window.Add(105.0, start.Add(10*time.Minute))
+	window.Add(99.0, start.Add(20*time.Minute))
+
+	now := start.Add(20 * time.Minute)
+
+	rise, fall := window.Move(now, 14*time.Minute)
+	if math.Abs(rise-0) > 1e-9 {
+		t.Errorf("Expected no rise within 14m, got %.4f", rise)
+	}
+	if math.Abs(fall-(105.0-99.0)/105.0*100) > 1e-9 {
+		t.Errorf("Expected fall from 105 to 99, got %.4f", fall)
+	}
+
+	rise, _ = window.Move(now, 30*time.Minute)
+	if math.Abs(rise-10) > 1e-9 {
+		t.Errorf("Expected 10%% rise from 90 within 30m, got %.4f", rise)
+	}
+}
+
+func TestPriceWindow_Prune(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	window := NewPriceWindow()
+
+	for i := 0; i < 10; i++ {
+		window.Add(100.0, start.Add(time.Duration(i)*time.Minute))
+	}
+
+	window.Prune(start.Add(9*time.Minute), 3*time.Minute)
+	if window.Len() != 4 {
+		t.Errorf("Expected 4 points after prune, got %d", window.Len())
+	}
+
+	window.Prune(start.Add(time.Hour), time.Minute)
+	if window.Len() != 1 {
+		t.Errorf("Expected latest point to be kept, got %d", window.Len())
+	}
+}
+
+func TestPriceWindow_SinceAndLast(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	window := NewPriceWindow()
+
+	for i := 0; i < 5; i++ {
+		window.Add(float64(100+i), start.Add(time.Duration(i)*time.Minute))
+	}
+
+	if got := window.Since(start.Add(4*time.Minute), 2*time.Minute); len(got) != 3 || got[0] != 102 {
+		t.Errorf("Since(2m) = %v, expected [102 103 104]", got)
+	}
+	if got := window.Since(start.Add(time.Hour), time.Minute); len(got) != 1 || got[0] != 104 {
+		t.Errorf("Since() = %v, expected the latest price", got)
+	}
+	if got := window.Last(2); len(got) != 2 || got[0] != 103 {
+		t.Errorf("Last(2) = %v, expected [103 104]", got)
+	}
+
+	window.PruneKeeping(start.Add(time.Hour), time.Minute, 3)
+	if window.Len() != 3 {
+		t.Errorf("Expected 3 points to be kept, got %d", window.Len())
+	}
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthenticator_UnaryInterceptor(t *testing.T) {
+	kf := newTestKeyFile(t)
+	apiKey, _ := GenerateAPIKey()
+	kf.AddAPIKey(APIKey{Name: "ops", User: "ops", Role: RoleAdmin, SHA256: HashAPIKey(apiKey)})
+	token, _ := kf.IssueToken("new", Identity{UserID: "alice"}, time.Hour, time.Now())
+
+	authenticator := NewAuthenticator(kf)
+	authenticator.AllowUnauthenticated("/test.Service/Public")
+	interceptor := authenticator.UnaryInterceptor()
+
+	tests := []struct {
+		name     string
+		method   string
+		md       metadata.MD
+		expected Identity
+		code     codes.Code
+	}{
+		{"API key", "/test.Service/Call", metadata.Pairs(APIKeyKey, apiKey), Identity{UserID: "ops", Role: RoleAdmin}, codes.OK},
+		{"bearer token", "/test.Service/Call", metadata.Pairs(AuthorizationKey, "Bearer "+token), Identity{UserID: "alice", Role: RoleUser}, codes.OK},
+		{"identity headers are ignored", "/test.Service/Call", metadata.Pairs(UserIDKey, "alice", RoleKey, "admin"), Identity{}, codes.Unauthenticated},
+		{"wrong API key", "/test.Service/Call", metadata.Pairs(APIKeyKey, "cpa_wrong"), Identity{}, codes.Unauthenticated},
+		{"basic auth", "/test.Service/Call", metadata.Pairs(AuthorizationKey, "Basic YWxpY2U6eA=="), Identity{}, codes.Unauthenticated},
+		{"public method", "/test.Service/Public", metadata.MD{}, Identity{}, codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+
+			var got Identity
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				got, _ = FromContext(ctx)
+				return nil, nil
+			}
+
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if status.Code(err) != tt.code {
+				t.Fatalf("interceptor code = %v, expected %v", status.Code(err), tt.code)
+			}
+			if got != tt.expected {
+				t.Errorf("identity = %+v, expected %+v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Metadata keys carrying the caller's identity.
+const (
+	UserIDKey = "x-user-id"
+	RoleKey   = "x-user-role"
+)
+
+type Role string
+
+const (
+	RoleUser  Role = "user"
+	RoleAdmin Role = "admin"
+)
+
+func ParseRole(value string) (Role, error) {
+	switch Role(strings.ToLower(strings.TrimSpace(value))) {
+	case "", RoleUser:
+		return RoleUser, nil
+	case RoleAdmin:
+		return RoleAdmin, nil
+	default:
+		return "", fmt.Errorf("unknown role %q", value)
+	}
+}
+
+// Identity is the caller an RPC runs on behalf of. Alerts are owned by
+// UserID; admins can see and manage every tenant's alerts.
+type Identity struct {
+	UserID string
+	Role   Role
+}
+
+func (i Identity) IsAdmin() bool {
+	return i.Role == RoleAdmin
+}
+
+type identityKey struct{}
+
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+func FromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok && identity.UserID != ""
+}
+
+// identityFromMetadata reads the identity headers set by the client. They
+// are only as trustworthy as whatever sits in front of the server.
+func identityFromMetadata(ctx context.Context) (Identity, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return Identity{}, false
+	}
+
+	userID := firstValue(md, UserIDKey)
+	if userID == "" {
+		return Identity{}, false
+	}
+
+	role := RoleUser
+	if strings.EqualFold(firstValue(md, RoleKey), string(RoleAdmin)) {
+		role = RoleAdmin
+	}
+
+	return Identity{UserID: userID, Role: role}, true
+}
+
+func firstValue(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(values[0])
+}
+
+// UnaryMetadataInterceptor attaches the identity from request metadata to
+// the context. Calls without one pass through; services that need an owner
+// reject them.
+func UnaryMetadataInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if identity, ok := identityFromMetadata(ctx); ok {
+			ctx = WithIdentity(ctx, identity)
+		}
+		return handler(ctx, req)
+	}
+}
+
+func StreamMetadataInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if identity, ok := identityFromMetadata(stream.Context()); ok {
+			stream = &identityStream{ServerStream: stream, ctx: WithIdentity(stream.Context(), identity)}
+		}
+		return handler(srv, stream)
+	}
+}
+
+type identityStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *identityStream) Context() context.Context {
+	return s.ctx
+}
+
+func (i Identity) outgoing(ctx context.Context) context.Context {
+	pairs := []string{UserIDKey, i.UserID}
+	if i.Role != "" {
+		pairs = append(pairs, RoleKey, string(i.Role))
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+// UnaryClientInterceptor sends identity with every client call.
+func UnaryClientInterceptor(identity Identity) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(identity.outgoing(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+func StreamClientInterceptor(identity Identity) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(identity.outgoing(ctx), desc, cc, method, opts...)
+	}
+}
@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// APIKey grants access to User. Only the SHA-256 of the key is stored.
+type APIKey struct {
+	Name   string `json:"name"`
+	User   string `json:"user"`
+	Role   Role   `json:"role,omitempty"`
+	SHA256 string `json:"sha256"`
+}
+
+// TokenKey is an HMAC secret for signing bearer tokens. Tokens name the key
+// they were signed with, so keys can be rotated by adding a new one before
+// removing the old.
+type TokenKey struct {
+	ID     string `json:"id"`
+	Secret string `json:"secret"` // base64
+}
+
+// KeyFile is the local credential store shared by the server and keytool.
+type KeyFile struct {
+	APIKeys   []APIKey   `json:"api_keys"`
+	TokenKeys []TokenKey `json:"token_keys"`
+
+	apiKeys   map[string]APIKey
+	tokenKeys map[string][]byte
+}
+
+func LoadKeyFile(path string) (*KeyFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %v", err)
+	}
+
+	var kf KeyFile
+	if err := json.Unmarshal(data, &kf); err != nil {
+		return nil, fmt.Errorf("failed to parse key file: %v", err)
+	}
+
+	if err := kf.index(); err != nil {
+		return nil, err
+	}
+
+	return &kf, nil
+}
+
+func (kf *KeyFile) Save(path string) error {
+	data, err := json.MarshalIndent(kf, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode key file: %v", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write key file: %v", err)
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+func (kf *KeyFile) index() error {
+	kf.apiKeys = make(map[string]APIKey, len(kf.APIKeys))
+	for _, key := range kf.APIKeys {
+		if key.User == "" {
+			return fmt.Errorf("API key %q has no user", key.Name)
+		}
+		if key.Role != "" && key.Role != RoleUser && key.Role != RoleAdmin {
+			return fmt.Errorf("API key %q has unknown role %q", key.Name, key.Role)
+		}
+		hash := strings.ToLower(key.SHA256)
+		if _, err := hex.DecodeString(hash); err != nil || len(hash) != sha256.Size*2 {
+			return fmt.Errorf("API key %q has an invalid sha256", key.Name)
+		}
+		kf.apiKeys[hash] = key
+	}
+
+	kf.tokenKeys = make(map[string][]byte, len(kf.TokenKeys))
+	for _, key := range kf.TokenKeys {
+		secret, err := base64.StdEncoding.DecodeString(key.Secret)
+		if err != nil || len(secret) < 32 {
+			return fmt.Errorf("token key %q must be at least 32 bytes of base64", key.ID)
+		}
+		if _, exists := kf.tokenKeys[key.ID]; exists {
+			return fmt.Errorf("duplicate token key %q", key.ID)
+		}
+		kf.tokenKeys[key.ID] = secret
+	}
+
+	return nil
+}
+
+func (kf *KeyFile) AddAPIKey(key APIKey) error {
+	kf.APIKeys = append(kf.APIKeys, key)
+	return kf.index()
+}
+
+func (kf *KeyFile) AddTokenKey(key TokenKey) error {
+	kf.TokenKeys = append(kf.TokenKeys, key)
+	return kf.index()
+}
+
+// LookupAPIKey returns the identity an API key grants.
+func (kf *KeyFile) LookupAPIKey(key string) (Identity, bool) {
+	entry, exists := kf.apiKeys[HashAPIKey(key)]
+	if !exists {
+		return Identity{}, false
+	}
+
+	role := entry.Role
+	if role == "" {
+		role = RoleUser
+	}
+
+	return Identity{UserID: entry.User, Role: role}, true
+}
+
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func GenerateAPIKey() (string, error) {
+	return randomString("cpa_", 32)
+}
+
+// GenerateTokenSecret returns a new base64 secret for a TokenKey.
+func GenerateTokenSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(secret), nil
+}
+
+func randomString(prefix string, n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return prefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
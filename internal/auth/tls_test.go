@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCert creates a certificate signed by parent (self-signed when nil)
+// and writes it and its key as PEM files in dir.
+func writeCert(t *testing.T, dir, name string, template *x509.Certificate, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	os.WriteFile(filepath.Join(dir, name+".pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	os.WriteFile(filepath.Join(dir, name+"-key.pem"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+
+	return cert, key
+}
+
+func certTemplate(serial int64, cn string, isCA bool) *x509.Certificate {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{cn},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	if isCA {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	}
+	return template
+}
+
+func handshake(serverConfig, clientConfig *tls.Config) error {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverConn, err := listener.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		server := tls.Server(serverConn, serverConfig)
+		err = server.Handshake()
+		// TLS 1.3 reports client certificate failures after the client's
+		// handshake returns, so read once to surface them.
+		if err == nil {
+			_, err = server.Read(make([]byte, 1))
+		}
+		serverErr <- err
+		server.Close()
+	}()
+
+	clientConn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		return err
+	}
+	defer clientConn.Close()
+
+	client := tls.Client(clientConn, clientConfig)
+	if err := client.Handshake(); err != nil {
+		return err
+	}
+	client.Write([]byte{0})
+
+	return <-serverErr
+}
+
+func TestTLSConfig_MutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := writeCert(t, dir, "ca", certTemplate(1, "test-ca", true), nil, nil)
+	writeCert(t, dir, "server", certTemplate(2, "alerts.local", false), ca, caKey)
+	writeCert(t, dir, "client", certTemplate(3, "alice", false), ca, caKey)
+	writeCert(t, dir, "rogue", certTemplate(4, "mallory", false), nil, nil)
+
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	serverConfig, err := ServerTLSConfig(path("server.pem"), path("server-key.pem"), path("ca.pem"))
+	if err != nil {
+		t.Fatalf("ServerTLSConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		cert    string
+		wantErr bool
+	}{
+		{"client signed by CA", "client", false},
+		{"no client certificate", "", true},
+		{"self-signed client", "rogue", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certFile, keyFile := "", ""
+			if tt.cert != "" {
+				certFile, keyFile = path(tt.cert+".pem"), path(tt.cert+"-key.pem")
+			}
+
+			clientConfig, err := ClientTLSConfig(path("ca.pem"), certFile, keyFile, "alerts.local")
+			if err != nil {
+				t.Fatalf("ClientTLSConfig() error = %v", err)
+			}
+
+			if err := handshake(serverConfig, clientConfig); (err != nil) != tt.wantErr {
+				t.Errorf("handshake error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
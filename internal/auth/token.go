@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Bearer tokens are HS256 JWTs signed with one of the key file's token keys.
+
+const clockSkew = 30 * time.Second
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+)
+
+type tokenHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+type tokenClaims struct {
+	Subject   string `json:"sub"`
+	Role      Role   `json:"role,omitempty"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// IssueToken signs a token for identity with the named token key.
+func (kf *KeyFile) IssueToken(keyID string, identity Identity, ttl time.Duration, now time.Time) (string, error) {
+	secret, exists := kf.tokenKeys[keyID]
+	if !exists {
+		return "", fmt.Errorf("unknown token key %q", keyID)
+	}
+	if identity.UserID == "" {
+		return "", fmt.Errorf("token subject is required")
+	}
+	if ttl <= 0 {
+		return "", fmt.Errorf("token lifetime must be positive")
+	}
+
+	header, err := json.Marshal(tokenHeader{Alg: "HS256", Typ: "JWT", Kid: keyID})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(tokenClaims{
+		Subject:   identity.UserID,
+		Role:      identity.Role,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := encodeSegment(header) + "." + encodeSegment(claims)
+	return signingInput + "." + encodeSegment(sign(secret, signingInput)), nil
+}
+
+// VerifyToken checks the signature and lifetime of a bearer token and
+// returns the identity it carries.
+func (kf *KeyFile) VerifyToken(token string, now time.Time) (Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Identity{}, ErrInvalidToken
+	}
+
+	var header tokenHeader
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return Identity{}, ErrInvalidToken
+	}
+
+	secret, exists := kf.tokenKeys[header.Kid]
+	if !exists {
+		return Identity{}, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, sign(secret, parts[0]+"."+parts[1])) {
+		return Identity{}, ErrInvalidToken
+	}
+
+	var claims tokenClaims
+	if err := decodeSegment(parts[1], &claims); err != nil || claims.Subject == "" || claims.ExpiresAt == 0 {
+		return Identity{}, ErrInvalidToken
+	}
+
+	if now.After(time.Unix(claims.ExpiresAt, 0).Add(clockSkew)) {
+		return Identity{}, ErrTokenExpired
+	}
+
+	role := claims.Role
+	if role != RoleAdmin {
+		role = RoleUser
+	}
+
+	return Identity{UserID: claims.Subject, Role: role}, nil
+}
+
+func sign(secret []byte, signingInput string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
+
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestKeyFile(t *testing.T) *KeyFile {
+	t.Helper()
+
+	kf := &KeyFile{}
+	for _, id := range []string{"old", "new"} {
+		secret, err := GenerateTokenSecret()
+		if err != nil {
+			t.Fatalf("GenerateTokenSecret() error = %v", err)
+		}
+		if err := kf.AddTokenKey(TokenKey{ID: id, Secret: secret}); err != nil {
+			t.Fatalf("AddTokenKey() error = %v", err)
+		}
+	}
+	return kf
+}
+
+func TestKeyFile_VerifyToken(t *testing.T) {
+	kf := newTestKeyFile(t)
+	other := newTestKeyFile(t)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	issue := func(kf *KeyFile, keyID string, identity Identity, ttl time.Duration) string {
+		token, err := kf.IssueToken(keyID, identity, ttl, now)
+		if err != nil {
+			t.Fatalf("IssueToken() error = %v", err)
+		}
+		return token
+	}
+
+	valid := issue(kf, "new", Identity{UserID: "alice", Role: RoleUser}, time.Hour)
+	parts := strings.Split(valid, ".")
+	forgedClaims := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"alice","role":"admin","exp":9999999999}`))
+	noneHeader := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","kid":"new"}`))
+
+	tests := []struct {
+		name     string
+		token    string
+		at       time.Time
+		expected Identity
+		err      error
+	}{
+		{"valid", valid, now, Identity{UserID: "alice", Role: RoleUser}, nil},
+		{"admin role", issue(kf, "old", Identity{UserID: "ops", Role: RoleAdmin}, time.Hour), now, Identity{UserID: "ops", Role: RoleAdmin}, nil},
+		{"within clock skew", valid, now.Add(time.Hour + 10*time.Second), Identity{UserID: "alice", Role: RoleUser}, nil},
+		{"expired", valid, now.Add(2 * time.Hour), Identity{}, ErrTokenExpired},
+		{"signed with unknown key", issue(other, "new", Identity{UserID: "alice"}, time.Hour), now, Identity{}, ErrInvalidToken},
+		{"tampered claims", parts[0] + "." + forgedClaims + "." + parts[2], now, Identity{}, ErrInvalidToken},
+		{"alg none", noneHeader + "." + parts[1] + ".", now, Identity{}, ErrInvalidToken},
+		{"garbage", "not-a-token", now, Identity{}, ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := kf.VerifyToken(tt.token, tt.at)
+			if err != tt.err {
+				t.Fatalf("VerifyToken() error = %v, expected %v", err, tt.err)
+			}
+			if identity != tt.expected {
+				t.Errorf("VerifyToken() = %+v, expected %+v", identity, tt.expected)
+			}
+		})
+	}
+}
+
+func TestKeyFile_LookupAPIKey(t *testing.T) {
+	kf := &KeyFile{}
+	key, _ := GenerateAPIKey()
+	if err := kf.AddAPIKey(APIKey{Name: "alice-cli", User: "alice", SHA256: HashAPIKey(key)}); err != nil {
+		t.Fatalf("AddAPIKey() error = %v", err)
+	}
+
+	if identity, ok := kf.LookupAPIKey(key); !ok || identity != (Identity{UserID: "alice", Role: RoleUser}) {
+		t.Errorf("LookupAPIKey() = %+v, %v", identity, ok)
+	}
+	if _, ok := kf.LookupAPIKey(key + "x"); ok {
+		t.Error("Expected an unknown key to be rejected")
+	}
+}
@@ -0,0 +1,90 @@
+package backtest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"crypto-price-alerts/pkg/models"
+)
+
+var start = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func TestRun_ReportsTriggersOnTickTime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ticks.csv")
+	content := "timestamp,symbol,price\n" +
+		"2025-01-01T12:00:00Z,BTC,99000\n" +
+		"2025-01-01T12:00:10Z,BTC,101000\n" + // fires
+		"2025-01-01T12:00:20Z,BTC,102000\n" + // cooling down
+		"2025-01-01T12:00:40Z,BTC,103000\n" + // fires
+		"2025-01-01T12:00:50Z,ETH,2900\n" + // fires
+		"2025-01-01T12:05:00Z,BTC,99500\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	btc := models.NewAlert("BTC", models.ComparatorGT, 100000, "")
+	eth := models.NewAlert("ETH", models.ComparatorLT, 3000, "")
+	quiet := models.NewAlert("SOL", models.ComparatorGT, 1, "")
+
+	report, err := Run(context.Background(), Config{Files: []string{path}, Cooldown: 30 * time.Second},
+		[]*models.Alert{btc, eth, quiet})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if report.Ticks != 6 || !report.Start.Equal(start) || !report.End.Equal(start.Add(5*time.Minute)) {
+		t.Errorf("Unexpected replay summary: %d ticks from %v to %v", report.Ticks, report.Start, report.End)
+	}
+
+	expected := []struct {
+		triggers    int
+		first, last time.Time
+	}{
+		{2, start.Add(10 * time.Second), start.Add(40 * time.Second)},
+		{1, start.Add(50 * time.Second), start.Add(50 * time.Second)},
+		{0, time.Time{}, time.Time{}},
+	}
+
+	for i, want := range expected {
+		got := report.Alerts[i]
+		if got.Triggers != want.triggers || !got.First.Equal(want.first) || !got.Last.Equal(want.last) {
+			t.Errorf("Alert %d: got %d triggers from %v to %v, expected %d from %v to %v",
+				i, got.Triggers, got.First, got.Last, want.triggers, want.first, want.last)
+		}
+	}
+
+	if len(report.Triggers) != 3 || report.Triggers[2].Alert.ID != eth.ID {
+		t.Errorf("Expected 3 triggers in firing order, got %v", report.Triggers)
+	}
+	if btc.LastTrigger == nil || !btc.LastTrigger.Equal(start.Add(40*time.Second)) {
+		t.Errorf("Expected the last trigger to be stamped with tick time, got %v", btc.LastTrigger)
+	}
+
+	windowed, err := Run(context.Background(), Config{Files: []string{path}, Cooldown: 30 * time.Second, To: start.Add(30 * time.Second)},
+		[]*models.Alert{models.NewAlert("BTC", models.ComparatorGT, 100000, "")})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if windowed.Ticks != 3 || windowed.Alerts[0].Triggers != 1 {
+		t.Errorf("Expected the time range to limit the replay, got %d ticks and %d triggers", windowed.Ticks, windowed.Alerts[0].Triggers)
+	}
+}
+
+func TestLoadAlerts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.json")
+	content := `[{"symbol":"BTC","comparator":1,"threshold":100000},{"id":"eth-dip","symbol":"ETH","comparator":3,"threshold":3000,"enabled":false}]`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	alertSet, err := LoadAlerts(path)
+	if err != nil {
+		t.Fatalf("LoadAlerts() error = %v", err)
+	}
+	if len(alertSet) != 2 || alertSet[0].ID != "1" || alertSet[1].ID != "eth-dip" || !alertSet[1].Enabled {
+		t.Errorf("Unexpected alerts: %+v, %+v", alertSet[0], alertSet[1])
+	}
+}
@@ -38,7 +38,7 @@ func BenchmarkAlertEngine(b *testing.B) {
 	b.ReportAllocs()
 	
 	for i := 0; i < b.N; i++ {
-		tick := models.NewTick("BTC", 60000.0)
+		tick := models.NewTick("BTC", 60000.0, time.Now())
 		engine.ProcessTick(tick)
 	}
 }
@@ -59,7 +59,7 @@ func BenchmarkPubSubBroker(b *testing.B) {
 	b.ReportAllocs()
 	
 	for i := 0; i < b.N; i++ {
-		tick := models.NewTick("BTC", 60000.0)
+		tick := models.NewTick("BTC", 60000.0, time.Now())
 		broker.Publish(tick)
 	}
 }
@@ -95,7 +95,7 @@ func BenchmarkConcurrentAlertProcessing(b *testing.B) {
 	b.RunParallel(func(pb *testing.PB) {
 		for pb.Next() {
 			for _, symbol := range symbols {
-				tick := models.NewTick(symbol, 2000.0)
+				tick := models.NewTick(symbol, 2000.0, time.Now())
 				engine.ProcessTick(tick)
 			}
 		}
@@ -146,7 +146,7 @@ func BenchmarkHighVolumeTickProcessing(b *testing.B) {
 		for _, symbol := range symbols {
 			go func(sym string) {
 				defer wg.Done()
-				tick := models.NewTick(sym, 2000.0)
+				tick := models.NewTick(sym, 2000.0, time.Now())
 				broker.Publish(tick)
 				engine.ProcessTick(tick)
 			}(symbol)
@@ -207,7 +207,7 @@ func BenchmarkLatency(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		start := time.Now()
 		
-		tick := models.NewTick("BTC", 60000.0)
+		tick := models.NewTick("BTC", 60000.0, time.Now())
 		broker.Publish(tick)
 		engine.ProcessTick(tick)
 		
@@ -107,7 +107,7 @@ func measureAlertEvaluationThroughput(t *testing.T) {
 				return
 			case <-ticker.C:
 				for _, symbol := range symbols {
-					tick := models.NewTick(symbol, 2000.0)
+					tick := models.NewTick(symbol, 2000.0, time.Now())
 					engine.ProcessTick(tick)
 					atomic.AddInt64(&evaluationCount, int64(alertsPerSymbol))
 				}
@@ -170,7 +170,7 @@ func measurePubSubThroughput(t *testing.T) {
 				return
 			case <-ticker.C:
 				for _, symbol := range symbols {
-					tick := models.NewTick(symbol, 50000.0)
+					tick := models.NewTick(symbol, 50000.0, time.Now())
 					broker.Publish(tick)
 					atomic.AddInt64(&publishCount, 1)
 				}
@@ -315,7 +315,7 @@ func TestLatencyMetrics(t *testing.T) {
 	for i := 0; i < numTests; i++ {
 		start := time.Now()
 		
-		tick := models.NewTick("BTC", 60000.0)
+		tick := models.NewTick("BTC", 60000.0, time.Now())
 		broker.Publish(tick)
 		engine.ProcessTick(tick)
 		
@@ -0,0 +1,333 @@
+// Package candles aggregates the tick stream into OHLCV candles.
+package candles
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"crypto-price-alerts/pkg/clock"
+	"crypto-price-alerts/pkg/models"
+)
+
+type Config struct {
+	// History is how many closed candles are kept per symbol, source and
+	// interval.
+	History int
+	// CloseInterval is how often candles whose interval has ended are
+	// closed when no later tick has closed them.
+	CloseInterval time.Duration
+	// SubscriberBuffer is the per-subscriber buffer used when Subscribe is
+	// called with a non-positive size.
+	SubscriberBuffer int
+	// Clock decides when candles whose interval has ended are closed; nil
+	// uses the system clock.
+	Clock clock.Clock
+}
+
+func DefaultConfig() Config {
+	return Config{
+		History:          500,
+		CloseInterval:    time.Second,
+		SubscriberBuffer: 100,
+	}
+}
+
+type seriesKey struct {
+	symbol   string
+	source   string
+	interval time.Duration
+}
+
+// series is one symbol's candles at one interval from one source.
+type series struct {
+	current *models.Candle
+	// history holds closed candles, oldest first.
+	history []models.Candle
+}
+
+type Subscriber struct {
+	ID         string
+	Symbols    map[string]bool
+	Interval   time.Duration
+	Source     string
+	CandleChan chan *models.Candle
+	done       chan struct{}
+}
+
+func (s *Subscriber) wants(candle *models.Candle) bool {
+	return candle.Interval == s.Interval && candle.Source == s.Source && s.Symbols[candle.Symbol]
+}
+
+func (s *Subscriber) close() {
+	close(s.done)
+	close(s.CandleChan)
+}
+
+// Aggregator builds candles at every models.CandleIntervals length from the
+// ticks passed to ObserveTick and streams them to subscribers: a partial
+// candle on every tick and the final one when it closes. A candle closes
+// when a tick from a later interval arrives or, failing that, shortly after
+// its interval ends. Intervals without ticks produce no candle.
+type Aggregator struct {
+	cfg           Config
+	series        map[seriesKey]*series
+	subscribers   map[string]*Subscriber
+	defaultSource string
+	running       bool
+	stopChan      chan struct{}
+	mu            sync.RWMutex
+	wg            sync.WaitGroup
+	clock         clock.Clock
+}
+
+func NewAggregator(cfg Config) *Aggregator {
+	defaults := DefaultConfig()
+	if cfg.History <= 0 {
+		cfg.History = defaults.History
+	}
+	if cfg.CloseInterval <= 0 {
+		cfg.CloseInterval = defaults.CloseInterval
+	}
+	if cfg.SubscriberBuffer <= 0 {
+		cfg.SubscriberBuffer = defaults.SubscriberBuffer
+	}
+
+	return &Aggregator{
+		cfg:         cfg,
+		series:      make(map[seriesKey]*series),
+		subscribers: make(map[string]*Subscriber),
+		stopChan:    make(chan struct{}),
+		clock:       clock.OrReal(cfg.Clock),
+	}
+}
+
+// SetDefaultSource sets which tick source requests without an explicit
+// source read. With the default of "" ticks from every source are combined,
+// which suits a single feed.
+func (a *Aggregator) SetDefaultSource(source string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.defaultSource = source
+}
+
+func (a *Aggregator) Start(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.running {
+		return nil
+	}
+	a.running = true
+
+	a.wg.Add(1)
+	go a.run(ctx)
+
+	log.Printf("Candle aggregator started (intervals %v, %d candles of history)", models.CandleIntervals, a.cfg.History)
+	return nil
+}
+
+func (a *Aggregator) Stop() {
+	a.mu.Lock()
+	if !a.running {
+		a.mu.Unlock()
+		return
+	}
+	a.running = false
+	close(a.stopChan)
+
+	for _, subscriber := range a.subscribers {
+		subscriber.close()
+	}
+	a.subscribers = make(map[string]*Subscriber)
+	a.mu.Unlock()
+
+	a.wg.Wait()
+}
+
+func (a *Aggregator) run(ctx context.Context) {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(a.cfg.CloseInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.stopChan:
+			return
+		case <-ticker.C:
+			a.closeExpired()
+		}
+	}
+}
+
+// ObserveTick folds a tick into the current candle of every interval.
+func (a *Aggregator) ObserveTick(tick *models.Tick) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	sources := []string{tick.Source}
+	if a.defaultSource == "" && tick.Source != "" {
+		sources = append(sources, "")
+	}
+
+	for _, source := range sources {
+		for _, interval := range models.CandleIntervals {
+			key := seriesKey{symbol: tick.Symbol, source: source, interval: interval}
+			s, exists := a.series[key]
+			if !exists {
+				s = &series{}
+				a.series[key] = s
+			}
+			a.update(s, tick, source, interval)
+		}
+	}
+}
+
+func (a *Aggregator) update(s *series, tick *models.Tick, source string, interval time.Duration) {
+	if s.current != nil {
+		switch {
+		case s.current.Contains(tick.Timestamp):
+			s.current.Add(tick)
+			a.publish(s.current)
+			return
+		case tick.Timestamp.Before(s.current.Start):
+			// Too late for a candle that has already been published as
+			// closed.
+			return
+		default:
+			a.close(s)
+		}
+	}
+
+	if n := len(s.history); n > 0 && tick.Timestamp.Before(s.history[n-1].End()) {
+		return
+	}
+
+	s.current = models.NewCandle(tick, interval)
+	s.current.Source = source
+	a.publish(s.current)
+}
+
+func (a *Aggregator) close(s *series) {
+	s.current.Closed = true
+	a.publish(s.current)
+
+	s.history = append(s.history, *s.current)
+	if len(s.history) > a.cfg.History {
+		s.history = s.history[len(s.history)-a.cfg.History:]
+	}
+	s.current = nil
+}
+
+// closeExpired closes the candles whose interval has ended by the clock.
+func (a *Aggregator) closeExpired() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.clock.Now()
+
+	for _, s := range a.series {
+		if s.current != nil && !now.Before(s.current.End()) {
+			a.close(s)
+		}
+	}
+}
+
+// publish sends a copy of the candle to interested subscribers without
+// blocking; a subscriber that falls behind misses updates.
+func (a *Aggregator) publish(candle *models.Candle) {
+	for _, subscriber := range a.subscribers {
+		if !subscriber.wants(candle) {
+			continue
+		}
+
+		update := *candle
+		select {
+		case subscriber.CandleChan <- &update:
+		case <-subscriber.done:
+		default:
+		}
+	}
+}
+
+// Subscribe streams the candles of the given symbols at one interval from
+// one source, where "" means the default source.
+func (a *Aggregator) Subscribe(subscriberID string, symbols []string, interval time.Duration, source string, bufferSize int) *Subscriber {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if bufferSize <= 0 {
+		bufferSize = a.cfg.SubscriberBuffer
+	}
+	if source == "" {
+		source = a.defaultSource
+	}
+
+	if existing, exists := a.subscribers[subscriberID]; exists {
+		existing.close()
+	}
+
+	symbolSet := make(map[string]bool)
+	for _, symbol := range symbols {
+		symbolSet[symbol] = true
+	}
+
+	subscriber := &Subscriber{
+		ID:         subscriberID,
+		Symbols:    symbolSet,
+		Interval:   interval,
+		Source:     source,
+		CandleChan: make(chan *models.Candle, bufferSize),
+		done:       make(chan struct{}),
+	}
+	a.subscribers[subscriberID] = subscriber
+
+	return subscriber
+}
+
+func (a *Aggregator) Unsubscribe(subscriberID string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if subscriber, exists := a.subscribers[subscriberID]; exists {
+		subscriber.close()
+		delete(a.subscribers, subscriberID)
+	}
+}
+
+func (a *Aggregator) GetSubscriberCount() int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return len(a.subscribers)
+}
+
+// Candles returns up to limit of the most recent candles for a symbol,
+// oldest first, from the given source ("" for the default). The candle
+// still in progress is included last when partial is set.
+func (a *Aggregator) Candles(symbol, source string, interval time.Duration, limit int, partial bool) []models.Candle {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if source == "" {
+		source = a.defaultSource
+	}
+
+	s, exists := a.series[seriesKey{symbol: symbol, source: source, interval: interval}]
+	if !exists {
+		return nil
+	}
+
+	candles := s.history
+	if partial && s.current != nil {
+		candles = append(candles[:len(candles):len(candles)], *s.current)
+	}
+	if limit > 0 && len(candles) > limit {
+		candles = candles[len(candles)-limit:]
+	}
+
+	return append([]models.Candle(nil), candles...)
+}
@@ -0,0 +1,134 @@
+package candles
+
+import (
+	"testing"
+	"time"
+
+	"crypto-price-alerts/pkg/clock"
+	"crypto-price-alerts/pkg/models"
+)
+
+var start = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func tickAt(symbol string, price float64, offset time.Duration) *models.Tick {
+	return &models.Tick{Symbol: symbol, Price: price, Volume: price * 10, Source: "mock", Timestamp: start.Add(offset)}
+}
+
+func TestAggregator_BuildsCandles(t *testing.T) {
+	a := NewAggregator(DefaultConfig())
+
+	for _, tick := range []*models.Tick{
+		tickAt("BTC", 100, 0),
+		tickAt("BTC", 110, 10*time.Second),
+		tickAt("BTC", 95, 20*time.Second),
+		tickAt("BTC", 105, 50*time.Second),
+		tickAt("BTC", 107, 70*time.Second),
+	} {
+		a.ObserveTick(tick)
+	}
+
+	candles := a.Candles("BTC", "", time.Minute, 0, true)
+	if len(candles) != 2 {
+		t.Fatalf("Expected a closed and a partial candle, got %v", candles)
+	}
+
+	first := candles[0]
+	if !first.Closed || first.Open != 100 || first.High != 110 || first.Low != 95 || first.Close != 105 ||
+		first.Ticks != 4 || first.Volume != 1050 || !first.Start.Equal(start) {
+		t.Errorf("Unexpected first candle: %+v", first)
+	}
+	if second := candles[1]; second.Closed || second.Open != 107 || second.Ticks != 1 {
+		t.Errorf("Unexpected partial candle: %+v", second)
+	}
+
+	if closed := a.Candles("BTC", "", time.Minute, 0, false); len(closed) != 1 {
+		t.Errorf("Expected only the closed candle without partial, got %v", closed)
+	}
+
+	hourly := a.Candles("BTC", "mock", time.Hour, 0, true)
+	if len(hourly) != 1 || hourly[0].Ticks != 5 || hourly[0].High != 110 || hourly[0].Close != 107 {
+		t.Errorf("Unexpected hourly candles: %v", hourly)
+	}
+
+	// A tick for an interval that has already closed is dropped.
+	a.ObserveTick(tickAt("BTC", 1, 30*time.Second))
+	if candles := a.Candles("BTC", "", time.Minute, 0, true); candles[0].Low != 95 || candles[1].Low != 107 {
+		t.Errorf("Expected a late tick to be ignored, got %v", candles)
+	}
+}
+
+func TestAggregator_ClosesExpiredCandles(t *testing.T) {
+	fake := clock.NewFake(start)
+	a := NewAggregator(Config{History: 2, Clock: fake})
+
+	for i := 0; i < 4; i++ {
+		a.ObserveTick(tickAt("ETH", float64(i+1), time.Duration(i)*time.Minute))
+	}
+
+	fake.Set(start.Add(4*time.Minute - time.Nanosecond))
+	a.closeExpired()
+	if candles := a.Candles("ETH", "", time.Minute, 0, true); candles[len(candles)-1].Closed {
+		t.Errorf("Expected the last candle to stay open until its interval ends, got %v", candles)
+	}
+
+	fake.Set(start.Add(4 * time.Minute))
+	a.closeExpired()
+
+	candles := a.Candles("ETH", "", time.Minute, 0, true)
+	if len(candles) != 2 || candles[0].Close != 3 || candles[1].Close != 4 || !candles[1].Closed {
+		t.Errorf("Expected the last two closed candles, got %v", candles)
+	}
+
+	if limited := a.Candles("ETH", "", time.Minute, 1, true); len(limited) != 1 || limited[0].Close != 4 {
+		t.Errorf("Expected the limit to keep the latest candle, got %v", limited)
+	}
+
+	// The next tick opens a new candle rather than reopening a closed one.
+	a.ObserveTick(tickAt("ETH", 9, 4*time.Minute+time.Second))
+	if candles := a.Candles("ETH", "", time.Minute, 0, true); len(candles) != 3 || candles[2].Open != 9 || candles[2].Closed {
+		t.Errorf("Expected a new partial candle, got %v", candles)
+	}
+}
+
+func TestAggregator_Subscribe(t *testing.T) {
+	a := NewAggregator(DefaultConfig())
+	a.SetDefaultSource("consolidated")
+
+	sub := a.Subscribe("sub", []string{"BTC"}, time.Minute, "", 10)
+	defer a.Unsubscribe("sub")
+
+	a.ObserveTick(&models.Tick{Symbol: "BTC", Price: 100, Source: "consolidated", Timestamp: start})
+	a.ObserveTick(&models.Tick{Symbol: "BTC", Price: 200, Source: "binance", Timestamp: start})
+	a.ObserveTick(&models.Tick{Symbol: "ETH", Price: 5, Source: "consolidated", Timestamp: start})
+	a.ObserveTick(&models.Tick{Symbol: "BTC", Price: 101, Source: "consolidated", Timestamp: start.Add(time.Minute)})
+
+	expected := []struct {
+		open   float64
+		closed bool
+	}{
+		{100, false},
+		{100, true},
+		{101, false},
+	}
+
+	for _, want := range expected {
+		select {
+		case candle := <-sub.CandleChan:
+			if candle.Open != want.open || candle.Closed != want.closed || candle.Interval != time.Minute {
+				t.Errorf("Got %+v, expected open %v closed %v", candle, want.open, want.closed)
+			}
+		default:
+			t.Fatalf("Expected a candle update (open %v closed %v)", want.open, want.closed)
+		}
+	}
+
+	select {
+	case candle := <-sub.CandleChan:
+		t.Errorf("Unexpected candle update: %+v", candle)
+	default:
+	}
+
+	if candles := a.Candles("BTC", "binance", time.Minute, 0, true); len(candles) != 1 || candles[0].Open != 200 {
+		t.Errorf("Expected venue candles to be kept apart, got %v", candles)
+	}
+}
@@ -0,0 +1,498 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"crypto-price-alerts/internal/alerts"
+	"crypto-price-alerts/internal/candles"
+	"crypto-price-alerts/internal/datafeed"
+	"crypto-price-alerts/internal/history"
+	"crypto-price-alerts/internal/notify"
+	"crypto-price-alerts/internal/pubsub"
+	"crypto-price-alerts/internal/triggerhistory"
+)
+
+// Config holds every server setting. Load fills it from, in increasing order
+// of precedence: the defaults, a YAML or TOML file, environment variables and
+// command-line flags.
+type Config struct {
+	Server         Server         `yaml:"server" toml:"server"`
+	Store          Store          `yaml:"store" toml:"store"`
+	Feed           Feed           `yaml:"feed" toml:"feed"`
+	Broker         Broker         `yaml:"broker" toml:"broker"`
+	Engine         Engine         `yaml:"engine" toml:"engine"`
+	Triggers       Triggers       `yaml:"triggers" toml:"triggers"`
+	Webhooks       Webhooks       `yaml:"webhooks" toml:"webhooks"`
+	Health         Health         `yaml:"health" toml:"health"`
+	Watchdog       Watchdog       `yaml:"watchdog" toml:"watchdog"`
+	Candles        Candles        `yaml:"candles" toml:"candles"`
+	History        History        `yaml:"history" toml:"history"`
+	Recorder       Recorder       `yaml:"recorder" toml:"recorder"`
+	TriggerHistory TriggerHistory `yaml:"trigger_history" toml:"trigger_history"`
+}
+
+type Server struct {
+	Address     string `yaml:"address" toml:"address"`
+	HTTPAddress string `yaml:"http_address" toml:"http_address"`
+	TLSCert     string `yaml:"tls_cert" toml:"tls_cert"`
+	TLSKey      string `yaml:"tls_key" toml:"tls_key"`
+	TLSClientCA string `yaml:"tls_client_ca" toml:"tls_client_ca"`
+	AuthKeys    string `yaml:"auth_keys" toml:"auth_keys"`
+}
+
+type Store struct {
+	Backend string `yaml:"backend" toml:"backend"`
+	DataDir string `yaml:"data_dir" toml:"data_dir"`
+}
+
+type Feed struct {
+	Name       string        `yaml:"name" toml:"name"`
+	Endpoint   string        `yaml:"endpoint" toml:"endpoint"`
+	Symbols    []string      `yaml:"symbols" toml:"symbols"`
+	TickRate   time.Duration `yaml:"tick_rate" toml:"tick_rate"`
+	BufferSize int           `yaml:"buffer_size" toml:"buffer_size"`
+
+	Venues        []string      `yaml:"venues" toml:"venues"`
+	Consolidation string        `yaml:"consolidation" toml:"consolidation"`
+	PrimaryVenue  string        `yaml:"primary_venue" toml:"primary_venue"`
+	Staleness     time.Duration `yaml:"staleness" toml:"staleness"`
+
+	ReplayFiles []string `yaml:"replay_files" toml:"replay_files"`
+	ReplaySpeed float64  `yaml:"replay_speed" toml:"replay_speed"`
+}
+
+type Broker struct {
+	TickBuffer       int `yaml:"tick_buffer" toml:"tick_buffer"`
+	SubscriberBuffer int `yaml:"subscriber_buffer" toml:"subscriber_buffer"`
+}
+
+type Engine struct {
+	Cooldown        time.Duration `yaml:"cooldown" toml:"cooldown"`
+	TickBuffer      int           `yaml:"tick_buffer" toml:"tick_buffer"`
+	CleanupInterval time.Duration `yaml:"cleanup_interval" toml:"cleanup_interval"`
+}
+
+type Triggers struct {
+	Buffer           int `yaml:"buffer" toml:"buffer"`
+	SubscriberBuffer int `yaml:"subscriber_buffer" toml:"subscriber_buffer"`
+	LogSize          int `yaml:"log_size" toml:"log_size"`
+}
+
+type Webhooks struct {
+	// Endpoints use the URL[;timeout=5s][;secret=...] form.
+	Endpoints []string `yaml:"endpoints" toml:"endpoints"`
+	// AlertHosts are the hosts alerts may send their own webhooks to, either
+	// exact or "*.example.com"; empty disables per-alert webhooks.
+	AlertHosts       []string      `yaml:"alert_hosts" toml:"alert_hosts"`
+	Secret           string        `yaml:"secret" toml:"secret"`
+	Timeout          time.Duration `yaml:"timeout" toml:"timeout"`
+	MaxAttempts      int           `yaml:"max_attempts" toml:"max_attempts"`
+	Workers          int           `yaml:"workers" toml:"workers"`
+	SubscriberBuffer int           `yaml:"subscriber_buffer" toml:"subscriber_buffer"`
+}
+
+type Health struct {
+	// MaxTickAge is how long a symbol may go without a tick before the
+	// server reports itself not ready.
+	MaxTickAge    time.Duration `yaml:"max_tick_age" toml:"max_tick_age"`
+	CheckInterval time.Duration `yaml:"check_interval" toml:"check_interval"`
+}
+
+type Watchdog struct {
+	// StaleAfter is how long a symbol may go without a tick before a
+	// feed-stale event is published; zero disables the watchdog.
+	StaleAfter    time.Duration `yaml:"stale_after" toml:"stale_after"`
+	CheckInterval time.Duration `yaml:"check_interval" toml:"check_interval"`
+}
+
+type Candles struct {
+	// History is how many closed candles are kept per symbol, source and
+	// interval.
+	History int `yaml:"history" toml:"history"`
+}
+
+type History struct {
+	// RawRetention is how long every tick is kept; the 1m and 1h bars they
+	// are downsampled into are kept for MinuteRetention and HourRetention.
+	// Zero disables a tier.
+	RawRetention    time.Duration `yaml:"raw_retention" toml:"raw_retention"`
+	MinuteRetention time.Duration `yaml:"minute_retention" toml:"minute_retention"`
+	HourRetention   time.Duration `yaml:"hour_retention" toml:"hour_retention"`
+}
+
+type Recorder struct {
+	// Dir is where every feed tick is recorded; empty disables the recorder.
+	Dir             string        `yaml:"dir" toml:"dir"`
+	SegmentDuration time.Duration `yaml:"segment_duration" toml:"segment_duration"`
+	SegmentSizeMB   int           `yaml:"segment_size_mb" toml:"segment_size_mb"`
+	// Retention is how long segments are kept; zero keeps them all.
+	Retention time.Duration `yaml:"retention" toml:"retention"`
+}
+
+type TriggerHistory struct {
+	// Retention is how long past triggers are kept for ListTriggers, and
+	// MaxRecords how many; zero lifts either limit.
+	Retention  time.Duration `yaml:"retention" toml:"retention"`
+	MaxRecords int           `yaml:"max_records" toml:"max_records"`
+}
+
+func Default() *Config {
+	broker := pubsub.DefaultBrokerConfig()
+	engine := alerts.DefaultEngineConfig()
+	triggers := alerts.DefaultTriggerBusConfig()
+	webhooks := notify.DefaultConfig()
+	watchdog := alerts.DefaultWatchdogConfig()
+	candleConfig := candles.DefaultConfig()
+	historyConfig := history.DefaultConfig()
+	recorder := datafeed.DefaultRecorderConfig()
+	triggerHistory := triggerhistory.DefaultConfig()
+
+	return &Config{
+		Server: Server{
+			Address:     ":9090",
+			HTTPAddress: ":9091",
+		},
+		Store: Store{
+			Backend: "memory",
+			DataDir: "data",
+		},
+		Feed: Feed{
+			Name:          "binance",
+			Symbols:       []string{"BTC", "ETH", "ADA", "SOL", "DOT", "MATIC", "AVAX", "LINK"},
+			TickRate:      200 * time.Millisecond,
+			BufferSize:    1000,
+			Venues:        []string{"binance"},
+			Consolidation: "median",
+			PrimaryVenue:  "binance",
+			Staleness:     30 * time.Second,
+			ReplaySpeed:   1,
+		},
+		Broker: Broker{
+			TickBuffer:       broker.TickBuffer,
+			SubscriberBuffer: broker.SubscriberBuffer,
+		},
+		Engine: Engine{
+			Cooldown:        engine.Cooldown,
+			TickBuffer:      engine.TickBuffer,
+			CleanupInterval: 5 * time.Minute,
+		},
+		Triggers: Triggers{
+			Buffer:           triggers.Buffer,
+			SubscriberBuffer: triggers.SubscriberBuffer,
+			LogSize:          10000,
+		},
+		Webhooks: Webhooks{
+			Timeout:          webhooks.Timeout,
+			MaxAttempts:      webhooks.MaxAttempts,
+			Workers:          webhooks.Workers,
+			SubscriberBuffer: webhooks.SubscriberBuffer,
+		},
+		Health: Health{
+			MaxTickAge:    time.Minute,
+			CheckInterval: 5 * time.Second,
+		},
+		Watchdog: Watchdog{
+			StaleAfter:    watchdog.StaleAfter,
+			CheckInterval: watchdog.CheckInterval,
+		},
+		Candles: Candles{
+			History: candleConfig.History,
+		},
+		History: History{
+			RawRetention:    historyConfig.RawRetention,
+			MinuteRetention: historyConfig.MinuteRetention,
+			HourRetention:   historyConfig.HourRetention,
+		},
+		Recorder: Recorder{
+			SegmentDuration: recorder.SegmentDuration,
+			SegmentSizeMB:   int(recorder.SegmentSize >> 20),
+		},
+		TriggerHistory: TriggerHistory{
+			Retention:  triggerHistory.Retention,
+			MaxRecords: triggerHistory.MaxRecords,
+		},
+	}
+}
+
+// Load builds the configuration from args (without the program name). The
+// file is named by -config or $CONFIG_FILE; its format follows the extension.
+// flag.ErrHelp is returned when -h was given.
+func Load(args []string) (*Config, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	configPath := fs.String("config", os.Getenv("CONFIG_FILE"), "YAML or TOML configuration file [$CONFIG_FILE]")
+
+	var flagValues []flagValue
+	for i := range settings {
+		s := &settings[i]
+		usage := s.usage
+		if s.env != "" {
+			usage += " [$" + s.env + "]"
+		}
+		fs.Var(&flagValue{setting: s, values: &flagValues}, s.flag, usage)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *configPath != "" {
+		if err := cfg.loadFile(*configPath); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range settings {
+		if s.env == "" {
+			continue
+		}
+		if value, exists := os.LookupEnv(s.env); exists && value != "" {
+			if err := s.set(cfg, value); err != nil {
+				return nil, fmt.Errorf("invalid $%s: %v", s.env, err)
+			}
+		}
+	}
+
+	for _, v := range flagValues {
+		if err := v.setting.set(cfg, v.value); err != nil {
+			return nil, fmt.Errorf("invalid -%s: %v", v.setting.flag, err)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to parse %s: %v", path, err)
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(data), c)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %v", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("failed to parse %s: unknown key %q", path, undecoded[0].String())
+		}
+	default:
+		return fmt.Errorf("unsupported config file %s (expected .yaml, .yml or .toml)", path)
+	}
+
+	return nil
+}
+
+// Validate reports every invalid setting at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Server.Address != "", "server.address is required")
+	check(c.Server.TLSCert == "" || c.Server.TLSKey != "", "server.tls_key is required with server.tls_cert")
+	check(c.Server.TLSClientCA == "" || c.Server.TLSCert != "", "server.tls_client_ca requires server.tls_cert")
+
+	check(c.Store.Backend == "memory" || c.Store.Backend == "file", "unknown store backend %q", c.Store.Backend)
+	check(c.Store.Backend != "file" || c.Store.DataDir != "", "store.data_dir is required for the file backend")
+
+	check(slices.Contains(datafeed.Available(), c.Feed.Name), "unknown data feed %q (available: %v)", c.Feed.Name, datafeed.Available())
+	check(len(c.Feed.Symbols) > 0, "feed.symbols must not be empty")
+	check(c.Feed.TickRate > 0, "feed.tick_rate must be positive")
+	check(c.Feed.BufferSize > 0, "feed.buffer_size must be positive")
+	check(c.Feed.Staleness > 0, "feed.staleness must be positive")
+	check(c.Feed.Name != "replay" || len(c.Feed.ReplayFiles) > 0, "feed.replay_files is required for the replay feed")
+	check(c.Feed.ReplaySpeed >= 0, "feed.replay_speed must not be negative")
+	if method, err := datafeed.ParseConsolidationMethod(c.Feed.Consolidation); err != nil {
+		errs = append(errs, err)
+	} else if c.Feed.Name == "aggregate" {
+		check(len(c.Feed.Venues) > 0, "feed.venues must not be empty for the aggregate feed")
+		check(len(slices.Compact(slices.Sorted(slices.Values(c.Feed.Venues)))) == len(c.Feed.Venues),
+			"feed.venues %v must not list a venue more than once", c.Feed.Venues)
+		check(method != datafeed.ConsolidationPrimary || slices.Contains(c.Feed.Venues, c.Feed.PrimaryVenue),
+			"feed.primary_venue %q must be one of feed.venues %v", c.Feed.PrimaryVenue, c.Feed.Venues)
+	}
+
+	check(c.Broker.TickBuffer > 0, "broker.tick_buffer must be positive")
+	check(c.Broker.SubscriberBuffer > 0, "broker.subscriber_buffer must be positive")
+
+	check(c.Engine.Cooldown >= 0, "engine.cooldown must not be negative")
+	check(c.Engine.TickBuffer > 0, "engine.tick_buffer must be positive")
+	check(c.Engine.CleanupInterval > 0, "engine.cleanup_interval must be positive")
+
+	check(c.Triggers.Buffer > 0, "triggers.buffer must be positive")
+	check(c.Triggers.SubscriberBuffer > 0, "triggers.subscriber_buffer must be positive")
+	check(c.Triggers.LogSize > 0, "triggers.log_size must be positive")
+
+	if _, err := c.webhookEndpoints(); err != nil {
+		errs = append(errs, err)
+	}
+	check(c.Webhooks.Timeout > 0, "webhooks.timeout must be positive")
+	check(c.Webhooks.MaxAttempts > 0, "webhooks.max_attempts must be positive")
+	check(c.Webhooks.Workers > 0, "webhooks.workers must be positive")
+	check(c.Webhooks.SubscriberBuffer > 0, "webhooks.subscriber_buffer must be positive")
+
+	check(c.Health.MaxTickAge > 0, "health.max_tick_age must be positive")
+	check(c.Health.CheckInterval > 0, "health.check_interval must be positive")
+
+	check(c.Watchdog.StaleAfter >= 0, "watchdog.stale_after must not be negative")
+	check(c.Watchdog.CheckInterval > 0, "watchdog.check_interval must be positive")
+
+	check(c.Candles.History > 0, "candles.history must be positive")
+
+	check(c.History.RawRetention >= 0, "history.raw_retention must not be negative")
+	check(c.History.MinuteRetention >= 0, "history.minute_retention must not be negative")
+	check(c.History.HourRetention >= 0, "history.hour_retention must not be negative")
+
+	check(c.Recorder.SegmentDuration > 0, "recorder.segment_duration must be positive")
+	check(c.Recorder.SegmentSizeMB > 0, "recorder.segment_size_mb must be positive")
+	check(c.Recorder.Retention >= 0, "recorder.retention must not be negative")
+
+	check(c.TriggerHistory.Retention >= 0, "trigger_history.retention must not be negative")
+	check(c.TriggerHistory.MaxRecords >= 0, "trigger_history.max_records must not be negative")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %v", errors.Join(errs...))
+	}
+	return nil
+}
+
+func (c *Config) BrokerConfig() pubsub.BrokerConfig {
+	return pubsub.BrokerConfig{
+		TickBuffer:       c.Broker.TickBuffer,
+		SubscriberBuffer: c.Broker.SubscriberBuffer,
+	}
+}
+
+func (c *Config) EngineConfig() alerts.EngineConfig {
+	return alerts.EngineConfig{
+		Cooldown:   c.Engine.Cooldown,
+		TickBuffer: c.Engine.TickBuffer,
+	}
+}
+
+func (c *Config) TriggerBusConfig() alerts.TriggerBusConfig {
+	return alerts.TriggerBusConfig{
+		Buffer:           c.Triggers.Buffer,
+		SubscriberBuffer: c.Triggers.SubscriberBuffer,
+	}
+}
+
+func (c *Config) WatchdogConfig() alerts.WatchdogConfig {
+	return alerts.WatchdogConfig{
+		StaleAfter:    c.Watchdog.StaleAfter,
+		CheckInterval: c.Watchdog.CheckInterval,
+		Symbols:       c.Feed.Symbols,
+	}
+}
+
+func (c *Config) CandlesConfig() candles.Config {
+	return candles.Config{
+		History: c.Candles.History,
+	}
+}
+
+// HistoryConfig saves the downsampled price history in the data directory
+// when alerts are persisted there too.
+func (c *Config) HistoryConfig() history.Config {
+	cfg := history.DefaultConfig()
+	cfg.RawRetention = c.History.RawRetention
+	cfg.MinuteRetention = c.History.MinuteRetention
+	cfg.HourRetention = c.History.HourRetention
+	if c.Store.Backend == "file" {
+		cfg.Path = filepath.Join(c.Store.DataDir, "price_history.gob.gz")
+	}
+	return cfg
+}
+
+// TriggerHistoryConfig saves the trigger history in the data directory when
+// alerts are persisted there too.
+func (c *Config) TriggerHistoryConfig() triggerhistory.Config {
+	cfg := triggerhistory.DefaultConfig()
+	cfg.Retention = c.TriggerHistory.Retention
+	cfg.MaxRecords = c.TriggerHistory.MaxRecords
+	if c.Store.Backend == "file" {
+		cfg.Path = filepath.Join(c.Store.DataDir, "trigger_history.jsonl")
+	}
+	return cfg
+}
+
+func (c *Config) FeedConfig() datafeed.Config {
+	return datafeed.Config{
+		Symbols:       c.Feed.Symbols,
+		TickRate:      c.Feed.TickRate,
+		Endpoint:      c.Feed.Endpoint,
+		BufferSize:    c.Feed.BufferSize,
+		Venues:        c.Feed.Venues,
+		Consolidation: c.Feed.Consolidation,
+		Primary:       c.Feed.PrimaryVenue,
+		Staleness:     c.Feed.Staleness,
+		ReplayFiles:   c.Feed.ReplayFiles,
+		ReplaySpeed:   c.Feed.ReplaySpeed,
+	}
+}
+
+func (c *Config) RecorderConfig() datafeed.RecorderConfig {
+	cfg := datafeed.DefaultRecorderConfig()
+	cfg.Dir = c.Recorder.Dir
+	cfg.SegmentDuration = c.Recorder.SegmentDuration
+	cfg.SegmentSize = int64(c.Recorder.SegmentSizeMB) << 20
+	cfg.Retention = c.Recorder.Retention
+	return cfg
+}
+
+func (c *Config) NotifierConfig() notify.Config {
+	// Validate has already parsed the endpoints.
+	endpoints, _ := c.webhookEndpoints()
+
+	cfg := notify.DefaultConfig()
+	cfg.Endpoints = endpoints
+	cfg.AlertHosts = c.Webhooks.AlertHosts
+	cfg.Secret = c.Webhooks.Secret
+	cfg.Timeout = c.Webhooks.Timeout
+	cfg.MaxAttempts = c.Webhooks.MaxAttempts
+	cfg.Workers = c.Webhooks.Workers
+	cfg.SubscriberBuffer = c.Webhooks.SubscriberBuffer
+	return cfg
+}
+
+func (c *Config) webhookEndpoints() ([]notify.Endpoint, error) {
+	var endpoints []notify.Endpoint
+	for _, spec := range c.Webhooks.Endpoints {
+		if strings.TrimSpace(spec) == "" {
+			continue
+		}
+		endpoint, err := notify.ParseEndpoint(spec)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints, nil
+}
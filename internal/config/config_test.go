@@ -0,0 +1,123 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeFile(t, "server.yaml", `
+server:
+  address: ":7000"
+feed:
+  name: mock
+  symbols: [BTC, ETH]
+  tick_rate: 1s
+engine:
+  cooldown: 1m
+broker:
+  tick_buffer: 500
+`)
+
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("ALERT_COOLDOWN", "2m")
+	t.Setenv("TICK_RATE", "2s")
+
+	cfg, err := Load([]string{"-tick-rate", "3s", "-symbols", "SOL, ADA"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		got      interface{}
+		expected interface{}
+	}{
+		{"default", cfg.Store.Backend, "memory"},
+		{"file over default", cfg.Server.Address, ":7000"},
+		{"file over default", cfg.Broker.TickBuffer, 500},
+		{"env over file", cfg.Engine.Cooldown, 2 * time.Minute},
+		{"flag over env", cfg.Feed.TickRate, 3 * time.Second},
+		{"flag over file", strings.Join(cfg.Feed.Symbols, ","), "SOL,ADA"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("%s: got %v, expected %v", tt.name, tt.got, tt.expected)
+		}
+	}
+}
+
+func TestLoad_TOML(t *testing.T) {
+	path := writeFile(t, "server.toml", `
+[feed]
+name = "mock"
+tick_rate = "500ms"
+
+[triggers]
+log_size = 42
+
+[webhooks]
+endpoints = ["https://hooks.example.com/alerts;timeout=2s"]
+`)
+
+	cfg, err := Load([]string{"-config", path})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if cfg.Feed.TickRate != 500*time.Millisecond || cfg.Triggers.LogSize != 42 {
+		t.Errorf("Unexpected config: %+v %+v", cfg.Feed, cfg.Triggers)
+	}
+	endpoints := cfg.NotifierConfig().Endpoints
+	if len(endpoints) != 1 || endpoints[0].Timeout != 2*time.Second {
+		t.Errorf("Unexpected webhook endpoints: %+v", endpoints)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		args    []string
+		errText string
+	}{
+		{"unknown YAML key", "server.yaml", "feed:\n  nme: mock\n", nil, "field nme not found"},
+		{"unknown TOML key", "server.toml", "[feed]\nnme = \"mock\"\n", nil, "unknown key"},
+		{"unsupported extension", "server.json", "{}", nil, "unsupported config file"},
+		{"bad flag value", "", "", []string{"-cooldown", "soon"}, "invalid duration"},
+		{"unknown feed", "", "", []string{"-feed", "nasdaq"}, "unknown data feed"},
+		{"negative buffer", "", "", []string{"-broker-buffer", "-1"}, "broker.tick_buffer must be positive"},
+		{"client CA without cert", "", "", []string{"-tls-client-ca", "ca.pem"}, "requires server.tls_cert"},
+		{"bad webhook", "", "", []string{"-webhooks", "ftp://example.com"}, "invalid webhook URL"},
+		{"primary venue not aggregated", "", "", []string{"-feed", "aggregate", "-consolidation", "primary", "-primary-venue", "coinbase"}, "must be one of feed.venues"},
+		{"repeated venue", "", "", []string{"-feed", "aggregate", "-venues", "mock,binance,mock"}, "more than once"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := tt.args
+			if tt.file != "" {
+				args = append([]string{"-config", writeFile(t, tt.file, tt.content)}, args...)
+			}
+
+			_, err := Load(args)
+			if err == nil || !strings.Contains(err.Error(), tt.errText) {
+				t.Errorf("Load() error = %v, expected it to contain %q", err, tt.errText)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// setting maps one config field to its flag and environment variable.
+type setting struct {
+	flag  string
+	env   string
+	usage string
+	get   func(c *Config) string
+	set   func(c *Config, value string) error
+}
+
+var settings = []setting{
+	stringSetting("addr", "LISTEN_ADDR", "gRPC listen address", func(c *Config) *string { return &c.Server.Address }),
+	stringSetting("http-addr", "HTTP_ADDR", "HTTP listen address for /metrics, /healthz and /readyz; empty disables it", func(c *Config) *string { return &c.Server.HTTPAddress }),
+	stringSetting("tls-cert", "TLS_CERT", "Server certificate (PEM); enables TLS", func(c *Config) *string { return &c.Server.TLSCert }),
+	stringSetting("tls-key", "TLS_KEY", "Server private key (PEM)", func(c *Config) *string { return &c.Server.TLSKey }),
+	stringSetting("tls-client-ca", "TLS_CLIENT_CA", "CA for client certificates; enables mutual TLS", func(c *Config) *string { return &c.Server.TLSClientCA }),
+	stringSetting("auth-keys", "AUTH_KEYS_FILE", "Key file with API keys and token keys; enables authentication", func(c *Config) *string { return &c.Server.AuthKeys }),
+
+	stringSetting("store", "STORE_BACKEND", "Alert storage backend (memory or file)", func(c *Config) *string { return &c.Store.Backend }),
+	stringSetting("data-dir", "DATA_DIR", "Directory for the file storage backend", func(c *Config) *string { return &c.Store.DataDir }),
+
+	stringSetting("feed", "FEED", "Price data feed", func(c *Config) *string { return &c.Feed.Name }),
+	stringSetting("feed-endpoint", "FEED_ENDPOINT", "Override the feed's WebSocket endpoint", func(c *Config) *string { return &c.Feed.Endpoint }),
+	listSetting("symbols", "SYMBOLS", "Comma-separated symbols to stream", func(c *Config) *[]string { return &c.Feed.Symbols }),
+	durationSetting("tick-rate", "TICK_RATE", "Tick interval for simulated feeds", func(c *Config) *time.Duration { return &c.Feed.TickRate }),
+	intSetting("feed-buffer", "FEED_BUFFER", "Feed tick channel capacity", func(c *Config) *int { return &c.Feed.BufferSize }),
+	listSetting("venues", "FEED_VENUES", "Comma-separated venues for the aggregate feed", func(c *Config) *[]string { return &c.Feed.Venues }),
+	stringSetting("consolidation", "FEED_CONSOLIDATION", "Consolidated price method for the aggregate feed: median, vwap or primary", func(c *Config) *string { return &c.Feed.Consolidation }),
+	stringSetting("primary-venue", "FEED_PRIMARY", "Preferred venue for primary consolidation", func(c *Config) *string { return &c.Feed.PrimaryVenue }),
+	durationSetting("staleness", "FEED_STALENESS", "Maximum venue quote age for the aggregate feed", func(c *Config) *time.Duration { return &c.Feed.Staleness }),
+	listSetting("replay-files", "FEED_REPLAY_FILES", "Comma-separated recorded tick files for the replay feed", func(c *Config) *[]string { return &c.Feed.ReplayFiles }),
+	floatSetting("replay-speed", "FEED_REPLAY_SPEED", "Replay speed relative to the recording; 0 replays as fast as possible", func(c *Config) *float64 { return &c.Feed.ReplaySpeed }),
+
+	intSetting("broker-buffer", "BROKER_BUFFER", "Ticks queued for fan-out to price subscribers", func(c *Config) *int { return &c.Broker.TickBuffer }),
+	intSetting("broker-subscriber-buffer", "BROKER_SUBSCRIBER_BUFFER", "Ticks buffered per price subscriber", func(c *Config) *int { return &c.Broker.SubscriberBuffer }),
+
+	durationSetting("cooldown", "ALERT_COOLDOWN", "Minimum time between triggers of the same alert", func(c *Config) *time.Duration { return &c.Engine.Cooldown }),
+	intSetting("engine-buffer", "ENGINE_BUFFER", "Ticks queued for alert evaluation", func(c *Config) *int { return &c.Engine.TickBuffer }),
+	durationSetting("cleanup-interval", "CLEANUP_INTERVAL", "How often expired cooldowns are cleaned up and expired alerts disabled", func(c *Config) *time.Duration { return &c.Engine.CleanupInterval }),
+
+	intSetting("trigger-buffer", "TRIGGER_BUFFER", "Triggers queued for fan-out to alert subscribers", func(c *Config) *int { return &c.Triggers.Buffer }),
+	intSetting("trigger-subscriber-buffer", "TRIGGER_SUBSCRIBER_BUFFER", "Triggers buffered per alert subscriber", func(c *Config) *int { return &c.Triggers.SubscriberBuffer }),
+	intSetting("trigger-log-size", "TRIGGER_LOG_SIZE", "Triggers kept for stream resumption", func(c *Config) *int { return &c.Triggers.LogSize }),
+
+	listSetting("webhooks", "WEBHOOK_URLS", "Comma-separated webhook endpoints notified on every trigger, each URL[;timeout=5s][;secret=...]", func(c *Config) *[]string { return &c.Webhooks.Endpoints }),
+	listSetting("alert-webhook-hosts", "ALERT_WEBHOOK_HOSTS", "Comma-separated hosts alerts may set their own webhook URL to, e.g. hooks.example.com or *.example.com; empty disables per-alert webhooks", func(c *Config) *[]string { return &c.Webhooks.AlertHosts }),
+	stringSetting("webhook-secret", "WEBHOOK_SECRET", "Default HMAC secret for signing webhook requests", func(c *Config) *string { return &c.Webhooks.Secret }),
+	durationSetting("webhook-timeout", "WEBHOOK_TIMEOUT", "Default per-request webhook timeout", func(c *Config) *time.Duration { return &c.Webhooks.Timeout }),
+	intSetting("webhook-attempts", "WEBHOOK_MAX_ATTEMPTS", "Delivery attempts before a webhook is dead-lettered", func(c *Config) *int { return &c.Webhooks.MaxAttempts }),
+	intSetting("webhook-workers", "WEBHOOK_WORKERS", "Concurrent webhook deliveries", func(c *Config) *int { return &c.Webhooks.Workers }),
+
+	durationSetting("max-tick-age", "MAX_TICK_AGE", "Longest a symbol may go without a tick before the server is not ready", func(c *Config) *time.Duration { return &c.Health.MaxTickAge }),
+	durationSetting("stale-after", "STALE_AFTER", "Silence after which a symbol's feed-stale event is published; 0 disables the watchdog", func(c *Config) *time.Duration { return &c.Watchdog.StaleAfter }),
+	durationSetting("health-interval", "HEALTH_INTERVAL", "How often the gRPC health status is refreshed", func(c *Config) *time.Duration { return &c.Health.CheckInterval }),
+
+	intSetting("candle-history", "CANDLE_HISTORY", "Closed candles kept per symbol and interval", func(c *Config) *int { return &c.Candles.History }),
+	durationSetting("history-retention", "HISTORY_RETENTION", "How long every tick is kept for price history queries", func(c *Config) *time.Duration { return &c.History.RawRetention }),
+	durationSetting("history-minute-retention", "HISTORY_MINUTE_RETENTION", "How long 1m price history bars are kept; 0 disables them", func(c *Config) *time.Duration { return &c.History.MinuteRetention }),
+	durationSetting("history-hour-retention", "HISTORY_HOUR_RETENTION", "How long 1h price history bars are kept; 0 disables them", func(c *Config) *time.Duration { return &c.History.HourRetention }),
+
+	stringSetting("record-dir", "RECORD_DIR", "Directory to record every feed tick to for replay; empty disables recording", func(c *Config) *string { return &c.Recorder.Dir }),
+	durationSetting("record-segment-duration", "RECORD_SEGMENT_DURATION", "How long a recording segment is written before the next is started", func(c *Config) *time.Duration { return &c.Recorder.SegmentDuration }),
+	intSetting("record-segment-mb", "RECORD_SEGMENT_MB", "Uncompressed megabytes per recording segment", func(c *Config) *int { return &c.Recorder.SegmentSizeMB }),
+	durationSetting("record-retention", "RECORD_RETENTION", "How long recording segments are kept; 0 keeps them all", func(c *Config) *time.Duration { return &c.Recorder.Retention }),
+
+	durationSetting("trigger-history-retention", "TRIGGER_HISTORY_RETENTION", "How long past triggers are kept for ListTriggers; 0 keeps them until the count limit", func(c *Config) *time.Duration { return &c.TriggerHistory.Retention }),
+	intSetting("trigger-history-max", "TRIGGER_HISTORY_MAX", "Past triggers kept for ListTriggers; 0 is unlimited", func(c *Config) *int { return &c.TriggerHistory.MaxRecords }),
+}
+
+func stringSetting(flag, env, usage string, field func(c *Config) *string) setting {
+	return setting{
+		flag:  flag,
+		env:   env,
+		usage: usage,
+		get:   func(c *Config) string { return *field(c) },
+		set: func(c *Config, value string) error {
+			*field(c) = value
+			return nil
+		},
+	}
+}
+
+func listSetting(flag, env, usage string, field func(c *Config) *[]string) setting {
+	return setting{
+		flag:  flag,
+		env:   env,
+		usage: usage,
+		get:   func(c *Config) string { return strings.Join(*field(c), ",") },
+		set: func(c *Config, value string) error {
+			var items []string
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			*field(c) = items
+			return nil
+		},
+	}
+}
+
+func intSetting(flag, env, usage string, field func(c *Config) *int) setting {
+	return setting{
+		flag:  flag,
+		env:   env,
+		usage: usage,
+		get:   func(c *Config) string { return strconv.Itoa(*field(c)) },
+		set: func(c *Config, value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid number %q", value)
+			}
+			*field(c) = n
+			return nil
+		},
+	}
+}
+
+func floatSetting(flag, env, usage string, field func(c *Config) *float64) setting {
+	return setting{
+		flag:  flag,
+		env:   env,
+		usage: usage,
+		get:   func(c *Config) string { return strconv.FormatFloat(*field(c), 'g', -1, 64) },
+		set: func(c *Config, value string) error {
+			f, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("invalid number %q", value)
+			}
+			*field(c) = f
+			return nil
+		},
+	}
+}
+
+func durationSetting(flag, env, usage string, field func(c *Config) *time.Duration) setting {
+	return setting{
+		flag:  flag,
+		env:   env,
+		usage: usage,
+		get:   func(c *Config) string { return field(c).String() },
+		set: func(c *Config, value string) error {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("invalid duration %q", value)
+			}
+			*field(c) = d
+			return nil
+		},
+	}
+}
+
+// flagValue records a flag as it is parsed so that it can be applied after
+// the file and the environment.
+type flagValue struct {
+	setting *setting
+	values  *[]flagValue
+	value   string
+}
+
+func (v *flagValue) String() string {
+	if v == nil || v.setting == nil {
+		return ""
+	}
+	return v.setting.get(Default())
+}
+
+func (v *flagValue) Set(value string) error {
+	// Parse the value now so mistakes are reported against the flag.
+	if err := v.setting.set(Default(), value); err != nil {
+		return err
+	}
+	*v.values = append(*v.values, flagValue{setting: v.setting, value: value})
+	return nil
+}
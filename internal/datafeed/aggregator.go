@@ -0,0 +1,350 @@
+package datafeed
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"crypto-price-alerts/internal/metrics"
+	"crypto-price-alerts/pkg/models"
+)
+
+type ConsolidationMethod int
+
+const (
+	ConsolidationMedian ConsolidationMethod = iota
+	ConsolidationVolumeWeighted
+	ConsolidationPrimary
+)
+
+func (m ConsolidationMethod) String() string {
+	switch m {
+	case ConsolidationMedian:
+		return "median"
+	case ConsolidationVolumeWeighted:
+		return "vwap"
+	case ConsolidationPrimary:
+		return "primary"
+	default:
+		return "unknown"
+	}
+}
+
+func ParseConsolidationMethod(name string) (ConsolidationMethod, error) {
+	switch strings.ToLower(name) {
+	case "", "median":
+		return ConsolidationMedian, nil
+	case "vwap", "volume-weighted", "volume_weighted":
+		return ConsolidationVolumeWeighted, nil
+	case "primary":
+		return ConsolidationPrimary, nil
+	default:
+		return ConsolidationMedian, fmt.Errorf("unknown consolidation method %q", name)
+	}
+}
+
+const defaultStaleness = 30 * time.Second
+
+var quoteSuffixes = []string{"USDT", "USDC", "BUSD", "USD"}
+
+// NormalizeSymbol maps venue-specific pair names such as "btc-usd",
+// "BTC/USDT" or "BTCUSDT" to the base asset symbol "BTC".
+func NormalizeSymbol(symbol string) string {
+	normalized := strings.ToUpper(strings.TrimSpace(symbol))
+	normalized = strings.NewReplacer("-", "", "/", "", "_", "").Replace(normalized)
+
+	for _, suffix := range quoteSuffixes {
+		if base := strings.TrimSuffix(normalized, suffix); base != normalized && base != "" {
+			return base
+		}
+	}
+
+	return normalized
+}
+
+type Venue struct {
+	Name string
+	Feed Feed
+}
+
+type AggregatorConfig struct {
+	Method ConsolidationMethod
+	// Primary names the preferred venue for ConsolidationPrimary.
+	Primary string
+	// Staleness is how old a venue's last tick may be and still count
+	// towards the consolidated price.
+	Staleness time.Duration
+	// BufferSize is the tick channel capacity; zero uses the default.
+	BufferSize int
+}
+
+// AggregatorFeed merges several venue feeds. It forwards every venue tick
+// tagged with the venue name and follows each one with a consolidated tick
+// for the symbol tagged models.SourceConsolidated.
+type AggregatorFeed struct {
+	venues    []Venue
+	method    ConsolidationMethod
+	primary   string
+	staleness time.Duration
+	latest    map[string]map[string]*models.Tick
+	prices    *PriceCache
+	tickChan  chan *models.Tick
+	stopChan  chan struct{}
+	running   bool
+	mu        sync.RWMutex
+	wg        sync.WaitGroup
+}
+
+func NewAggregatorFeed(venues []Venue, cfg AggregatorConfig) *AggregatorFeed {
+	staleness := cfg.Staleness
+	if staleness <= 0 {
+		staleness = defaultStaleness
+	}
+
+	bufferSize := cfg.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultTickBuffer
+	}
+
+	return &AggregatorFeed{
+		venues:    venues,
+		method:    cfg.Method,
+		primary:   cfg.Primary,
+		staleness: staleness,
+		latest:    make(map[string]map[string]*models.Tick),
+		prices:    NewPriceCache(),
+		tickChan:  make(chan *models.Tick, bufferSize),
+		stopChan:  make(chan struct{}),
+	}
+}
+
+func init() {
+	Register("aggregate", func(cfg Config) (Feed, error) {
+		if len(cfg.Venues) == 0 {
+			return nil, fmt.Errorf("aggregate feed requires at least one venue")
+		}
+
+		method, err := ParseConsolidationMethod(cfg.Consolidation)
+		if err != nil {
+			return nil, err
+		}
+		if method == ConsolidationPrimary && !slices.Contains(cfg.Venues, cfg.Primary) {
+			return nil, fmt.Errorf("primary venue %q is not one of the venues %v", cfg.Primary, cfg.Venues)
+		}
+
+		venues := make([]Venue, 0, len(cfg.Venues))
+		for i, name := range cfg.Venues {
+			if name == "aggregate" {
+				return nil, fmt.Errorf("aggregate feed cannot be its own venue")
+			}
+			if slices.Contains(cfg.Venues[:i], name) {
+				return nil, fmt.Errorf("venue %q is listed more than once", name)
+			}
+
+			venueCfg := cfg
+			venueCfg.Venues = nil
+			feed, err := New(name, venueCfg)
+			if err != nil {
+				return nil, err
+			}
+			venues = append(venues, Venue{Name: name, Feed: feed})
+		}
+
+		return NewAggregatorFeed(venues, AggregatorConfig{
+			Method:     method,
+			Primary:    cfg.Primary,
+			Staleness:  cfg.Staleness,
+			BufferSize: cfg.tickBuffer(),
+		}), nil
+	})
+}
+
+func (a *AggregatorFeed) Start(ctx context.Context) error {
+	a.mu.Lock()
+	if a.running {
+		a.mu.Unlock()
+		return nil
+	}
+	a.running = true
+	a.mu.Unlock()
+
+	for i, venue := range a.venues {
+		if err := venue.Feed.Start(ctx); err != nil {
+			for _, started := range a.venues[:i] {
+				started.Feed.Stop()
+			}
+			a.wg.Wait()
+
+			a.mu.Lock()
+			a.running = false
+			a.mu.Unlock()
+
+			return fmt.Errorf("failed to start venue %s: %v", venue.Name, err)
+		}
+
+		a.wg.Add(1)
+		go a.consume(venue)
+	}
+
+	log.Printf("Aggregating %d venue(s) using %s consolidation", len(a.venues), a.method)
+
+	return nil
+}
+
+func (a *AggregatorFeed) Stop() {
+	a.mu.Lock()
+	if !a.running {
+		a.mu.Unlock()
+		return
+	}
+	a.running = false
+	close(a.stopChan)
+	a.mu.Unlock()
+
+	for _, venue := range a.venues {
+		venue.Feed.Stop()
+	}
+
+	a.wg.Wait()
+	close(a.tickChan)
+}
+
+func (a *AggregatorFeed) TickChannel() <-chan *models.Tick {
+	return a.tickChan
+}
+
+func (a *AggregatorFeed) GetCurrentPrice(symbol string) (float64, bool) {
+	return a.prices.Get(symbol)
+}
+
+// Status is connected while any venue is connected.
+func (a *AggregatorFeed) Status() FeedStatus {
+	status := FeedStatusDown
+	for _, venue := range a.venues {
+		switch venue.Feed.Status() {
+		case FeedStatusConnected:
+			return FeedStatusConnected
+		case FeedStatusReconnecting:
+			status = FeedStatusReconnecting
+		}
+	}
+	return status
+}
+
+func (a *AggregatorFeed) consume(venue Venue) {
+	defer a.wg.Done()
+
+	for tick := range venue.Feed.TickChannel() {
+		venueTick := *tick
+		venueTick.Symbol = NormalizeSymbol(tick.Symbol)
+		venueTick.Source = venue.Name
+
+		consolidated := a.update(&venueTick)
+
+		a.emit(&venueTick)
+		if consolidated != nil {
+			a.emit(consolidated)
+		}
+	}
+}
+
+func (a *AggregatorFeed) emit(tick *models.Tick) {
+	select {
+	case a.tickChan <- tick:
+	case <-a.stopChan:
+	default:
+		metrics.TicksDropped.WithLabelValues(metrics.StageFeed).Inc()
+	}
+}
+
+func (a *AggregatorFeed) update(tick *models.Tick) *models.Tick {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	quotes, exists := a.latest[tick.Symbol]
+	if !exists {
+		quotes = make(map[string]*models.Tick)
+		a.latest[tick.Symbol] = quotes
+	}
+	quotes[tick.Source] = tick
+
+	price, volume, ok := a.consolidate(quotes, tick.Timestamp)
+	if !ok {
+		return nil
+	}
+
+	consolidated := &models.Tick{
+		Symbol:    tick.Symbol,
+		Price:     price,
+		Volume:    volume,
+		Source:    models.SourceConsolidated,
+		Timestamp: tick.Timestamp,
+	}
+	a.prices.Observe(consolidated)
+
+	return consolidated
+}
+
+func (a *AggregatorFeed) consolidate(quotes map[string]*models.Tick, now time.Time) (float64, float64, bool) {
+	cutoff := now.Add(-a.staleness)
+
+	fresh := make([]*models.Tick, 0, len(quotes))
+	for _, venue := range a.venues {
+		if quote, exists := quotes[venue.Name]; exists && !quote.Timestamp.Before(cutoff) {
+			fresh = append(fresh, quote)
+		}
+	}
+
+	if len(fresh) == 0 {
+		return 0, 0, false
+	}
+
+	var totalVolume float64
+	for _, quote := range fresh {
+		totalVolume += quote.Volume
+	}
+
+	switch a.method {
+	case ConsolidationPrimary:
+		for _, quote := range fresh {
+			if quote.Source == a.primary {
+				return quote.Price, quote.Volume, true
+			}
+		}
+		// Venues are checked in configuration order, so the fallback is the
+		// first fresh venue listed, wherever the primary is in the list.
+		return fresh[0].Price, fresh[0].Volume, true
+
+	case ConsolidationVolumeWeighted:
+		if totalVolume > 0 {
+			var weighted float64
+			for _, quote := range fresh {
+				weighted += quote.Price * quote.Volume
+			}
+			return weighted / totalVolume, totalVolume, true
+		}
+		return medianPrice(fresh), totalVolume, true
+
+	default:
+		return medianPrice(fresh), totalVolume, true
+	}
+}
+
+func medianPrice(quotes []*models.Tick) float64 {
+	prices := make([]float64, len(quotes))
+	for i, quote := range quotes {
+		prices[i] = quote.Price
+	}
+	sort.Float64s(prices)
+
+	mid := len(prices) / 2
+	if len(prices)%2 == 0 {
+		return (prices[mid-1] + prices[mid]) / 2
+	}
+	return prices[mid]
+}
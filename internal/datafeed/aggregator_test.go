@@ -0,0 +1,190 @@
+package datafeed
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"crypto-price-alerts/pkg/models"
+)
+
+type stubFeed struct {
+	tickChan chan *models.Tick
+	startErr error
+}
+
+func newStubFeed() *stubFeed {
+	return &stubFeed{tickChan: make(chan *models.Tick, 10)}
+}
+
+func (f *stubFeed) Start(ctx context.Context) error        { return f.startErr }
+func (f *stubFeed) Stop()                                  { close(f.tickChan) }
+func (f *stubFeed) TickChannel() <-chan *models.Tick       { return f.tickChan }
+func (f *stubFeed) GetCurrentPrice(string) (float64, bool) { return 0, false }
+func (f *stubFeed) Status() FeedStatus                     { return FeedStatusConnected }
+
+func TestNormalizeSymbol(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"BTC", "BTC"},
+		{"btcusdt", "BTC"},
+		{"BTC-USD", "BTC"},
+		{"ETH/USDC", "ETH"},
+		{"sol_usdt", "SOL"},
+		{"USDT", "USDT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if result := NormalizeSymbol(tt.input); result != tt.expected {
+				t.Errorf("NormalizeSymbol(%q) = %s, expected %s", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAggregatorFeed_Consolidate(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	quotes := map[string]*models.Tick{
+		"binance":  {Price: 100, Volume: 3, Source: "binance", Timestamp: now},
+		"coinbase": {Price: 104, Volume: 1, Source: "coinbase", Timestamp: now},
+		"kraken":   {Price: 101, Volume: 0, Source: "kraken", Timestamp: now.Add(-time.Minute)},
+	}
+	venues := []Venue{{Name: "kraken"}, {Name: "binance"}, {Name: "coinbase"}}
+
+	tests := []struct {
+		name     string
+		cfg      AggregatorConfig
+		expected float64
+	}{
+		{"median of fresh venues", AggregatorConfig{Method: ConsolidationMedian, Staleness: 10 * time.Second}, 102},
+		{"median including stale window", AggregatorConfig{Method: ConsolidationMedian, Staleness: 2 * time.Minute}, 101},
+		{"volume weighted", AggregatorConfig{Method: ConsolidationVolumeWeighted, Staleness: 10 * time.Second}, 101},
+		{"primary", AggregatorConfig{Method: ConsolidationPrimary, Primary: "coinbase", Staleness: 10 * time.Second}, 104},
+		{"primary stale falls back", AggregatorConfig{Method: ConsolidationPrimary, Primary: "kraken", Staleness: 10 * time.Second}, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aggregator := NewAggregatorFeed(venues, tt.cfg)
+			price, _, ok := aggregator.consolidate(quotes, now)
+			if !ok {
+				t.Fatal("Expected a consolidated price")
+			}
+			if math.Abs(price-tt.expected) > 1e-9 {
+				t.Errorf("consolidate() = %.4f, expected %.4f", price, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAggregatorFeed_StopsStartedVenuesOnError(t *testing.T) {
+	binance := newStubFeed()
+	broken := newStubFeed()
+	broken.startErr = errors.New("connection refused")
+
+	aggregator := NewAggregatorFeed([]Venue{
+		{Name: "binance", Feed: binance},
+		{Name: "broken", Feed: broken},
+	}, AggregatorConfig{Method: ConsolidationMedian})
+
+	if err := aggregator.Start(context.Background()); err == nil {
+		t.Fatal("Expected Start() to fail")
+	}
+
+	if _, open := <-binance.tickChan; open {
+		t.Error("Expected the started venue to be stopped")
+	}
+	if aggregator.running {
+		t.Error("Expected the aggregator not to be running")
+	}
+}
+
+func TestAggregatorFeed_PrimaryFallsBackInVenueOrder(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	quotes := map[string]*models.Tick{
+		"coinbase": {Price: 104, Source: "coinbase", Timestamp: now},
+		"kraken":   {Price: 101, Source: "kraken", Timestamp: now.Add(-time.Minute)},
+		"binance":  {Price: 100, Source: "binance", Timestamp: now},
+	}
+	venues := []Venue{{Name: "coinbase"}, {Name: "kraken"}, {Name: "binance"}}
+
+	aggregator := NewAggregatorFeed(venues, AggregatorConfig{Method: ConsolidationPrimary, Primary: "kraken", Staleness: 10 * time.Second})
+	if price, _, _ := aggregator.consolidate(quotes, now); price != 104 {
+		t.Errorf("Expected the stale primary to fall back to the first venue listed, got %.2f", price)
+	}
+
+	quotes["coinbase"].Timestamp = now.Add(-time.Minute)
+	if price, _, _ := aggregator.consolidate(quotes, now); price != 100 {
+		t.Errorf("Expected the fallback to skip stale venues, got %.2f", price)
+	}
+}
+
+func TestAggregatorFeed_RequiresPrimaryVenue(t *testing.T) {
+	_, err := New("aggregate", Config{Symbols: []string{"BTC"}, Venues: []string{"mock"}, Consolidation: "primary", Primary: "binance"})
+	if err == nil {
+		t.Error("Expected an error for a primary venue that is not aggregated")
+	}
+}
+
+func TestAggregatorFeed_RejectsRepeatedVenue(t *testing.T) {
+	_, err := New("aggregate", Config{Symbols: []string{"BTC"}, TickRate: time.Second, Venues: []string{"mock", "mock"}})
+	if err == nil || !strings.Contains(err.Error(), "more than once") {
+		t.Errorf("Expected an error for a repeated venue, got %v", err)
+	}
+}
+
+func TestAggregatorFeed_EmitsVenueAndConsolidatedTicks(t *testing.T) {
+	binance := newStubFeed()
+	coinbase := newStubFeed()
+
+	aggregator := NewAggregatorFeed([]Venue{
+		{Name: "binance", Feed: binance},
+		{Name: "coinbase", Feed: coinbase},
+	}, AggregatorConfig{Method: ConsolidationMedian})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	aggregator.Start(ctx)
+
+	binance.tickChan <- &models.Tick{Symbol: "BTCUSDT", Price: 100, Timestamp: time.Now()}
+	coinbase.tickChan <- &models.Tick{Symbol: "BTC-USD", Price: 110, Timestamp: time.Now()}
+
+	sources := make(map[string]int)
+	var lastConsolidated float64
+	timeout := time.After(time.Second)
+	for received := 0; received < 4; received++ {
+		select {
+		case tick := <-aggregator.TickChannel():
+			if tick.Symbol != "BTC" {
+				t.Errorf("Expected normalized symbol BTC, got %s", tick.Symbol)
+			}
+			sources[tick.Source]++
+			if tick.Source == models.SourceConsolidated {
+				lastConsolidated = tick.Price
+			}
+		case <-timeout:
+			t.Fatalf("Timed out after %d ticks", received)
+		}
+	}
+
+	if sources["binance"] != 1 || sources["coinbase"] != 1 || sources[models.SourceConsolidated] != 2 {
+		t.Errorf("Unexpected tick sources: %v", sources)
+	}
+
+	if lastConsolidated != 105 {
+		t.Errorf("Expected final consolidated price 105, got %.2f", lastConsolidated)
+	}
+
+	if price, ok := aggregator.GetCurrentPrice("BTC"); !ok || price != 105 {
+		t.Errorf("GetCurrentPrice() = %.2f, %v", price, ok)
+	}
+
+	aggregator.Stop()
+}
@@ -5,35 +5,97 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math/rand"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"crypto-price-alerts/internal/metrics"
+	"crypto-price-alerts/pkg/clock"
 	"crypto-price-alerts/pkg/models"
 
 	"github.com/gorilla/websocket"
 )
 
+const (
+	binanceEndpoint = "wss://stream.binance.com:9443/ws"
+
+	// Binance drops every connection after 24 hours, so reconnect a little
+	// before that on our own terms.
+	binanceMaxConnLifetime = 23*time.Hour + 30*time.Minute
+	binancePingInterval    = 30 * time.Second
+	binanceReadTimeout     = 90 * time.Second
+	binanceWriteTimeout    = 10 * time.Second
+	binanceMinBackoff      = 1 * time.Second
+	binanceMaxBackoff      = 60 * time.Second
+	// A connection must stay up this long before the backoff starts over, so
+	// a server that accepts and immediately drops us is not hammered.
+	binanceStableAfter = 30 * time.Second
+)
+
 type BinanceDataFeed struct {
 	symbols  []string
+	endpoint string
 	tickChan chan *models.Tick
 	stopChan chan struct{}
 	running  bool
+	status   FeedStatus
 	mu       sync.RWMutex
 	conn     *websocket.Conn
+	wg       sync.WaitGroup
+	prices   *PriceCache
+	clock    clock.Clock
+
+	maxConnLifetime time.Duration
+	pingInterval    time.Duration
+	readTimeout     time.Duration
+	minBackoff      time.Duration
+	maxBackoff      time.Duration
+	stableAfter     time.Duration
 }
 
 type BinanceTickerMessage struct {
 	Symbol        string          `json:"s"`
 	PriceRaw      json.RawMessage `json:"c"`
+	VolumeRaw     json.RawMessage `json:"v"`
 	CloseTime     int64           `json:"C"`
 }
 
+func init() {
+	Register("binance", func(cfg Config) (Feed, error) {
+		endpoint := cfg.Endpoint
+		if endpoint == "" {
+			endpoint = binanceEndpoint
+		}
+		return newBinanceDataFeed(cfg.Symbols, endpoint, cfg.tickBuffer(), clock.OrReal(cfg.Clock)), nil
+	})
+}
+
 func NewBinanceDataFeed(symbols []string) *BinanceDataFeed {
+	return NewBinanceDataFeedWithEndpoint(symbols, binanceEndpoint)
+}
+
+func NewBinanceDataFeedWithEndpoint(symbols []string, endpoint string) *BinanceDataFeed {
+	return newBinanceDataFeed(symbols, endpoint, defaultTickBuffer, clock.Real)
+}
+
+func newBinanceDataFeed(symbols []string, endpoint string, bufferSize int, clk clock.Clock) *BinanceDataFeed {
 	return &BinanceDataFeed{
-		symbols:  symbols,
-		tickChan: make(chan *models.Tick, 1000),
-		stopChan: make(chan struct{}),
+		symbols:         symbols,
+		endpoint:        strings.TrimSuffix(endpoint, "/"),
+		tickChan:        make(chan *models.Tick, bufferSize),
+		stopChan:        make(chan struct{}),
+		status:          FeedStatusDown,
+		prices:          NewPriceCache(),
+		clock:           clk,
+		maxConnLifetime: binanceMaxConnLifetime,
+		pingInterval:    binancePingInterval,
+		readTimeout:     binanceReadTimeout,
+		minBackoff:      binanceMinBackoff,
+		maxBackoff:      binanceMaxBackoff,
+		stableAfter:     binanceStableAfter,
 	}
 }
 
@@ -46,32 +108,16 @@ func (b *BinanceDataFeed) Start(ctx context.Context) error {
 	b.running = true
 	b.mu.Unlock()
 
-	streams := make([]string, len(b.symbols))
-	for i, symbol := range b.symbols {
-		streams[i] = strings.ToLower(symbol) + "usdt@ticker"
-	}
-
-	streamParam := strings.Join(streams, "/")
-	wsURL := fmt.Sprintf("wss://stream.binance.com:9443/ws/%s", streamParam)
-
-	log.Printf("Connecting to Binance WebSocket: %s", wsURL)
-
-	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
-	if err != nil {
-		return fmt.Errorf("failed to connect to Binance WebSocket: %v", err)
-	}
-
-	b.conn = conn
-	go b.readMessages(ctx)
+	b.wg.Add(1)
+	go b.run(ctx)
 
 	return nil
 }
 
 func (b *BinanceDataFeed) Stop() {
 	b.mu.Lock()
-	defer b.mu.Unlock()
-
 	if !b.running {
+		b.mu.Unlock()
 		return
 	}
 
@@ -81,7 +127,11 @@ func (b *BinanceDataFeed) Stop() {
 	if b.conn != nil {
 		b.conn.Close()
 	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
 
+	b.setStatus(FeedStatusDown)
 	close(b.tickChan)
 }
 
@@ -89,25 +139,227 @@ func (b *BinanceDataFeed) TickChannel() <-chan *models.Tick {
 	return b.tickChan
 }
 
-func (b *BinanceDataFeed) readMessages(ctx context.Context) {
-	defer b.conn.Close()
+func (b *BinanceDataFeed) Status() FeedStatus {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.status
+}
 
-	for {
+func (b *BinanceDataFeed) setStatus(status FeedStatus) {
+	b.mu.Lock()
+	previous := b.status
+	b.status = status
+	b.mu.Unlock()
+
+	if previous != status {
+		log.Printf("Binance feed status: %s -> %s", previous, status)
+	}
+}
+
+func (b *BinanceDataFeed) streamURL() string {
+	streams := make([]string, len(b.symbols))
+	for i, symbol := range b.symbols {
+		streams[i] = strings.ToLower(symbol) + "usdt@ticker"
+	}
+
+	return fmt.Sprintf("%s/%s", b.endpoint, strings.Join(streams, "/"))
+}
+
+func (b *BinanceDataFeed) run(ctx context.Context) {
+	defer b.wg.Done()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
 		select {
+		case <-b.stopChan:
+			cancel()
 		case <-ctx.Done():
+		}
+	}()
+
+	wsURL := b.streamURL()
+	attempt := 0
+
+	for {
+		if b.stopping(ctx) {
 			return
-		case <-b.stopChan:
+		}
+
+		log.Printf("Connecting to Binance WebSocket: %s", wsURL)
+
+		conn, _, err := websocket.DefaultDialer.DialContext(ctx, wsURL, nil)
+		if err != nil {
+			b.setStatus(FeedStatusReconnecting)
+			delay := b.backoff(attempt)
+			attempt++
+			log.Printf("Failed to connect to Binance WebSocket: %v (retrying in %v)", err, delay)
+
+			if !b.wait(ctx, delay) {
+				return
+			}
+			continue
+		}
+
+		if !b.attach(conn) {
+			conn.Close()
 			return
-		default:
-			var msg BinanceTickerMessage
-			err := b.conn.ReadJSON(&msg)
-			if err != nil {
-				log.Printf("Error reading WebSocket message: %v", err)
+		}
+
+		b.setStatus(FeedStatusConnected)
+		connectedAt := time.Now()
+
+		err = b.readMessages(ctx, conn)
+
+		b.detach(conn)
+
+		if b.stopping(ctx) {
+			return
+		}
+
+		b.setStatus(FeedStatusReconnecting)
+		if err == nil {
+			// Planned reconnect at the end of the connection's lifetime.
+			attempt = 0
+			continue
+		}
+
+		if time.Since(connectedAt) >= b.stableAfter {
+			attempt = 0
+		}
+		delay := b.backoff(attempt)
+		attempt++
+		log.Printf("Binance WebSocket connection lost: %v (reconnecting in %v)", err, delay)
+
+		if !b.wait(ctx, delay) {
+			return
+		}
+	}
+}
+
+// attach makes conn the active connection unless the feed is being stopped.
+func (b *BinanceDataFeed) attach(conn *websocket.Conn) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.running {
+		return false
+	}
+
+	b.conn = conn
+	return true
+}
+
+func (b *BinanceDataFeed) detach(conn *websocket.Conn) {
+	b.mu.Lock()
+	if b.conn == conn {
+		b.conn = nil
+	}
+	b.mu.Unlock()
+
+	conn.Close()
+}
+
+func (b *BinanceDataFeed) stopping(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	case <-b.stopChan:
+		return true
+	default:
+		return false
+	}
+}
+
+func (b *BinanceDataFeed) wait(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-b.stopChan:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// backoff returns an exponential delay for the given attempt with up to 50%
+// jitter so that many clients don't reconnect in lockstep.
+func (b *BinanceDataFeed) backoff(attempt int) time.Duration {
+	delay := b.maxBackoff
+	if attempt < 30 {
+		if d := b.minBackoff << uint(attempt); d > 0 && d < b.maxBackoff {
+			delay = d
+		}
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// readMessages serves conn until it fails, the feed stops, or the connection
+// reaches its maximum lifetime.
+func (b *BinanceDataFeed) readMessages(ctx context.Context, conn *websocket.Conn) error {
+	done := make(chan struct{})
+	defer close(done)
+
+	var expired atomic.Bool
+
+	conn.SetReadDeadline(time.Now().Add(b.readTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(b.readTimeout))
+	})
+	conn.SetPingHandler(func(data string) error {
+		conn.SetReadDeadline(time.Now().Add(b.readTimeout))
+		err := conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(binanceWriteTimeout))
+		if err == websocket.ErrCloseSent {
+			return nil
+		}
+		return err
+	})
+
+	go func() {
+		pingTicker := time.NewTicker(b.pingInterval)
+		defer pingTicker.Stop()
+
+		lifetime := time.NewTimer(b.maxConnLifetime)
+		defer lifetime.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				conn.Close()
+				return
+			case <-pingTicker.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(binanceWriteTimeout)); err != nil {
+					conn.Close()
+					return
+				}
+			case <-lifetime.C:
+				log.Printf("Binance connection reached its %v lifetime, reconnecting", b.maxConnLifetime)
+				expired.Store(true)
+				conn.Close()
 				return
 			}
+		}
+	}()
 
-			b.processTicker(msg)
+	for {
+		var msg BinanceTickerMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			if expired.Load() {
+				return nil
+			}
+			return err
 		}
+
+		conn.SetReadDeadline(time.Now().Add(b.readTimeout))
+		b.processTicker(msg)
 	}
 }
 
@@ -132,14 +384,37 @@ func (b *BinanceDataFeed) processTicker(msg BinanceTickerMessage) {
 	
 	log.Printf("LIVE: %s = $%.2f", symbol, price)
 
-	tick := models.NewTick(symbol, price)
+	tick := models.NewTick(symbol, price, b.clock.Now())
+	tick.Source = "binance"
+	tick.Volume = parseBinanceNumber(msg.VolumeRaw)
+	b.prices.Observe(tick)
 
 	select {
 	case b.tickChan <- tick:
 	default:
+		metrics.TicksDropped.WithLabelValues(metrics.StageFeed).Inc()
 	}
 }
 
+func parseBinanceNumber(raw json.RawMessage) float64 {
+	if len(raw) == 0 {
+		return 0
+	}
+
+	var value float64
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return value
+	}
+
+	var valueStr string
+	if err := json.Unmarshal(raw, &valueStr); err != nil {
+		return 0
+	}
+
+	value, _ = strconv.ParseFloat(valueStr, 64)
+	return value
+}
+
 func (b *BinanceDataFeed) GetCurrentPrice(symbol string) (float64, bool) {
-	return 0, false
+	return b.prices.Get(symbol)
 }
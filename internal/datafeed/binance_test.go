@@ -0,0 +1,225 @@
+package datafeed
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// fakeBinance is a local stand-in for the Binance stream endpoint. Every
+// connection sends messagesPerConn ticker updates and is then dropped.
+type fakeBinance struct {
+	server          *httptest.Server
+	connections     atomic.Int32
+	messagesPerConn int
+	holdOpen        bool
+
+	mu          sync.Mutex
+	connectedAt []time.Time
+}
+
+func newFakeBinance(t *testing.T, messagesPerConn int, holdOpen bool) *fakeBinance {
+	fb := &fakeBinance{messagesPerConn: messagesPerConn, holdOpen: holdOpen}
+	upgrader := websocket.Upgrader{}
+
+	fb.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		n := fb.connections.Add(1)
+		fb.mu.Lock()
+		fb.connectedAt = append(fb.connectedAt, time.Now())
+		fb.mu.Unlock()
+
+		for i := 0; i < fb.messagesPerConn; i++ {
+			msg := fmt.Sprintf(`{"s":"BTCUSDT","c":"%d.50","C":%d}`, 100000+int(n)*10+i, time.Now().UnixMilli())
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+				return
+			}
+		}
+
+		if fb.holdOpen {
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}
+	}))
+	t.Cleanup(fb.server.Close)
+
+	return fb
+}
+
+func (fb *fakeBinance) endpoint() string {
+	return "ws" + strings.TrimPrefix(fb.server.URL, "http") + "/ws"
+}
+
+func newTestBinanceFeed(endpoint string) *BinanceDataFeed {
+	feed := NewBinanceDataFeedWithEndpoint([]string{"BTC"}, endpoint)
+	feed.minBackoff = 5 * time.Millisecond
+	feed.maxBackoff = 20 * time.Millisecond
+	return feed
+}
+
+func waitFor(t *testing.T, timeout time.Duration, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("Timed out waiting for condition")
+}
+
+func TestBinanceDataFeed_ReconnectsAfterDrop(t *testing.T) {
+	fb := newFakeBinance(t, 3, false)
+	feed := newTestBinanceFeed(fb.endpoint())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := feed.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	received := 0
+	lastPrice := 0.0
+	timeout := time.After(5 * time.Second)
+	for received < 9 {
+		select {
+		case tick := <-feed.TickChannel():
+			if tick.Symbol != "BTC" {
+				t.Errorf("Expected symbol BTC, got %s", tick.Symbol)
+			}
+			received++
+			lastPrice = tick.Price
+		case <-timeout:
+			t.Fatalf("Timed out after %d ticks", received)
+		}
+	}
+
+	feed.Stop()
+
+	for tick := range feed.TickChannel() {
+		lastPrice = tick.Price
+	}
+	if price, ok := feed.GetCurrentPrice("btcusdt"); !ok || price != lastPrice {
+		t.Errorf("GetCurrentPrice() = %.2f, %v, expected the last tick's %.2f", price, ok, lastPrice)
+	}
+
+	if fb.connections.Load() < 3 {
+		t.Errorf("Expected at least 3 connections after drops, got %d", fb.connections.Load())
+	}
+	if feed.Status() != FeedStatusDown {
+		t.Errorf("Expected status down after Stop, got %s", feed.Status())
+	}
+}
+
+func TestBinanceDataFeed_RetriesUntilServerIsUp(t *testing.T) {
+	listener := httptest.NewUnstartedServer(nil)
+	endpoint := "ws://" + listener.Listener.Addr().String() + "/ws"
+	listener.Listener.Close()
+
+	feed := newTestBinanceFeed(endpoint)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	feed.Start(ctx)
+	defer feed.Stop()
+
+	waitFor(t, 2*time.Second, func() bool {
+		return feed.Status() == FeedStatusReconnecting
+	})
+}
+
+func TestBinanceDataFeed_ForcedReconnectOnLifetime(t *testing.T) {
+	fb := newFakeBinance(t, 1, true)
+	feed := newTestBinanceFeed(fb.endpoint())
+	feed.maxConnLifetime = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	feed.Start(ctx)
+	defer feed.Stop()
+
+	waitFor(t, 2*time.Second, func() bool {
+		return fb.connections.Load() >= 3
+	})
+}
+
+func TestBinanceDataFeed_ReadTimeoutTriggersReconnect(t *testing.T) {
+	fb := newFakeBinance(t, 1, true)
+	feed := newTestBinanceFeed(fb.endpoint())
+	feed.readTimeout = 50 * time.Millisecond
+	feed.pingInterval = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	feed.Start(ctx)
+	defer feed.Stop()
+
+	waitFor(t, 2*time.Second, func() bool {
+		return fb.connections.Load() >= 2
+	})
+}
+
+func TestBinanceDataFeed_BacksOffWhenDroppedImmediately(t *testing.T) {
+	fb := newFakeBinance(t, 0, false)
+	feed := newTestBinanceFeed(fb.endpoint())
+	feed.minBackoff = 20 * time.Millisecond
+	feed.maxBackoff = time.Second
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	feed.Start(ctx)
+	defer feed.Stop()
+
+	waitFor(t, 3*time.Second, func() bool {
+		return fb.connections.Load() >= 5
+	})
+
+	fb.mu.Lock()
+	defer fb.mu.Unlock()
+
+	// Each delay is at least half the exponential step, whatever the jitter.
+	for i := 1; i < 5; i++ {
+		gap := fb.connectedAt[i].Sub(fb.connectedAt[i-1])
+		if minimum := (feed.minBackoff << uint(i-1)) / 2; gap < minimum {
+			t.Errorf("Reconnect %d came after %v, expected at least %v", i, gap, minimum)
+		}
+	}
+}
+
+func TestBinanceDataFeed_Backoff(t *testing.T) {
+	feed := NewBinanceDataFeed([]string{"BTC"})
+
+	for attempt := 0; attempt < 40; attempt++ {
+		delay := feed.backoff(attempt)
+		if delay < feed.minBackoff/2 || delay > feed.maxBackoff {
+			t.Errorf("backoff(%d) = %v, outside [%v, %v]", attempt, delay, feed.minBackoff/2, feed.maxBackoff)
+		}
+	}
+
+	if delay := feed.backoff(20); delay < feed.maxBackoff/2 {
+		t.Errorf("Expected backoff to reach the cap, got %v", delay)
+	}
+}
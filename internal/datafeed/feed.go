@@ -0,0 +1,97 @@
+package datafeed
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"crypto-price-alerts/pkg/clock"
+	"crypto-price-alerts/pkg/models"
+)
+
+type Feed interface {
+	Start(ctx context.Context) error
+	Stop()
+	TickChannel() <-chan *models.Tick
+	GetCurrentPrice(symbol string) (float64, bool)
+	Status() FeedStatus
+}
+
+// Config carries the settings a feed constructor may need. Feeds ignore the
+// fields that don't apply to them.
+type Config struct {
+	Symbols  []string
+	TickRate time.Duration
+	Endpoint string
+	// BufferSize is the tick channel capacity; zero uses defaultTickBuffer.
+	BufferSize int
+	// Clock stamps the ticks feeds generate or receive; nil uses the system
+	// clock.
+	Clock clock.Clock
+
+	// Aggregate feed settings.
+	Venues        []string
+	Consolidation string
+	Primary       string
+	Staleness     time.Duration
+
+	// Replay feed settings. ReplayFiles may name recording directories;
+	// a zero ReplayFrom or ReplayTo leaves that end of the range open.
+	ReplayFiles []string
+	ReplaySpeed float64
+	ReplayFrom  time.Time
+	ReplayTo    time.Time
+}
+
+const defaultTickBuffer = 1000
+
+func (cfg Config) tickBuffer() int {
+	if cfg.BufferSize > 0 {
+		return cfg.BufferSize
+	}
+	return defaultTickBuffer
+}
+
+type Factory func(cfg Config) (Feed, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("datafeed: feed %q registered twice", name))
+	}
+	registry[name] = factory
+}
+
+func New(name string, cfg Config) (Feed, error) {
+	registryMu.RLock()
+	factory, exists := registry[name]
+	registryMu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("unknown data feed %q (available: %v)", name, Available())
+	}
+
+	return factory(cfg)
+}
+
+func Available() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,52 @@
+package datafeed
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNew_BuiltinFeeds(t *testing.T) {
+	for _, name := range []string{"mock", "binance"} {
+		feed, err := New(name, Config{Symbols: []string{"BTC"}, TickRate: time.Millisecond})
+		if err != nil {
+			t.Errorf("New(%q) error = %v", name, err)
+			continue
+		}
+		if feed.Status() != FeedStatusDown {
+			t.Errorf("Expected new %s feed to be down, got %s", name, feed.Status())
+		}
+	}
+}
+
+func TestNew_UnknownFeed(t *testing.T) {
+	if _, err := New("does-not-exist", Config{}); err == nil {
+		t.Error("Expected error for unknown feed")
+	}
+}
+
+func TestRegister_CustomFeed(t *testing.T) {
+	Register("test-exchange", func(cfg Config) (Feed, error) {
+		return NewMockDataFeed(cfg.Symbols, cfg.TickRate), nil
+	})
+
+	feed, err := New("test-exchange", Config{Symbols: []string{"ETH"}, TickRate: time.Millisecond})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	feed.Start(ctx)
+	defer feed.Stop()
+
+	select {
+	case tick := <-feed.TickChannel():
+		if tick.Symbol != "ETH" {
+			t.Errorf("Expected ETH tick, got %s", tick.Symbol)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for tick")
+	}
+}
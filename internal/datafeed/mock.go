@@ -2,10 +2,13 @@ package datafeed
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
 	"sync"
 	"time"
 
+	"crypto-price-alerts/internal/metrics"
+	"crypto-price-alerts/pkg/clock"
 	"crypto-price-alerts/pkg/models"
 )
 
@@ -17,9 +20,23 @@ type MockDataFeed struct {
 	stopChan   chan struct{}
 	running    bool
 	tickRate   time.Duration
+	clock      clock.Clock
+}
+
+func init() {
+	Register("mock", func(cfg Config) (Feed, error) {
+		if cfg.TickRate <= 0 {
+			return nil, fmt.Errorf("mock feed requires a positive tick rate")
+		}
+		return newMockDataFeed(cfg.Symbols, cfg.TickRate, cfg.tickBuffer(), clock.OrReal(cfg.Clock)), nil
+	})
 }
 
 func NewMockDataFeed(symbols []string, tickRate time.Duration) *MockDataFeed {
+	return newMockDataFeed(symbols, tickRate, defaultTickBuffer, clock.Real)
+}
+
+func newMockDataFeed(symbols []string, tickRate time.Duration, bufferSize int, clk clock.Clock) *MockDataFeed {
 	initialPrices := map[string]float64{
 		"BTC":  110000.00,
 		"ETH":  4200.00,
@@ -43,9 +60,10 @@ func NewMockDataFeed(symbols []string, tickRate time.Duration) *MockDataFeed {
 	return &MockDataFeed{
 		symbols:  symbols,
 		prices:   prices,
-		tickChan: make(chan *models.Tick, 1000),
+		tickChan: make(chan *models.Tick, bufferSize),
 		stopChan: make(chan struct{}),
 		tickRate: tickRate,
+		clock:    clk,
 	}
 }
 
@@ -79,6 +97,16 @@ func (m *MockDataFeed) TickChannel() <-chan *models.Tick {
 	return m.tickChan
 }
 
+func (m *MockDataFeed) Status() FeedStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.running {
+		return FeedStatusConnected
+	}
+	return FeedStatusDown
+}
+
 func (m *MockDataFeed) GetCurrentPrice(symbol string) (float64, bool) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -130,11 +158,21 @@ func (m *MockDataFeed) generateRandomTick() {
 	m.prices[symbol] = newPrice
 	m.mu.Unlock()
 
-	tick := models.NewTick(symbol, newPrice)
-	
+	tick := models.NewTick(symbol, newPrice, m.clock.Now())
+	tick.Source = "mock"
+	tick.Volume = 1 + rand.Float64()*999
+
+	// Stop closes tickChan under the lock, so only send while still running.
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if !m.running {
+		return
+	}
+
 	select {
 	case m.tickChan <- tick:
 	default:
+		metrics.TicksDropped.WithLabelValues(metrics.StageFeed).Inc()
 	}
 }
 
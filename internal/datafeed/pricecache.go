@@ -0,0 +1,33 @@
+package datafeed
+
+import (
+	"sync"
+
+	"crypto-price-alerts/pkg/models"
+)
+
+// PriceCache remembers the last price of every symbol a feed has emitted so
+// that feeds can answer GetCurrentPrice without keeping prices of their own.
+// Symbols are stored and looked up in their NormalizeSymbol form.
+type PriceCache struct {
+	prices map[string]float64
+	mu     sync.RWMutex
+}
+
+func NewPriceCache() *PriceCache {
+	return &PriceCache{prices: make(map[string]float64)}
+}
+
+func (c *PriceCache) Observe(tick *models.Tick) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prices[NormalizeSymbol(tick.Symbol)] = tick.Price
+}
+
+func (c *PriceCache) Get(symbol string) (float64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	price, exists := c.prices[NormalizeSymbol(symbol)]
+	return price, exists
+}
@@ -0,0 +1,595 @@
+package datafeed
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"crypto-price-alerts/internal/metrics"
+	"crypto-price-alerts/pkg/clock"
+	"crypto-price-alerts/pkg/models"
+)
+
+const (
+	recordingIndexName = "index.jsonl"
+	segmentNameFormat  = "ticks-%06d.jsonl.gz"
+)
+
+type RecorderConfig struct {
+	// Dir holds the segment files and their index.
+	Dir string
+	// SegmentDuration is how long a segment is written to before the next
+	// one is started.
+	SegmentDuration time.Duration
+	// SegmentSize is how many uncompressed bytes a segment holds before the
+	// next one is started.
+	SegmentSize int64
+	// Retention is how long segments are kept after their last tick; zero
+	// keeps every segment.
+	Retention time.Duration
+	// Buffer is how many ticks may wait to be written before new ones are
+	// dropped.
+	Buffer int
+	// FlushInterval is how often written ticks are pushed to the file, which
+	// bounds what a crash loses.
+	FlushInterval time.Duration
+	// Clock stamps the receive time of every tick; nil uses the system
+	// clock.
+	Clock clock.Clock
+}
+
+func DefaultRecorderConfig() RecorderConfig {
+	return RecorderConfig{
+		SegmentDuration: time.Hour,
+		SegmentSize:     64 << 20,
+		Buffer:          10000,
+		FlushInterval:   time.Second,
+	}
+}
+
+// SegmentInfo is a recording index entry describing one finished segment.
+type SegmentInfo struct {
+	File string `json:"file"`
+	// First and Last are the earliest and latest tick timestamps in the
+	// segment.
+	First time.Time `json:"first"`
+	Last  time.Time `json:"last"`
+	Ticks int       `json:"ticks"`
+}
+
+func (s *SegmentInfo) add(tick *recordedTick) {
+	if s.Ticks == 0 || tick.Timestamp.Before(s.First) {
+		s.First = tick.Timestamp
+	}
+	if s.Ticks == 0 || tick.Timestamp.After(s.Last) {
+		s.Last = tick.Timestamp
+	}
+	s.Ticks++
+}
+
+// recordedTick is a tick as the recorder writes it: the replay feed's JSONL
+// format plus the time the recorder received the tick.
+type recordedTick struct {
+	Symbol    string    `json:"symbol"`
+	Price     float64   `json:"price"`
+	Volume    float64   `json:"volume,omitempty"`
+	Source    string    `json:"source,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Received  time.Time `json:"received"`
+}
+
+type segment struct {
+	info   SegmentInfo
+	opened time.Time
+	size   int64
+	file   *os.File
+	gz     *gzip.Writer
+	buf    *bufio.Writer
+}
+
+// Recorder writes every tick passed to Record to gzip-compressed JSON Lines
+// segments in a directory, starting a new segment when the current one
+// reaches SegmentSize or SegmentDuration. Finished segments are listed in
+// index.jsonl with their time range, so that OpenRecording, and the replay
+// feed given the directory, read only the segments a time range needs. The
+// segment being written is not in the index until it is finished; segments
+// left unfinished by a crash are repaired and indexed on the next start.
+type Recorder struct {
+	cfg      RecorderConfig
+	clock    clock.Clock
+	index    []SegmentInfo
+	next     int
+	current  *segment
+	queue    chan *recordedTick
+	stopChan chan struct{}
+	running  bool
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+}
+
+func NewRecorder(cfg RecorderConfig) (*Recorder, error) {
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("recorder requires a directory")
+	}
+
+	defaults := DefaultRecorderConfig()
+	if cfg.SegmentDuration <= 0 {
+		cfg.SegmentDuration = defaults.SegmentDuration
+	}
+	if cfg.SegmentSize <= 0 {
+		cfg.SegmentSize = defaults.SegmentSize
+	}
+	if cfg.Buffer <= 0 {
+		cfg.Buffer = defaults.Buffer
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaults.FlushInterval
+	}
+
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create recording directory: %v", err)
+	}
+
+	r := &Recorder{
+		cfg:      cfg,
+		clock:    clock.OrReal(cfg.Clock),
+		next:     1,
+		queue:    make(chan *recordedTick, cfg.Buffer),
+		stopChan: make(chan struct{}),
+	}
+
+	index, err := readRecordingIndex(cfg.Dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read recording index: %v", err)
+	}
+	r.index = index
+
+	if err := r.recover(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *Recorder) Start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.running {
+		return nil
+	}
+	r.running = true
+
+	r.wg.Add(1)
+	go r.run(ctx)
+
+	log.Printf("Recording ticks to %s (%d segment(s) so far)", r.cfg.Dir, len(r.index))
+	return nil
+}
+
+// Stop writes the ticks still queued and finishes the current segment.
+func (r *Recorder) Stop() {
+	r.mu.Lock()
+	if !r.running {
+		r.mu.Unlock()
+		return
+	}
+	r.running = false
+	close(r.stopChan)
+	r.mu.Unlock()
+
+	r.wg.Wait()
+}
+
+// Record queues a tick for writing, stamped with the time it was received.
+// When the queue is full the tick is dropped rather than holding up the
+// pipeline.
+func (r *Recorder) Record(tick *models.Tick) {
+	entry := &recordedTick{
+		Symbol:    tick.Symbol,
+		Price:     tick.Price,
+		Volume:    tick.Volume,
+		Source:    tick.Source,
+		Timestamp: tick.Timestamp,
+		Received:  r.clock.Now(),
+	}
+
+	select {
+	case r.queue <- entry:
+	default:
+		metrics.TicksDropped.WithLabelValues(metrics.StageRecorder).Inc()
+	}
+}
+
+func (r *Recorder) QueuedTicks() int {
+	return len(r.queue)
+}
+
+// Segments returns the index of finished segments, oldest first.
+func (r *Recorder) Segments() []SegmentInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.index)
+}
+
+func (r *Recorder) run(ctx context.Context) {
+	defer r.wg.Done()
+
+	flush := time.NewTicker(r.cfg.FlushInterval)
+	defer flush.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.finish()
+			return
+		case <-r.stopChan:
+			r.finish()
+			return
+		case entry := <-r.queue:
+			r.write(entry)
+		case <-flush.C:
+			r.flush()
+		}
+	}
+}
+
+// finish writes whatever is still queued and closes the current segment.
+func (r *Recorder) finish() {
+	for {
+		select {
+		case entry := <-r.queue:
+			r.write(entry)
+		default:
+			r.closeSegment()
+			return
+		}
+	}
+}
+
+func (r *Recorder) write(entry *recordedTick) {
+	if r.current != nil && (r.current.size >= r.cfg.SegmentSize || r.clock.Now().Sub(r.current.opened) >= r.cfg.SegmentDuration) {
+		r.closeSegment()
+	}
+	if r.current == nil {
+		if err := r.openSegment(); err != nil {
+			log.Printf("Error recording tick: %v", err)
+			return
+		}
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("Error encoding recorded tick: %v", err)
+		return
+	}
+
+	line = append(line, '\n')
+	if _, err := r.current.buf.Write(line); err != nil {
+		log.Printf("Error writing %s: %v", r.current.info.File, err)
+		return
+	}
+	r.current.size += int64(len(line))
+	r.current.info.add(entry)
+}
+
+func (r *Recorder) openSegment() error {
+	name := fmt.Sprintf(segmentNameFormat, r.next)
+	file, err := os.Create(filepath.Join(r.cfg.Dir, name))
+	if err != nil {
+		return fmt.Errorf("failed to create segment: %v", err)
+	}
+	r.next++
+
+	gz := gzip.NewWriter(file)
+	r.current = &segment{
+		info:   SegmentInfo{File: name},
+		opened: r.clock.Now(),
+		file:   file,
+		gz:     gz,
+		buf:    bufio.NewWriter(gz),
+	}
+	return nil
+}
+
+// flush pushes the current segment's buffered ticks to the file as a
+// complete gzip block, so a crash loses at most one flush interval.
+func (r *Recorder) flush() {
+	if r.current == nil {
+		return
+	}
+
+	if err := r.current.buf.Flush(); err != nil {
+		log.Printf("Error flushing %s: %v", r.current.info.File, err)
+		return
+	}
+	if err := r.current.gz.Flush(); err != nil {
+		log.Printf("Error flushing %s: %v", r.current.info.File, err)
+	}
+}
+
+func (r *Recorder) closeSegment() {
+	current := r.current
+	if current == nil {
+		return
+	}
+	r.current = nil
+
+	err := errors.Join(current.buf.Flush(), current.gz.Close(), current.file.Close())
+	if err != nil {
+		log.Printf("Error finishing %s: %v", current.info.File, err)
+	}
+
+	r.addSegment(current.info)
+}
+
+// addSegment indexes a finished segment and drops the segments that have
+// outlived the retention.
+func (r *Recorder) addSegment(info SegmentInfo) {
+	if info.Ticks == 0 {
+		os.Remove(filepath.Join(r.cfg.Dir, info.File))
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.index = append(r.index, info)
+
+	if r.cfg.Retention > 0 {
+		cutoff := r.clock.Now().Add(-r.cfg.Retention)
+		kept := r.index[:0]
+		for _, segment := range r.index {
+			if segment.Last.Before(cutoff) {
+				if err := os.Remove(filepath.Join(r.cfg.Dir, segment.File)); err != nil && !os.IsNotExist(err) {
+					log.Printf("Error removing expired segment %s: %v", segment.File, err)
+					kept = append(kept, segment)
+				}
+				continue
+			}
+			kept = append(kept, segment)
+		}
+		r.index = kept
+	}
+
+	if err := r.saveIndex(); err != nil {
+		log.Printf("Error saving recording index: %v", err)
+	}
+}
+
+func (r *Recorder) saveIndex() error {
+	path := filepath.Join(r.cfg.Dir, recordingIndexName)
+	tmpPath := path + ".tmp"
+
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(file)
+	encoder := json.NewEncoder(w)
+	for _, segment := range r.index {
+		if err := encoder.Encode(segment); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := errors.Join(w.Flush(), file.Sync(), file.Close()); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
+// recover indexes the segments a crash left unfinished, rewriting each as a
+// complete gzip file of the ticks that can still be read, and picks the
+// number of the next segment.
+func (r *Recorder) recover() error {
+	indexed := make(map[string]bool)
+	for _, segment := range r.index {
+		indexed[segment.File] = true
+	}
+
+	names, err := filepath.Glob(filepath.Join(r.cfg.Dir, "ticks-*.jsonl.gz"))
+	if err != nil {
+		return err
+	}
+	slices.Sort(names)
+
+	for _, path := range names {
+		name := filepath.Base(path)
+
+		var sequence int
+		if _, err := fmt.Sscanf(name, segmentNameFormat, &sequence); err != nil {
+			continue
+		}
+		r.next = max(r.next, sequence+1)
+
+		if indexed[name] {
+			continue
+		}
+
+		info, err := repairSegment(path)
+		if err != nil {
+			return fmt.Errorf("failed to recover segment %s: %v", name, err)
+		}
+		log.Printf("Recovered %d tick(s) from unfinished segment %s", info.Ticks, name)
+		r.addSegment(info)
+	}
+
+	return nil
+}
+
+// repairSegment reads the ticks of a segment up to the first damaged one and
+// writes them back as a complete gzip file.
+func repairSegment(path string) (SegmentInfo, error) {
+	info := SegmentInfo{File: filepath.Base(path)}
+
+	var lines [][]byte
+	if file, err := os.Open(path); err == nil {
+		if gz, err := gzip.NewReader(file); err == nil {
+			scanner := bufio.NewScanner(gz)
+			scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+			for scanner.Scan() {
+				var entry recordedTick
+				if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+					break
+				}
+				info.add(&entry)
+				lines = append(lines, slices.Clone(scanner.Bytes()))
+			}
+		}
+		file.Close()
+	}
+
+	if info.Ticks == 0 {
+		return info, nil
+	}
+
+	tmpPath := path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return info, err
+	}
+
+	gz := gzip.NewWriter(file)
+	for _, line := range lines {
+		gz.Write(line)
+		gz.Write([]byte{'\n'})
+	}
+	if err := errors.Join(gz.Close(), file.Close()); err != nil {
+		return info, err
+	}
+
+	return info, os.Rename(tmpPath, path)
+}
+
+func readRecordingIndex(dir string) ([]SegmentInfo, error) {
+	file, err := os.Open(filepath.Join(dir, recordingIndexName))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var index []SegmentInfo
+	decoder := json.NewDecoder(file)
+	for {
+		var segment SegmentInfo
+		if err := decoder.Decode(&segment); err == io.EOF {
+			return index, nil
+		} else if err != nil {
+			return nil, err
+		}
+		index = append(index, segment)
+	}
+}
+
+// ReadRecordingIndex returns the finished segments of the recording in dir,
+// oldest first.
+func ReadRecordingIndex(dir string) ([]SegmentInfo, error) {
+	index, err := readRecordingIndex(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording index in %s: %v", dir, err)
+	}
+	return index, nil
+}
+
+// OpenRecording reads the ticks of a recording in [from, to), opening only
+// the segments whose time range overlaps it. A zero time leaves that end
+// open.
+func OpenRecording(dir string, from, to time.Time) (TickReader, error) {
+	index, err := ReadRecordingIndex(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, segment := range index {
+		if (!from.IsZero() && segment.Last.Before(from)) || (!to.IsZero() && !segment.First.Before(to)) {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, segment.File))
+	}
+
+	return newRangeTickReader(&chainedTickReader{paths: paths}, from, to), nil
+}
+
+// chainedTickReader reads files one after another, opening each only when
+// the previous one is exhausted.
+type chainedTickReader struct {
+	paths   []string
+	current TickReader
+}
+
+func (c *chainedTickReader) Next() (*models.Tick, error) {
+	for {
+		if c.current == nil {
+			if len(c.paths) == 0 {
+				return nil, io.EOF
+			}
+
+			reader, err := OpenTickFile(c.paths[0])
+			if err != nil {
+				return nil, err
+			}
+			c.current = reader
+			c.paths = c.paths[1:]
+		}
+
+		tick, err := c.current.Next()
+		if err != io.EOF {
+			return tick, err
+		}
+
+		c.current.Close()
+		c.current = nil
+	}
+}
+
+func (c *chainedTickReader) Close() error {
+	if c.current == nil {
+		return nil
+	}
+	return c.current.Close()
+}
+
+// rangeTickReader skips the ticks outside [from, to).
+type rangeTickReader struct {
+	reader   TickReader
+	from, to time.Time
+}
+
+func newRangeTickReader(reader TickReader, from, to time.Time) TickReader {
+	if from.IsZero() && to.IsZero() {
+		return reader
+	}
+	return &rangeTickReader{reader: reader, from: from, to: to}
+}
+
+func (r *rangeTickReader) Next() (*models.Tick, error) {
+	for {
+		tick, err := r.reader.Next()
+		if err != nil {
+			return nil, err
+		}
+		if (!r.from.IsZero() && tick.Timestamp.Before(r.from)) || (!r.to.IsZero() && !tick.Timestamp.Before(r.to)) {
+			continue
+		}
+		return tick, nil
+	}
+}
+
+func (r *rangeTickReader) Close() error {
+	return r.reader.Close()
+}
@@ -0,0 +1,171 @@
+package datafeed
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"crypto-price-alerts/pkg/clock"
+	"crypto-price-alerts/pkg/models"
+)
+
+func recordTick(r *Recorder, symbol string, price float64, at time.Time) {
+	r.write(&recordedTick{Symbol: symbol, Price: price, Source: "binance", Timestamp: at, Received: r.clock.Now()})
+}
+
+func TestRecorder_RotatesAndIndexesSegments(t *testing.T) {
+	dir := t.TempDir()
+	fake := clock.NewFake(replayStart)
+	recorder, err := NewRecorder(RecorderConfig{Dir: dir, SegmentDuration: time.Minute, Clock: fake})
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+
+	for i := 0; i < 6; i++ {
+		at := replayStart.Add(time.Duration(i) * 30 * time.Second)
+		fake.Set(at)
+		recordTick(recorder, "BTC", float64(100+i), at)
+	}
+	recorder.closeSegment()
+
+	segments := recorder.Segments()
+	if len(segments) != 3 {
+		t.Fatalf("Expected a segment per minute, got %+v", segments)
+	}
+	for i, segment := range segments {
+		first := replayStart.Add(time.Duration(i) * time.Minute)
+		if segment.Ticks != 2 || !segment.First.Equal(first) || !segment.Last.Equal(first.Add(30*time.Second)) {
+			t.Errorf("Segment %d = %+v", i, segment)
+		}
+	}
+
+	index, err := ReadRecordingIndex(dir)
+	if err != nil || len(index) != 3 || index[2].File != "ticks-000003.jsonl.gz" {
+		t.Errorf("ReadRecordingIndex() = %+v, %v", index, err)
+	}
+
+	reader, err := OpenTickFile(dir)
+	if err != nil {
+		t.Fatalf("OpenTickFile() error = %v", err)
+	}
+	ticks := readAll(t, reader)
+	if len(ticks) != 6 || ticks[5].Price != 105 || ticks[5].Source != "binance" || !ticks[5].Timestamp.Equal(replayStart.Add(150*time.Second)) {
+		t.Errorf("Expected the recording to replay every tick, got %d ticks", len(ticks))
+	}
+
+	// Range reads skip whole segments, so removing one outside the range
+	// must not matter.
+	os.Remove(filepath.Join(dir, segments[0].File))
+	reader, err = OpenRecording(dir, replayStart.Add(90*time.Second), replayStart.Add(150*time.Second))
+	if err != nil {
+		t.Fatalf("OpenRecording() error = %v", err)
+	}
+	var prices []float64
+	for _, tick := range readAll(t, reader) {
+		prices = append(prices, tick.Price)
+	}
+	if len(prices) != 2 || prices[0] != 103 || prices[1] != 104 {
+		t.Errorf("Expected the ticks in range only, got prices %v", prices)
+	}
+}
+
+func TestRecorder_SegmentSizeAndRetention(t *testing.T) {
+	dir := t.TempDir()
+	fake := clock.NewFake(replayStart)
+	recorder, err := NewRecorder(RecorderConfig{Dir: dir, SegmentSize: 1, Retention: time.Hour, Clock: fake})
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+
+	recordTick(recorder, "BTC", 1, replayStart)
+	recordTick(recorder, "BTC", 2, replayStart.Add(45*time.Minute))
+	if segments := recorder.Segments(); len(segments) != 1 {
+		t.Fatalf("Expected a full segment to be finished, got %+v", segments)
+	}
+
+	fake.Set(replayStart.Add(90 * time.Minute))
+	recordTick(recorder, "BTC", 3, fake.Now())
+	recorder.closeSegment()
+
+	segments := recorder.Segments()
+	if len(segments) != 2 || segments[0].File != "ticks-000002.jsonl.gz" {
+		t.Errorf("Expected the expired segment to be dropped, got %+v", segments)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ticks-000001.jsonl.gz")); !os.IsNotExist(err) {
+		t.Errorf("Expected the expired segment file to be removed, got %v", err)
+	}
+}
+
+func TestRecorder_RecoversUnfinishedSegment(t *testing.T) {
+	dir := t.TempDir()
+	crashed, err := NewRecorder(RecorderConfig{Dir: dir})
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+
+	recordTick(crashed, "BTC", 100, replayStart)
+	recordTick(crashed, "ETH", 4000, replayStart.Add(time.Second))
+	crashed.flush()
+	recordTick(crashed, "SOL", 200, replayStart.Add(2*time.Second)) // never flushed
+
+	recorder, err := NewRecorder(RecorderConfig{Dir: dir})
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+
+	segments := recorder.Segments()
+	if len(segments) != 1 || segments[0].Ticks != 2 || !segments[0].Last.Equal(replayStart.Add(time.Second)) {
+		t.Fatalf("Expected the flushed ticks to be recovered, got %+v", segments)
+	}
+	if ticks := readAll(t, mustOpen(t, dir)); len(ticks) != 2 {
+		t.Errorf("Expected the recovered segment to be readable, got %d ticks", len(ticks))
+	}
+
+	recordTick(recorder, "BTC", 101, replayStart.Add(time.Hour))
+	if recorder.current.info.File != "ticks-000002.jsonl.gz" {
+		t.Errorf("Expected numbering to continue after the recovered segment, got %s", recorder.current.info.File)
+	}
+}
+
+func TestRecorder_RecordsLiveTicks(t *testing.T) {
+	dir := t.TempDir()
+	recorder, err := NewRecorder(RecorderConfig{Dir: dir})
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	recorder.Start(context.Background())
+
+	for i := 0; i < 100; i++ {
+		recorder.Record(models.NewTick("BTC", float64(i+1), replayStart.Add(time.Duration(i)*time.Millisecond)))
+	}
+	recorder.Stop()
+
+	feed, err := NewReplayFeed(Config{ReplayFiles: []string{dir}, ReplayFrom: replayStart.Add(50 * time.Millisecond)})
+	if err != nil {
+		t.Fatalf("NewReplayFeed() error = %v", err)
+	}
+	feed.Start(context.Background())
+	defer feed.Stop()
+
+	count := 0
+	for tick := range feed.TickChannel() {
+		count++
+		if tick.Price <= 50 {
+			t.Errorf("Unexpected tick before the replay range: %+v", tick)
+		}
+	}
+	if count != 50 {
+		t.Errorf("Expected 50 ticks in range, got %d", count)
+	}
+}
+
+func mustOpen(t *testing.T, path string) TickReader {
+	t.Helper()
+	reader, err := OpenTickFile(path)
+	if err != nil {
+		t.Fatalf("OpenTickFile(%s) error = %v", path, err)
+	}
+	return reader
+}
@@ -0,0 +1,585 @@
+package datafeed
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"crypto-price-alerts/pkg/models"
+)
+
+// TickReader reads recorded ticks in time order.
+type TickReader interface {
+	// Next returns the next tick, or io.EOF after the last one.
+	Next() (*models.Tick, error)
+	Close() error
+}
+
+// OpenTickFile opens a file of recorded ticks. The format follows the
+// extension, after an optional .gz which is decompressed:
+//
+//	.csv           a header row naming the columns timestamp, symbol, price
+//	               and optionally volume and source, then one tick per row
+//	.jsonl/.ndjson one tick per line, as models.Tick encodes it
+//	.json          columnar: an object holding one equal-length array per
+//	               column, named as in the CSV header
+//
+// Timestamps are RFC 3339 strings or Unix milliseconds. Symbols are
+// normalized with NormalizeSymbol. A directory is read as a Recorder's
+// recording.
+func OpenTickFile(path string) (TickReader, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return OpenRecording(path, time.Time{}, time.Time{})
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tick file: %v", err)
+	}
+	closers := closerList{file}
+
+	var r io.Reader = file
+	name := path
+	if base, compressed := strings.CutSuffix(path, ".gz"); compressed {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to decompress %s: %v", path, err)
+		}
+		r = gz
+		closers = append(closerList{gz}, closers...)
+		name = base
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".csv":
+		return newCSVTickReader(path, r, closers)
+	case ".jsonl", ".ndjson":
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		return &jsonlTickReader{path: path, scanner: scanner, closer: closers}, nil
+	case ".json":
+		defer closers.Close()
+		return readColumnarTicks(path, r)
+	default:
+		closers.Close()
+		return nil, fmt.Errorf("unsupported tick file format %q (expected .csv, .jsonl or .json)", ext)
+	}
+}
+
+// OpenTicks opens several tick files and reads them as one stream, oldest
+// tick first. Each file must itself be in time order.
+func OpenTicks(paths ...string) (TickReader, error) {
+	return OpenTicksBetween(time.Time{}, time.Time{}, paths...)
+}
+
+// OpenTicksBetween is OpenTicks limited to ticks in [from, to). A zero time
+// leaves that end open. Recordings only open the segments the range needs.
+func OpenTicksBetween(from, to time.Time, paths ...string) (TickReader, error) {
+	merged := &mergedTickReader{}
+	for _, path := range paths {
+		var reader TickReader
+		var err error
+		if info, statErr := os.Stat(path); statErr == nil && info.IsDir() {
+			reader, err = OpenRecording(path, from, to)
+		} else if reader, err = OpenTickFile(path); err == nil {
+			reader = newRangeTickReader(reader, from, to)
+		}
+		if err != nil {
+			merged.Close()
+			return nil, err
+		}
+		merged.readers = append(merged.readers, reader)
+	}
+	merged.heads = make([]*models.Tick, len(merged.readers))
+	merged.done = make([]bool, len(merged.readers))
+
+	return merged, nil
+}
+
+type closerList []io.Closer
+
+func (c closerList) Close() error {
+	var errs []error
+	for _, closer := range c {
+		errs = append(errs, closer.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// parseTimestamp reads an RFC 3339 time or a count of Unix milliseconds.
+func parseTimestamp(value string) (time.Time, error) {
+	if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.UnixMilli(millis).UTC(), nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+	}
+	return t, nil
+}
+
+func newReplayTick(symbol string, price, volume float64, source string, timestamp time.Time) (*models.Tick, error) {
+	symbol = NormalizeSymbol(symbol)
+	if symbol == "" {
+		return nil, fmt.Errorf("missing symbol")
+	}
+	if price <= 0 {
+		return nil, fmt.Errorf("price must be positive, got %v", price)
+	}
+
+	return &models.Tick{Symbol: symbol, Price: price, Volume: volume, Source: source, Timestamp: timestamp}, nil
+}
+
+type csvTickReader struct {
+	path    string
+	reader  *csv.Reader
+	columns map[string]int
+	closer  io.Closer
+	line    int
+}
+
+func newCSVTickReader(path string, r io.Reader, closer io.Closer) (*csvTickReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		closer.Close()
+		return nil, fmt.Errorf("%s: failed to read header: %v", path, err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"timestamp", "symbol", "price"} {
+		if _, exists := columns[required]; !exists {
+			closer.Close()
+			return nil, fmt.Errorf("%s: header has no %s column", path, required)
+		}
+	}
+
+	return &csvTickReader{path: path, reader: reader, columns: columns, closer: closer, line: 1}, nil
+}
+
+func (r *csvTickReader) Next() (*models.Tick, error) {
+	record, err := r.reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("%s: %v", r.path, err)
+	}
+	r.line++
+
+	field := func(name string) string {
+		if i, exists := r.columns[name]; exists && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	tick, err := r.parse(field)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%d: %v", r.path, r.line, err)
+	}
+	return tick, nil
+}
+
+func (r *csvTickReader) parse(field func(name string) string) (*models.Tick, error) {
+	timestamp, err := parseTimestamp(field("timestamp"))
+	if err != nil {
+		return nil, err
+	}
+
+	price, err := strconv.ParseFloat(field("price"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid price %q", field("price"))
+	}
+
+	var volume float64
+	if value := field("volume"); value != "" {
+		if volume, err = strconv.ParseFloat(value, 64); err != nil {
+			return nil, fmt.Errorf("invalid volume %q", value)
+		}
+	}
+
+	return newReplayTick(field("symbol"), price, volume, field("source"), timestamp)
+}
+
+func (r *csvTickReader) Close() error {
+	return r.closer.Close()
+}
+
+// tickRecord is a recorded tick as JSON, with a timestamp that may be a
+// string or a number.
+type tickRecord struct {
+	Symbol    string          `json:"symbol"`
+	Price     float64         `json:"price"`
+	Volume    float64         `json:"volume"`
+	Source    string          `json:"source"`
+	Timestamp json.RawMessage `json:"timestamp"`
+}
+
+func jsonTimestamp(raw json.RawMessage) (time.Time, error) {
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		value = string(raw)
+	}
+	return parseTimestamp(value)
+}
+
+type jsonlTickReader struct {
+	path    string
+	scanner *bufio.Scanner
+	closer  io.Closer
+	line    int
+}
+
+func (r *jsonlTickReader) Next() (*models.Tick, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := strings.TrimSpace(r.scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		tick, err := r.parse(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", r.path, r.line, err)
+		}
+		return tick, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %v", r.path, err)
+	}
+	return nil, io.EOF
+}
+
+func (r *jsonlTickReader) parse(line string) (*models.Tick, error) {
+	var record tickRecord
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		return nil, fmt.Errorf("invalid tick: %v", err)
+	}
+
+	timestamp, err := jsonTimestamp(record.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	return newReplayTick(record.Symbol, record.Price, record.Volume, record.Source, timestamp)
+}
+
+func (r *jsonlTickReader) Close() error {
+	return r.closer.Close()
+}
+
+type tickColumns struct {
+	Timestamp []json.RawMessage `json:"timestamp"`
+	Symbol    []string          `json:"symbol"`
+	Price     []float64         `json:"price"`
+	Volume    []float64         `json:"volume"`
+	Source    []string          `json:"source"`
+}
+
+// readColumnarTicks decodes a whole columnar file, which has to be held in
+// memory to be read row by row.
+func readColumnarTicks(path string, r io.Reader) (*sliceTickReader, error) {
+	var columns tickColumns
+	if err := json.NewDecoder(r).Decode(&columns); err != nil {
+		return nil, fmt.Errorf("%s: invalid columnar tick file: %v", path, err)
+	}
+
+	rows := len(columns.Timestamp)
+	if len(columns.Symbol) != rows || len(columns.Price) != rows ||
+		(columns.Volume != nil && len(columns.Volume) != rows) ||
+		(columns.Source != nil && len(columns.Source) != rows) {
+		return nil, fmt.Errorf("%s: columns have different lengths", path)
+	}
+
+	ticks := make([]*models.Tick, 0, rows)
+	for i := 0; i < rows; i++ {
+		timestamp, err := jsonTimestamp(columns.Timestamp[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: row %d: %v", path, i+1, err)
+		}
+
+		var volume float64
+		var source string
+		if columns.Volume != nil {
+			volume = columns.Volume[i]
+		}
+		if columns.Source != nil {
+			source = columns.Source[i]
+		}
+
+		tick, err := newReplayTick(columns.Symbol[i], columns.Price[i], volume, source, timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("%s: row %d: %v", path, i+1, err)
+		}
+		ticks = append(ticks, tick)
+	}
+
+	return &sliceTickReader{ticks: ticks}, nil
+}
+
+type sliceTickReader struct {
+	ticks []*models.Tick
+	next  int
+}
+
+func (r *sliceTickReader) Next() (*models.Tick, error) {
+	if r.next >= len(r.ticks) {
+		return nil, io.EOF
+	}
+	r.next++
+	return r.ticks[r.next-1], nil
+}
+
+func (r *sliceTickReader) Close() error {
+	return nil
+}
+
+// mergedTickReader interleaves several readers by timestamp. Ties go to the
+// reader opened first.
+type mergedTickReader struct {
+	readers []TickReader
+	heads   []*models.Tick
+	done    []bool
+}
+
+func (m *mergedTickReader) Next() (*models.Tick, error) {
+	next := -1
+	for i, reader := range m.readers {
+		if m.heads[i] == nil && !m.done[i] {
+			tick, err := reader.Next()
+			switch {
+			case err == io.EOF:
+				m.done[i] = true
+				continue
+			case err != nil:
+				return nil, err
+			}
+			m.heads[i] = tick
+		}
+
+		if m.heads[i] != nil && (next < 0 || m.heads[i].Timestamp.Before(m.heads[next].Timestamp)) {
+			next = i
+		}
+	}
+
+	if next < 0 {
+		return nil, io.EOF
+	}
+
+	tick := m.heads[next]
+	m.heads[next] = nil
+	return tick, nil
+}
+
+func (m *mergedTickReader) Close() error {
+	var errs []error
+	for _, reader := range m.readers {
+		errs = append(errs, reader.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// ReplayFeed plays recorded ticks back with their original timestamps. At a
+// speed of 1 the gaps between ticks are kept, at 10 they are a tenth as
+// long, and at 0 ticks are sent as fast as they are read. The feed goes down
+// once every tick has been sent.
+type ReplayFeed struct {
+	reader   TickReader
+	speed    float64
+	symbols  map[string]bool
+	prices   *PriceCache
+	tickChan chan *models.Tick
+	stopChan chan struct{}
+	done     chan struct{}
+	running  bool
+	finished bool
+	err      error
+	mu       sync.RWMutex
+}
+
+func init() {
+	Register("replay", func(cfg Config) (Feed, error) {
+		return NewReplayFeed(cfg)
+	})
+}
+
+// NewReplayFeed opens cfg.ReplayFiles for replay at cfg.ReplaySpeed. When
+// cfg.Symbols is set, ticks for other symbols are skipped, as are ticks
+// outside [cfg.ReplayFrom, cfg.ReplayTo).
+func NewReplayFeed(cfg Config) (*ReplayFeed, error) {
+	if len(cfg.ReplayFiles) == 0 {
+		return nil, fmt.Errorf("replay feed requires at least one tick file")
+	}
+	if cfg.ReplaySpeed < 0 {
+		return nil, fmt.Errorf("replay speed must not be negative")
+	}
+
+	reader, err := OpenTicksBetween(cfg.ReplayFrom, cfg.ReplayTo, cfg.ReplayFiles...)
+	if err != nil {
+		return nil, err
+	}
+
+	var symbols map[string]bool
+	if len(cfg.Symbols) > 0 {
+		symbols = make(map[string]bool)
+		for _, symbol := range cfg.Symbols {
+			symbols[NormalizeSymbol(symbol)] = true
+		}
+	}
+
+	return &ReplayFeed{
+		reader:   reader,
+		speed:    cfg.ReplaySpeed,
+		symbols:  symbols,
+		prices:   NewPriceCache(),
+		tickChan: make(chan *models.Tick, cfg.tickBuffer()),
+		stopChan: make(chan struct{}),
+		done:     make(chan struct{}),
+	}, nil
+}
+
+func (f *ReplayFeed) Start(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.running || f.finished {
+		return nil
+	}
+	f.running = true
+
+	go f.replay(ctx)
+	return nil
+}
+
+// Stop ends the replay early and waits for the tick channel to close.
+func (f *ReplayFeed) Stop() {
+	f.mu.Lock()
+	if !f.running {
+		f.mu.Unlock()
+		return
+	}
+	f.running = false
+	close(f.stopChan)
+	f.mu.Unlock()
+
+	<-f.done
+}
+
+func (f *ReplayFeed) TickChannel() <-chan *models.Tick {
+	return f.tickChan
+}
+
+func (f *ReplayFeed) GetCurrentPrice(symbol string) (float64, bool) {
+	return f.prices.Get(symbol)
+}
+
+func (f *ReplayFeed) Status() FeedStatus {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.running && !f.finished {
+		return FeedStatusConnected
+	}
+	return FeedStatusDown
+}
+
+// Err returns the error that ended the replay early, if any.
+func (f *ReplayFeed) Err() error {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.err
+}
+
+func (f *ReplayFeed) replay(ctx context.Context) {
+	defer close(f.done)
+	defer close(f.tickChan)
+	defer f.reader.Close()
+
+	var last time.Time
+	replayed := 0
+
+	for {
+		tick, err := f.reader.Next()
+		if err == io.EOF {
+			log.Printf("Replay finished after %d ticks", replayed)
+			f.finish(nil)
+			return
+		}
+		if err != nil {
+			log.Printf("Replay stopped after %d ticks: %v", replayed, err)
+			f.finish(err)
+			return
+		}
+
+		if f.symbols != nil && !f.symbols[tick.Symbol] {
+			continue
+		}
+
+		if f.speed > 0 && !last.IsZero() && tick.Timestamp.After(last) {
+			if !f.wait(ctx, time.Duration(float64(tick.Timestamp.Sub(last))/f.speed)) {
+				f.finish(nil)
+				return
+			}
+		}
+		if tick.Timestamp.After(last) {
+			last = tick.Timestamp
+		}
+
+		f.prices.Observe(tick)
+
+		select {
+		case f.tickChan <- tick:
+			replayed++
+		case <-f.stopChan:
+			f.finish(nil)
+			return
+		case <-ctx.Done():
+			f.finish(nil)
+			return
+		}
+	}
+}
+
+// wait sleeps for d unless the replay is stopped first.
+func (f *ReplayFeed) wait(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-f.stopChan:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (f *ReplayFeed) finish(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.finished = true
+	f.err = err
+}
@@ -0,0 +1,168 @@
+package datafeed
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"crypto-price-alerts/pkg/models"
+)
+
+var replayStart = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func writeTickFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var w io.Writer = file
+	if filepath.Ext(name) == ".gz" {
+		gz := gzip.NewWriter(file)
+		defer gz.Close()
+		w = gz
+	}
+	if _, err := io.WriteString(w, content); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func readAll(t *testing.T, reader TickReader) []*models.Tick {
+	t.Helper()
+	defer reader.Close()
+
+	var ticks []*models.Tick
+	for {
+		tick, err := reader.Next()
+		if err == io.EOF {
+			return ticks
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		ticks = append(ticks, tick)
+	}
+}
+
+func TestOpenTickFile_Formats(t *testing.T) {
+	expected := []models.Tick{
+		{Symbol: "BTC", Price: 100000, Volume: 2, Source: "binance", Timestamp: replayStart},
+		{Symbol: "ETH", Price: 4000, Source: "binance", Timestamp: replayStart.Add(1500 * time.Millisecond)},
+	}
+
+	files := map[string]string{
+		"ticks.csv": "timestamp,symbol,price,volume,source\n" +
+			"2025-01-01T12:00:00Z,BTCUSDT,100000,2,binance\n" +
+			"1735732801500,ETH,4000,,binance\n",
+		"ticks.jsonl.gz": `{"symbol":"BTC","price":100000,"volume":2,"source":"binance","timestamp":"2025-01-01T12:00:00Z"}` + "\n\n" +
+			`{"symbol":"ETH-USD","price":4000,"source":"binance","timestamp":1735732801500}` + "\n",
+		"ticks.json": `{"timestamp":["2025-01-01T12:00:00Z",1735732801500],"symbol":["BTC","ETH"],` +
+			`"price":[100000,4000],"volume":[2,0],"source":["binance","binance"]}`,
+	}
+
+	for name, content := range files {
+		reader, err := OpenTickFile(writeTickFile(t, name, content))
+		if err != nil {
+			t.Errorf("OpenTickFile(%s) error = %v", name, err)
+			continue
+		}
+
+		ticks := readAll(t, reader)
+		if len(ticks) != len(expected) {
+			t.Errorf("%s: got %d ticks, expected %d", name, len(ticks), len(expected))
+			continue
+		}
+		for i, tick := range ticks {
+			if tick.Symbol != expected[i].Symbol || tick.Price != expected[i].Price || tick.Volume != expected[i].Volume ||
+				tick.Source != expected[i].Source || !tick.Timestamp.Equal(expected[i].Timestamp) {
+				t.Errorf("%s: tick %d = %+v, expected %+v", name, i, tick, expected[i])
+			}
+		}
+	}
+}
+
+func TestOpenTickFile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"missing-column.csv", "timestamp,symbol\n2025-01-01T12:00:00Z,BTC\n"},
+		{"bad-price.csv", "timestamp,symbol,price\n2025-01-01T12:00:00Z,BTC,abc\n"},
+		{"bad-time.jsonl", `{"symbol":"BTC","price":1,"timestamp":"yesterday"}`},
+		{"uneven.json", `{"timestamp":[1,2],"symbol":["BTC"],"price":[1,2]}`},
+		{"ticks.parquet", ""},
+	}
+
+	for _, tt := range tests {
+		reader, err := OpenTickFile(writeTickFile(t, tt.name, tt.content))
+		if err == nil {
+			_, err = reader.Next()
+			reader.Close()
+		}
+		if err == nil || err == io.EOF {
+			t.Errorf("%s: expected an error, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestOpenTicks_MergesByTime(t *testing.T) {
+	first := writeTickFile(t, "a.csv", "timestamp,symbol,price\n1000,BTC,1\n3000,BTC,3\n")
+	second := writeTickFile(t, "b.csv", "timestamp,symbol,price\n2000,ETH,2\n3000,ETH,4\n")
+
+	reader, err := OpenTicks(first, second)
+	if err != nil {
+		t.Fatalf("OpenTicks() error = %v", err)
+	}
+
+	var prices []float64
+	for _, tick := range readAll(t, reader) {
+		prices = append(prices, tick.Price)
+	}
+	if len(prices) != 4 || prices[0] != 1 || prices[1] != 2 || prices[2] != 3 || prices[3] != 4 {
+		t.Errorf("Expected ticks in time order, got prices %v", prices)
+	}
+}
+
+func TestReplayFeed(t *testing.T) {
+	path := writeTickFile(t, "ticks.csv", "timestamp,symbol,price\n"+
+		"2025-01-01T12:00:00Z,BTC,100\n"+
+		"2025-01-01T12:00:00.05Z,SOL,5\n"+
+		"2025-01-01T12:00:00.1Z,BTC,101\n")
+
+	feed, err := New("replay", Config{Symbols: []string{"BTC"}, ReplayFiles: []string{path}, ReplaySpeed: 1})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	begin := time.Now()
+	feed.Start(context.Background())
+	defer feed.Stop()
+
+	var prices []float64
+	for tick := range feed.TickChannel() {
+		prices = append(prices, tick.Price)
+	}
+
+	if len(prices) != 2 || prices[0] != 100 || prices[1] != 101 {
+		t.Errorf("Expected the BTC ticks only, got prices %v", prices)
+	}
+	if elapsed := time.Since(begin); elapsed < 100*time.Millisecond {
+		t.Errorf("Expected the recorded gaps to be kept, replay took %v", elapsed)
+	}
+	if price, ok := feed.GetCurrentPrice("BTC"); !ok || price != 101 {
+		t.Errorf("GetCurrentPrice() = %v, %v", price, ok)
+	}
+	if feed.Status() != FeedStatusDown {
+		t.Errorf("Expected the feed to be down after the last tick, got %s", feed.Status())
+	}
+}
@@ -0,0 +1,22 @@
+package datafeed
+
+type FeedStatus int
+
+const (
+	FeedStatusDown FeedStatus = iota
+	FeedStatusConnected
+	FeedStatusReconnecting
+)
+
+func (s FeedStatus) String() string {
+	switch s {
+	case FeedStatusDown:
+		return "down"
+	case FeedStatusConnected:
+		return "connected"
+	case FeedStatusReconnecting:
+		return "reconnecting"
+	default:
+		return "unknown"
+	}
+}
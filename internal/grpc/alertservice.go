@@ -2,61 +2,227 @@ package grpc
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"strconv"
+	"strings"
+	"time"
 
 	pb "crypto-price-alerts/api/gen/crypto-price-alerts/api/gen"
 	"crypto-price-alerts/internal/alerts"
+	"crypto-price-alerts/internal/auth"
+	"crypto-price-alerts/internal/notify"
+	"crypto-price-alerts/internal/triggerhistory"
+	"crypto-price-alerts/pkg/clock"
 	"crypto-price-alerts/pkg/models"
+	"crypto-price-alerts/pkg/rules"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const catchUpInterval = time.Second
+
 type CryptoAlertServiceServer struct {
 	pb.UnimplementedCryptoAlertServiceServer
-	store      *alerts.Store
+	store      alerts.Storage
 	triggerBus *alerts.TriggerBus
+	history    *triggerhistory.Store
+	// webhookHosts are the hosts alerts may set their own webhook URL to.
+	webhookHosts []string
+	clock        clock.Clock
 }
 
-func NewCryptoAlertServiceServer(store *alerts.Store, triggerBus *alerts.TriggerBus) *CryptoAlertServiceServer {
+func NewCryptoAlertServiceServer(store alerts.Storage, triggerBus *alerts.TriggerBus, history *triggerhistory.Store) *CryptoAlertServiceServer {
 	return &CryptoAlertServiceServer{
 		store:      store,
 		triggerBus: triggerBus,
+		history:    history,
+		clock:      clock.Real,
 	}
 }
 
+// SetClock sets the clock expiry times are checked against. It should be the
+// engine's, which decides when alerts expire.
+func (s *CryptoAlertServiceServer) SetClock(c clock.Clock) {
+	s.clock = clock.OrReal(c)
+}
+
+// SetWebhookHosts sets the hosts alerts may send their own webhooks to. With
+// none, the default, alerts cannot have a webhook URL.
+func (s *CryptoAlertServiceServer) SetWebhookHosts(hosts []string) {
+	s.webhookHosts = hosts
+}
+
 func (s *CryptoAlertServiceServer) CreateAlert(ctx context.Context, req *pb.CreateAlertRequest) (*pb.CreateAlertResponse, error) {
-	if req.Symbol == "" {
-		return nil, status.Error(codes.InvalidArgument, "symbol is required")
+	identity, err := callerIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerID := identity.UserID
+	if req.OwnerId != "" && req.OwnerId != ownerID {
+		if !identity.IsAdmin() {
+			return nil, status.Error(codes.PermissionDenied, "only admins can create alerts for other users")
+		}
+		ownerID = req.OwnerId
+	}
+
+	switch req.Kind {
+	case pb.AlertKind_ALERT_KIND_COMPOUND, pb.AlertKind_ALERT_KIND_EXPRESSION:
+	case pb.AlertKind_ALERT_KIND_MA_CROSSOVER, pb.AlertKind_ALERT_KIND_BOLLINGER:
+		if req.Symbol == "" {
+			return nil, status.Error(codes.InvalidArgument, "symbol is required")
+		}
+	default:
+		if req.Symbol == "" {
+			return nil, status.Error(codes.InvalidArgument, "symbol is required")
+		}
+
+		if req.Threshold <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "threshold must be positive")
+		}
+	}
+
+	var alert *models.Alert
+
+	switch req.Kind {
+	case pb.AlertKind_ALERT_KIND_COMPOUND:
+		condition, err := convertConditionFromProto(req.Condition)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		alert = models.NewCompoundAlert(condition, req.Note)
+
+	case pb.AlertKind_ALERT_KIND_EXPRESSION:
+		program, err := rules.Compile(req.Expression)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid rule: %v", err)
+		}
+
+		alert = models.NewExpressionAlert(program, req.Note)
+
+	case pb.AlertKind_ALERT_KIND_MA_CROSSOVER, pb.AlertKind_ALERT_KIND_RSI, pb.AlertKind_ALERT_KIND_BOLLINGER:
+		if req.Indicator == nil {
+			return nil, status.Error(codes.InvalidArgument, "indicator parameters are required")
+		}
+
+		indicator := convertIndicatorFromProto(req.Indicator)
+		direction := convertDirectionFromProto(req.Direction)
+
+		switch req.Kind {
+		case pb.AlertKind_ALERT_KIND_MA_CROSSOVER:
+			alert = models.NewMACrossoverAlert(req.Symbol, indicator.Average, indicator.Period, indicator.SlowPeriod,
+				indicator.Interval, direction, req.Note)
+		case pb.AlertKind_ALERT_KIND_RSI:
+			alert = models.NewRSIAlert(req.Symbol, indicator.Period, req.Threshold, indicator.Interval, direction, req.Note)
+		default:
+			alert = models.NewBollingerAlert(req.Symbol, indicator.Period, indicator.StdDevs, indicator.Interval, direction, req.Note)
+		}
+
+		if err := alert.ValidateIndicator(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+	case pb.AlertKind_ALERT_KIND_PERCENT_MOVE:
+		if req.Window == nil || req.Window.AsDuration() <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "window must be positive for percent-move alerts")
+		}
+
+		alert = models.NewPercentMoveAlert(req.Symbol, req.Threshold, req.Window.AsDuration(),
+			convertDirectionFromProto(req.Direction), req.Note)
+
+	case pb.AlertKind_ALERT_KIND_UNSPECIFIED, pb.AlertKind_ALERT_KIND_THRESHOLD:
+		if mode := convertTriggerModeFromProto(req.Mode); mode != models.TriggerModeLevel {
+			alert = models.NewCrossingAlert(req.Symbol, mode, req.Threshold, req.Note)
+			break
+		}
+
+		comparator := convertComparatorFromProto(req.Comparator)
+		if comparator == models.ComparatorUnspecified {
+			return nil, status.Error(codes.InvalidArgument, "invalid comparator")
+		}
+
+		alert = models.NewAlert(req.Symbol, comparator, req.Threshold, req.Note)
+
+	default:
+		return nil, status.Error(codes.InvalidArgument, "invalid alert kind")
 	}
 
-	if req.Threshold <= 0 {
-		return nil, status.Error(codes.InvalidArgument, "threshold must be positive")
+	if req.WebhookUrl != "" {
+		if err := notify.ValidateAlertURL(req.WebhookUrl, s.webhookHosts); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	if req.Cooldown != nil {
+		if req.Cooldown.AsDuration() < 0 {
+			return nil, status.Error(codes.InvalidArgument, "cooldown must not be negative")
+		}
+		alert.Cooldown = req.Cooldown.AsDuration()
 	}
 
-	comparator := convertComparatorFromProto(req.Comparator)
-	if comparator == models.ComparatorUnspecified {
-		return nil, status.Error(codes.InvalidArgument, "invalid comparator")
+	if req.ExpiresAt != nil {
+		expiresAt := req.ExpiresAt.AsTime()
+		if !expiresAt.After(s.clock.Now()) {
+			return nil, status.Error(codes.InvalidArgument, "expires_at must be in the future")
+		}
+		alert.ExpiresAt = &expiresAt
+	}
+
+	if req.MaxFires < 0 {
+		return nil, status.Error(codes.InvalidArgument, "max_fires must not be negative")
 	}
 
-	alert := models.NewAlert(req.Symbol, comparator, req.Threshold, req.Note)
+	alert.OwnerID = ownerID
+	alert.Source = req.Source
+	alert.Labels = normalizeLabels(req.Labels)
+	alert.Severity = convertSeverityFromProto(req.Severity)
+	alert.WebhookURL = req.WebhookUrl
+	if alert.WebhookURL != "" {
+		secret, err := notify.NewAlertSecret()
+		if err != nil {
+			log.Printf("Error generating webhook secret: %v", err)
+			return nil, status.Error(codes.Internal, "failed to create alert")
+		}
+		alert.WebhookSecret = secret
+	}
+	alert.FireOnce = req.FireOnce
+	alert.MaxFires = int(req.MaxFires)
 
 	if err := s.store.Create(alert); err != nil {
 		log.Printf("Error creating alert: %v", err)
 		return nil, status.Error(codes.Internal, "failed to create alert")
 	}
 
-	log.Printf("Created alert: %s %s %.2f for symbol %s", 
-		alert.Symbol, alert.Comparator.String(), alert.Threshold, alert.Symbol)
+	log.Printf("Created alert: %s", alert.Rule())
 
 	return &pb.CreateAlertResponse{
-		Alert: convertAlertToProto(alert),
+		Alert:         convertAlertToProto(alert),
+		WebhookSecret: alert.WebhookSecret,
 	}, nil
 }
 
 func (s *CryptoAlertServiceServer) GetAlerts(ctx context.Context, req *pb.GetAlertsRequest) (*pb.GetAlertsResponse, error) {
-	alerts := s.store.GetAll()
+	identity, err := callerIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []*models.Alert
+	switch {
+	case identity.IsAdmin() && req.OwnerId == "":
+		alerts = s.store.GetAll()
+	case identity.IsAdmin():
+		alerts = s.store.GetByOwner(req.OwnerId)
+	case req.OwnerId != "" && req.OwnerId != identity.UserID:
+		return nil, status.Error(codes.PermissionDenied, "only admins can list other users' alerts")
+	default:
+		alerts = s.store.GetByOwner(identity.UserID)
+	}
 
 	pbAlerts := make([]*pb.Alert, len(alerts))
 	for i, alert := range alerts {
@@ -73,8 +239,65 @@ func (s *CryptoAlertServiceServer) UpdateAlert(ctx context.Context, req *pb.Upda
 		return nil, status.Error(codes.InvalidArgument, "alert ID is required")
 	}
 
+	existing, err := s.authorizedAlert(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
 	updates := make(map[string]interface{})
 
+	if req.Symbol != nil || req.Comparator != nil || req.Threshold != nil {
+		switch existing.Kind {
+		case models.AlertKindCompound:
+			return nil, status.Error(codes.InvalidArgument, "compound alerts are changed through their condition")
+		case models.AlertKindExpression:
+			return nil, status.Error(codes.InvalidArgument, "expression alerts are changed through their expression")
+		}
+	}
+
+	if req.Expression != nil {
+		if existing.Kind != models.AlertKindExpression {
+			return nil, status.Error(codes.InvalidArgument, "only expression alerts have an expression")
+		}
+
+		program, err := rules.Compile(*req.Expression)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid rule: %v", err)
+		}
+		updates["expression"] = program
+	}
+
+	if req.Condition != nil {
+		if existing.Kind != models.AlertKindCompound {
+			return nil, status.Error(codes.InvalidArgument, "only compound alerts have a condition")
+		}
+
+		condition, err := convertConditionFromProto(req.Condition)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		updates["condition"] = condition
+		updates["symbol"] = condition.Symbols()[0]
+	}
+
+	if req.Indicator != nil || (existing.IsIndicator() && req.Threshold != nil) {
+		if !existing.IsIndicator() {
+			return nil, status.Error(codes.InvalidArgument, "only indicator alerts have indicator parameters")
+		}
+
+		candidate := *existing
+		if req.Indicator != nil {
+			candidate.Indicator = convertIndicatorFromProto(req.Indicator)
+			updates["indicator"] = candidate.Indicator
+		}
+		if req.Threshold != nil {
+			candidate.Threshold = *req.Threshold
+		}
+		if err := candidate.ValidateIndicator(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
 	if req.Symbol != nil {
 		if *req.Symbol == "" {
 			return nil, status.Error(codes.InvalidArgument, "symbol cannot be empty")
@@ -97,10 +320,93 @@ func (s *CryptoAlertServiceServer) UpdateAlert(ctx context.Context, req *pb.Upda
 		updates["threshold"] = *req.Threshold
 	}
 
+	if req.Source != nil {
+		updates["source"] = *req.Source
+	}
+
+	if req.Severity != nil {
+		updates["severity"] = convertSeverityFromProto(*req.Severity)
+	}
+
+	if req.Labels != nil {
+		updates["labels"] = normalizeLabels(req.Labels.Labels)
+	}
+
+	if req.WebhookUrl != nil {
+		if *req.WebhookUrl != "" {
+			if err := notify.ValidateAlertURL(*req.WebhookUrl, s.webhookHosts); err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+		}
+		updates["webhook_url"] = *req.WebhookUrl
+
+		// An alert keeps its secret when its URL changes; one getting its
+		// first webhook gets a new secret, returned with this response only.
+		if *req.WebhookUrl != "" && existing.WebhookSecret == "" {
+			secret, err := notify.NewAlertSecret()
+			if err != nil {
+				log.Printf("Error generating webhook secret: %v", err)
+				return nil, status.Error(codes.Internal, "failed to update alert")
+			}
+			updates["webhook_secret"] = secret
+		}
+	}
+
+	if req.Mode != nil {
+		mode := convertTriggerModeFromProto(*req.Mode)
+		if mode == models.TriggerModeLevel && req.Comparator == nil {
+			if existing.Comparator == models.ComparatorUnspecified {
+				return nil, status.Error(codes.InvalidArgument, "comparator is required for level-triggered alerts")
+			}
+		}
+		updates["mode"] = mode
+	}
+
+	if req.Direction != nil {
+		updates["direction"] = convertDirectionFromProto(*req.Direction)
+	}
+
+	if req.Window != nil {
+		if req.Window.AsDuration() <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "window must be positive")
+		}
+		updates["window"] = req.Window.AsDuration()
+	}
+
 	if req.Note != nil {
 		updates["note"] = *req.Note
 	}
 
+	if req.Cooldown != nil {
+		if req.Cooldown.AsDuration() < 0 {
+			return nil, status.Error(codes.InvalidArgument, "cooldown must not be negative")
+		}
+		updates["cooldown"] = req.Cooldown.AsDuration()
+	}
+
+	if req.ExpiresAt != nil {
+		var expiresAt *time.Time
+		if req.ExpiresAt.Seconds != 0 || req.ExpiresAt.Nanos != 0 {
+			t := req.ExpiresAt.AsTime()
+			if !t.After(s.clock.Now()) {
+				return nil, status.Error(codes.InvalidArgument, "expires_at must be in the future")
+			}
+			expiresAt = &t
+		}
+		updates["expires_at"] = expiresAt
+	}
+
+	if req.FireOnce != nil {
+		updates["fire_once"] = *req.FireOnce
+	}
+
+	if req.MaxFires != nil {
+		if *req.MaxFires < 0 {
+			return nil, status.Error(codes.InvalidArgument, "max_fires must not be negative")
+		}
+		updates["max_fires"] = int(*req.MaxFires)
+	}
+
 	if req.Enabled != nil {
 		updates["enabled"] = *req.Enabled
 	}
@@ -116,8 +422,10 @@ func (s *CryptoAlertServiceServer) UpdateAlert(ctx context.Context, req *pb.Upda
 
 	log.Printf("Updated alert: %s", req.Id)
 
+	secret, _ := updates["webhook_secret"].(string)
 	return &pb.UpdateAlertResponse{
-		Alert: convertAlertToProto(alert),
+		Alert:         convertAlertToProto(alert),
+		WebhookSecret: secret,
 	}, nil
 }
 
@@ -126,6 +434,10 @@ func (s *CryptoAlertServiceServer) DeleteAlert(ctx context.Context, req *pb.Dele
 		return nil, status.Error(codes.InvalidArgument, "alert ID is required")
 	}
 
+	if _, err := s.authorizedAlert(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
 	err := s.store.Delete(req.Id)
 	if err != nil {
 		if err == alerts.ErrAlertNotFound {
@@ -143,33 +455,191 @@ func (s *CryptoAlertServiceServer) DeleteAlert(ctx context.Context, req *pb.Dele
 }
 
 func (s *CryptoAlertServiceServer) SubscribeAlerts(req *pb.AlertSubscriptionRequest, stream pb.CryptoAlertService_SubscribeAlertsServer) error {
+	identity, err := callerIdentity(stream.Context())
+	if err != nil {
+		return err
+	}
+
 	subscriberID := generateSubscriberID()
 	
-	log.Printf("Client subscribing to alert triggers (subscriber: %s)", subscriberID)
+	log.Printf("Client subscribing to alert triggers (subscriber: %s, user: %s)", subscriberID, identity.UserID)
+
+	filter := alerts.NewTriggerFilter(req.Symbols, req.AlertIds, req.Labels,
+		convertSeverityFromProto(req.MinSeverity))
+	if !identity.IsAdmin() {
+		filter.OwnerID = identity.UserID
+	}
 
-	subscriber := s.triggerBus.Subscribe(subscriberID, 100)
+	// Subscribe before reading the trigger log so nothing published in
+	// between is missed; duplicates are skipped by sequence number.
+	subscriber := s.triggerBus.SubscribeWithFilter(subscriberID, 0, filter)
 	defer s.triggerBus.Unsubscribe(subscriberID)
 
+	cursor := s.triggerBus.LastSequence()
+	if req.ResumeFromSequence != nil && *req.ResumeFromSequence < cursor {
+		cursor = *req.ResumeFromSequence
+		log.Printf("Replaying triggers after sequence %d (subscriber: %s)", cursor, subscriberID)
+	}
+
+	send := func(trigger *models.AlertTrigger) error {
+		if err := stream.Send(convertAlertTriggerToProto(trigger)); err != nil {
+			log.Printf("Error sending alert trigger to client (subscriber: %s): %v", subscriberID, err)
+			return err
+		}
+		cursor = trigger.Sequence
+		return nil
+	}
+
+	catchUp := func() error {
+		for _, trigger := range s.triggerBus.TriggersSince(cursor) {
+			if !filter.Matches(trigger) {
+				cursor = trigger.Sequence
+				continue
+			}
+			if err := send(trigger); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := catchUp(); err != nil {
+		return err
+	}
+
+	// Catch-up also runs periodically so that triggers dropped for a slow
+	// subscriber are delivered even if no later trigger reveals the gap.
+	ticker := time.NewTicker(catchUpInterval)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-stream.Context().Done():
 			log.Printf("Client disconnected from alert stream (subscriber: %s)", subscriberID)
 			return stream.Context().Err()
+		case <-ticker.C:
+			if s.triggerBus.LastSequence() > cursor {
+				if err := catchUp(); err != nil {
+					return err
+				}
+			}
 		case trigger, ok := <-subscriber.TriggerChan:
 			if !ok {
 				return nil
 			}
 
-			pbTrigger := convertAlertTriggerToProto(trigger)
-
-			if err := stream.Send(pbTrigger); err != nil {
-				log.Printf("Error sending alert trigger to client (subscriber: %s): %v", subscriberID, err)
-				return err
+			switch {
+			case trigger.Sequence <= cursor:
+				continue
+			case trigger.Sequence == cursor+1:
+				if err := send(trigger); err != nil {
+					return err
+				}
+			default:
+				if err := catchUp(); err != nil {
+					return err
+				}
 			}
 		}
 	}
 }
 
+func (s *CryptoAlertServiceServer) ListTriggers(ctx context.Context, req *pb.ListTriggersRequest) (*pb.ListTriggersResponse, error) {
+	identity, err := callerIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.history == nil {
+		return nil, status.Error(codes.Unavailable, "trigger history is not enabled")
+	}
+
+	query := triggerhistory.Query{
+		AlertID: req.AlertId,
+		Symbol:  req.Symbol,
+		OwnerID: req.OwnerId,
+		Limit:   int(req.PageSize),
+	}
+
+	if !identity.IsAdmin() {
+		if req.OwnerId != "" && req.OwnerId != identity.UserID {
+			return nil, status.Error(codes.PermissionDenied, "only admins can list other users' triggers")
+		}
+		query.OwnerID = identity.UserID
+	}
+
+	if req.PageSize > triggerhistory.MaxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be at most %d", triggerhistory.MaxPageSize)
+	}
+
+	if req.From != nil {
+		query.From = req.From.AsTime()
+	}
+	if req.To != nil {
+		query.To = req.To.AsTime()
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		return nil, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+
+	if req.PageToken != "" {
+		cursor, err := strconv.ParseUint(req.PageToken, 10, 64)
+		if err != nil || cursor == 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page token")
+		}
+		query.Cursor = cursor
+	}
+
+	records, next := s.history.List(query)
+
+	resp := &pb.ListTriggersResponse{
+		Triggers: make([]*pb.TriggerRecord, 0, len(records)),
+	}
+	for _, record := range records {
+		resp.Triggers = append(resp.Triggers, &pb.TriggerRecord{
+			Trigger:   convertAlertTriggerToProto(record.Trigger),
+			Condition: record.Condition,
+		})
+	}
+	if next != 0 {
+		resp.NextPageToken = strconv.FormatUint(next, 10)
+	}
+
+	return resp, nil
+}
+
+func callerIdentity(ctx context.Context) (auth.Identity, error) {
+	identity, ok := auth.FromContext(ctx)
+	if !ok {
+		return auth.Identity{}, status.Error(codes.Unauthenticated, "caller identity is required")
+	}
+	return identity, nil
+}
+
+// authorizedAlert returns the alert if the caller owns it or is an admin.
+// Other tenants' alerts are reported as not found so IDs don't leak.
+func (s *CryptoAlertServiceServer) authorizedAlert(ctx context.Context, id string) (*models.Alert, error) {
+	identity, err := callerIdentity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	alert, err := s.store.Get(id)
+	if err != nil {
+		if err == alerts.ErrAlertNotFound {
+			return nil, status.Error(codes.NotFound, "alert not found")
+		}
+		log.Printf("Error getting alert: %v", err)
+		return nil, status.Error(codes.Internal, "failed to get alert")
+	}
+
+	if alert.OwnerID != identity.UserID && !identity.IsAdmin() {
+		return nil, status.Error(codes.NotFound, "alert not found")
+	}
+
+	return alert, nil
+}
+
 func convertComparatorFromProto(pbComparator pb.Comparator) models.Comparator {
 	switch pbComparator {
 	case pb.Comparator_COMPARATOR_GT:
@@ -204,13 +674,135 @@ func convertComparatorToProto(comparator models.Comparator) pb.Comparator {
 	}
 }
 
+func normalizeLabels(labels []string) []string {
+	normalized := make([]string, 0, len(labels))
+	seen := make(map[string]bool, len(labels))
+
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" || seen[label] {
+			continue
+		}
+		seen[label] = true
+		normalized = append(normalized, label)
+	}
+
+	if len(normalized) == 0 {
+		return nil
+	}
+	return normalized
+}
+
+func convertSeverityFromProto(pbSeverity pb.Severity) models.Severity {
+	switch pbSeverity {
+	case pb.Severity_SEVERITY_WARNING:
+		return models.SeverityWarning
+	case pb.Severity_SEVERITY_CRITICAL:
+		return models.SeverityCritical
+	default:
+		return models.SeverityInfo
+	}
+}
+
+func convertSeverityToProto(severity models.Severity) pb.Severity {
+	switch severity {
+	case models.SeverityWarning:
+		return pb.Severity_SEVERITY_WARNING
+	case models.SeverityCritical:
+		return pb.Severity_SEVERITY_CRITICAL
+	default:
+		return pb.Severity_SEVERITY_INFO
+	}
+}
+
+func convertTriggerModeFromProto(pbMode pb.TriggerMode) models.TriggerMode {
+	switch pbMode {
+	case pb.TriggerMode_TRIGGER_MODE_CROSS_UP:
+		return models.TriggerModeCrossUp
+	case pb.TriggerMode_TRIGGER_MODE_CROSS_DOWN:
+		return models.TriggerModeCrossDown
+	case pb.TriggerMode_TRIGGER_MODE_CROSS_ANY:
+		return models.TriggerModeCrossAny
+	default:
+		return models.TriggerModeLevel
+	}
+}
+
+func convertTriggerModeToProto(mode models.TriggerMode) pb.TriggerMode {
+	switch mode {
+	case models.TriggerModeCrossUp:
+		return pb.TriggerMode_TRIGGER_MODE_CROSS_UP
+	case models.TriggerModeCrossDown:
+		return pb.TriggerMode_TRIGGER_MODE_CROSS_DOWN
+	case models.TriggerModeCrossAny:
+		return pb.TriggerMode_TRIGGER_MODE_CROSS_ANY
+	default:
+		return pb.TriggerMode_TRIGGER_MODE_LEVEL
+	}
+}
+
+func convertAlertKindToProto(kind models.AlertKind) pb.AlertKind {
+	switch kind {
+	case models.AlertKindThreshold:
+		return pb.AlertKind_ALERT_KIND_THRESHOLD
+	case models.AlertKindPercentMove:
+		return pb.AlertKind_ALERT_KIND_PERCENT_MOVE
+	case models.AlertKindFeedSilence:
+		return pb.AlertKind_ALERT_KIND_FEED_SILENCE
+	case models.AlertKindCompound:
+		return pb.AlertKind_ALERT_KIND_COMPOUND
+	case models.AlertKindExpression:
+		return pb.AlertKind_ALERT_KIND_EXPRESSION
+	case models.AlertKindMACrossover:
+		return pb.AlertKind_ALERT_KIND_MA_CROSSOVER
+	case models.AlertKindRSI:
+		return pb.AlertKind_ALERT_KIND_RSI
+	case models.AlertKindBollinger:
+		return pb.AlertKind_ALERT_KIND_BOLLINGER
+	default:
+		return pb.AlertKind_ALERT_KIND_UNSPECIFIED
+	}
+}
+
+func convertDirectionFromProto(pbDirection pb.MoveDirection) models.Direction {
+	switch pbDirection {
+	case pb.MoveDirection_MOVE_DIRECTION_UP:
+		return models.DirectionUp
+	case pb.MoveDirection_MOVE_DIRECTION_DOWN:
+		return models.DirectionDown
+	default:
+		return models.DirectionEither
+	}
+}
+
+func convertDirectionToProto(direction models.Direction) pb.MoveDirection {
+	switch direction {
+	case models.DirectionUp:
+		return pb.MoveDirection_MOVE_DIRECTION_UP
+	case models.DirectionDown:
+		return pb.MoveDirection_MOVE_DIRECTION_DOWN
+	default:
+		return pb.MoveDirection_MOVE_DIRECTION_EITHER
+	}
+}
+
 func convertAlertToProto(alert *models.Alert) *pb.Alert {
 	pbAlert := &pb.Alert{
 		Id:         alert.ID,
+		OwnerId:    alert.OwnerID,
 		Symbol:     alert.Symbol,
+		Source:     alert.Source,
+		Kind:       convertAlertKindToProto(alert.Kind),
 		Comparator: convertComparatorToProto(alert.Comparator),
 		Threshold:  alert.Threshold,
+		Mode:       convertTriggerModeToProto(alert.Mode),
 		Note:       alert.Note,
+		Labels:     alert.Labels,
+		Severity:   convertSeverityToProto(alert.Severity),
+		WebhookUrl: alert.WebhookURL,
+		FireOnce:   alert.FireOnce,
+		MaxFires:   int32(alert.MaxFires),
+		FireCount:  int32(alert.FireCount),
 		Enabled:    alert.Enabled,
 	}
 
@@ -218,13 +810,178 @@ func convertAlertToProto(alert *models.Alert) *pb.Alert {
 		pbAlert.LastTrigger = timestamppb.New(*alert.LastTrigger)
 	}
 
+	if alert.Cooldown > 0 {
+		pbAlert.Cooldown = durationpb.New(alert.Cooldown)
+	}
+
+	if alert.ExpiresAt != nil {
+		pbAlert.ExpiresAt = timestamppb.New(*alert.ExpiresAt)
+	}
+
+	switch alert.Kind {
+	case models.AlertKindPercentMove:
+		pbAlert.Direction = convertDirectionToProto(alert.Direction)
+		pbAlert.Window = durationpb.New(alert.Window)
+	case models.AlertKindFeedSilence:
+		pbAlert.Window = durationpb.New(alert.Window)
+	case models.AlertKindCompound:
+		pbAlert.Condition = convertConditionToProto(alert.Condition)
+	case models.AlertKindExpression:
+		pbAlert.Expression = alert.Expression
+	case models.AlertKindMACrossover, models.AlertKindRSI, models.AlertKindBollinger:
+		pbAlert.Direction = convertDirectionToProto(alert.Direction)
+		pbAlert.Indicator = convertIndicatorToProto(alert.Indicator)
+	}
+
 	return pbAlert
 }
 
+func convertIndicatorFromProto(pbIndicator *pb.Indicator) *models.IndicatorConfig {
+	indicator := &models.IndicatorConfig{
+		Average:    models.MovingAverageSMA,
+		Period:     int(pbIndicator.Period),
+		SlowPeriod: int(pbIndicator.SlowPeriod),
+		StdDevs:    pbIndicator.StdDevs,
+	}
+
+	if pbIndicator.Average == pb.MovingAverage_MOVING_AVERAGE_EMA {
+		indicator.Average = models.MovingAverageEMA
+	}
+	if indicator.StdDevs == 0 {
+		indicator.StdDevs = models.DefaultBollingerStdDevs
+	}
+	if pbIndicator.Interval != nil {
+		indicator.Interval = pbIndicator.Interval.AsDuration()
+	}
+
+	return indicator
+}
+
+func convertIndicatorToProto(indicator *models.IndicatorConfig) *pb.Indicator {
+	if indicator == nil {
+		return nil
+	}
+
+	pbIndicator := &pb.Indicator{
+		Average:    pb.MovingAverage_MOVING_AVERAGE_SMA,
+		Period:     uint32(indicator.Period),
+		SlowPeriod: uint32(indicator.SlowPeriod),
+		StdDevs:    indicator.StdDevs,
+	}
+
+	if indicator.Average == models.MovingAverageEMA {
+		pbIndicator.Average = pb.MovingAverage_MOVING_AVERAGE_EMA
+	}
+	if indicator.Interval > 0 {
+		pbIndicator.Interval = durationpb.New(indicator.Interval)
+	}
+
+	return pbIndicator
+}
+
+// maxConditionDepth bounds recursion over a condition tree sent by a client.
+const maxConditionDepth = 32
+
+// convertConditionFromProto converts and validates a condition tree.
+func convertConditionFromProto(pbCondition *pb.Condition) (*models.Condition, error) {
+	if pbCondition == nil {
+		return nil, fmt.Errorf("condition is required for compound alerts")
+	}
+
+	condition, err := convertConditionNodeFromProto(pbCondition, 0)
+	if err == nil {
+		err = condition.Validate()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition: %v", err)
+	}
+	return condition, nil
+}
+
+func convertConditionNodeFromProto(pbCondition *pb.Condition, depth int) (*models.Condition, error) {
+	if depth > maxConditionDepth {
+		return nil, fmt.Errorf("nested more than %d levels deep", maxConditionDepth)
+	}
+	if pbCondition == nil {
+		return nil, fmt.Errorf("empty condition")
+	}
+
+	condition := &models.Condition{
+		Symbol:     strings.ToUpper(strings.TrimSpace(pbCondition.Symbol)),
+		Comparator: convertComparatorFromProto(pbCondition.Comparator),
+		Threshold:  pbCondition.Threshold,
+	}
+
+	switch pbCondition.Op {
+	case pb.ConditionOp_CONDITION_OP_COMPARE:
+		condition.Op = models.ConditionCompare
+	case pb.ConditionOp_CONDITION_OP_AND:
+		condition.Op = models.ConditionAnd
+	case pb.ConditionOp_CONDITION_OP_OR:
+		condition.Op = models.ConditionOr
+	case pb.ConditionOp_CONDITION_OP_NOT:
+		condition.Op = models.ConditionNot
+	default:
+		return nil, fmt.Errorf("operator is required")
+	}
+
+	for _, pbChild := range pbCondition.Children {
+		child, err := convertConditionNodeFromProto(pbChild, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		condition.Children = append(condition.Children, child)
+	}
+
+	return condition, nil
+}
+
+func convertConditionToProto(condition *models.Condition) *pb.Condition {
+	if condition == nil {
+		return nil
+	}
+
+	pbCondition := &pb.Condition{
+		Symbol:     condition.Symbol,
+		Comparator: convertComparatorToProto(condition.Comparator),
+		Threshold:  condition.Threshold,
+	}
+
+	switch condition.Op {
+	case models.ConditionCompare:
+		pbCondition.Op = pb.ConditionOp_CONDITION_OP_COMPARE
+	case models.ConditionAnd:
+		pbCondition.Op = pb.ConditionOp_CONDITION_OP_AND
+	case models.ConditionOr:
+		pbCondition.Op = pb.ConditionOp_CONDITION_OP_OR
+	case models.ConditionNot:
+		pbCondition.Op = pb.ConditionOp_CONDITION_OP_NOT
+	}
+
+	for _, child := range condition.Children {
+		pbCondition.Children = append(pbCondition.Children, convertConditionToProto(child))
+	}
+
+	return pbCondition
+}
+
 func convertAlertTriggerToProto(trigger *models.AlertTrigger) *pb.AlertTrigger {
 	return &pb.AlertTrigger{
 		Alert:          convertAlertToProto(trigger.Alert),
 		TriggeredPrice: trigger.TriggeredPrice,
 		Timestamp:      timestamppb.New(trigger.Timestamp),
+		Sequence:       trigger.Sequence,
+		Event:          convertTriggerEventToProto(trigger.Event),
+	}
+}
+
+func convertTriggerEventToProto(event models.TriggerEvent) pb.TriggerEvent {
+	switch event {
+	case models.TriggerEventFeedStale:
+		return pb.TriggerEvent_TRIGGER_EVENT_FEED_STALE
+	case models.TriggerEventFeedRecovered:
+		return pb.TriggerEvent_TRIGGER_EVENT_FEED_RECOVERED
+	default:
+		return pb.TriggerEvent_TRIGGER_EVENT_ALERT
 	}
 }
@@ -0,0 +1,466 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	pb "crypto-price-alerts/api/gen/crypto-price-alerts/api/gen"
+	"crypto-price-alerts/internal/alerts"
+	"crypto-price-alerts/internal/auth"
+	"crypto-price-alerts/internal/triggerhistory"
+	"crypto-price-alerts/pkg/clock"
+	"crypto-price-alerts/pkg/models"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func asUser(userID string) context.Context {
+	return auth.WithIdentity(context.Background(), auth.Identity{UserID: userID, Role: auth.RoleUser})
+}
+
+func asAdmin() context.Context {
+	return auth.WithIdentity(context.Background(), auth.Identity{UserID: "root", Role: auth.RoleAdmin})
+}
+
+func createTestAlert(t *testing.T, s *CryptoAlertServiceServer, ctx context.Context, symbol string) *pb.Alert {
+	t.Helper()
+
+	resp, err := s.CreateAlert(ctx, &pb.CreateAlertRequest{
+		Symbol:     symbol,
+		Comparator: pb.Comparator_COMPARATOR_GT,
+		Threshold:  100,
+	})
+	if err != nil {
+		t.Fatalf("CreateAlert() error = %v", err)
+	}
+	return resp.Alert
+}
+
+func TestAlertService_TenantIsolation(t *testing.T) {
+	s := NewCryptoAlertServiceServer(alerts.NewStore(), alerts.NewTriggerBus(), nil)
+
+	aliceAlert := createTestAlert(t, s, asUser("alice"), "BTC")
+	bobAlert := createTestAlert(t, s, asUser("bob"), "ETH")
+
+	if aliceAlert.OwnerId != "alice" {
+		t.Errorf("Expected owner alice, got %q", aliceAlert.OwnerId)
+	}
+
+	listTests := []struct {
+		name     string
+		ctx      context.Context
+		ownerID  string
+		expected int
+		code     codes.Code
+	}{
+		{"user sees own alerts", asUser("alice"), "", 1, codes.OK},
+		{"user cannot list other tenant", asUser("alice"), "bob", 0, codes.PermissionDenied},
+		{"admin sees all tenants", asAdmin(), "", 2, codes.OK},
+		{"admin lists one tenant", asAdmin(), "bob", 1, codes.OK},
+		{"anonymous caller rejected", context.Background(), "", 0, codes.Unauthenticated},
+	}
+
+	for _, tt := range listTests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.GetAlerts(tt.ctx, &pb.GetAlertsRequest{OwnerId: tt.ownerID})
+			if status.Code(err) != tt.code {
+				t.Fatalf("GetAlerts() code = %v, expected %v", status.Code(err), tt.code)
+			}
+			if err == nil && len(resp.Alerts) != tt.expected {
+				t.Errorf("GetAlerts() returned %d alert(s), expected %d", len(resp.Alerts), tt.expected)
+			}
+		})
+	}
+
+	if _, err := s.DeleteAlert(asUser("alice"), &pb.DeleteAlertRequest{Id: bobAlert.Id}); status.Code(err) != codes.NotFound {
+		t.Errorf("Deleting another tenant's alert: code = %v, expected NotFound", status.Code(err))
+	}
+
+	note := "hijacked"
+	if _, err := s.UpdateAlert(asUser("alice"), &pb.UpdateAlertRequest{Id: bobAlert.Id, Note: &note}); status.Code(err) != codes.NotFound {
+		t.Errorf("Updating another tenant's alert: code = %v, expected NotFound", status.Code(err))
+	}
+
+	if _, err := s.DeleteAlert(asAdmin(), &pb.DeleteAlertRequest{Id: bobAlert.Id}); err != nil {
+		t.Errorf("Admin DeleteAlert() error = %v", err)
+	}
+}
+
+func TestAlertService_CreateForOtherOwner(t *testing.T) {
+	s := NewCryptoAlertServiceServer(alerts.NewStore(), alerts.NewTriggerBus(), nil)
+	req := &pb.CreateAlertRequest{Symbol: "BTC", Comparator: pb.Comparator_COMPARATOR_GT, Threshold: 100, OwnerId: "bob"}
+
+	if _, err := s.CreateAlert(asUser("alice"), req); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Expected PermissionDenied for user, got %v", status.Code(err))
+	}
+
+	resp, err := s.CreateAlert(asAdmin(), req)
+	if err != nil {
+		t.Fatalf("Admin CreateAlert() error = %v", err)
+	}
+	if resp.Alert.OwnerId != "bob" {
+		t.Errorf("Expected owner bob, got %q", resp.Alert.OwnerId)
+	}
+}
+
+func TestAlertService_CompoundAlert(t *testing.T) {
+	s := NewCryptoAlertServiceServer(alerts.NewStore(), alerts.NewTriggerBus(), nil)
+	ctx := asUser("alice")
+
+	compare := func(symbol string, comparator pb.Comparator, threshold float64) *pb.Condition {
+		return &pb.Condition{Op: pb.ConditionOp_CONDITION_OP_COMPARE, Symbol: symbol, Comparator: comparator, Threshold: threshold}
+	}
+	condition := &pb.Condition{Op: pb.ConditionOp_CONDITION_OP_AND, Children: []*pb.Condition{
+		compare("btc", pb.Comparator_COMPARATOR_GT, 100000),
+		{Op: pb.ConditionOp_CONDITION_OP_NOT, Children: []*pb.Condition{compare("ETH", pb.Comparator_COMPARATOR_LT, 3000)}},
+	}}
+
+	resp, err := s.CreateAlert(ctx, &pb.CreateAlertRequest{Kind: pb.AlertKind_ALERT_KIND_COMPOUND, Condition: condition})
+	if err != nil {
+		t.Fatalf("CreateAlert() error = %v", err)
+	}
+	if resp.Alert.Symbol != "BTC" || len(resp.Alert.Condition.GetChildren()) != 2 {
+		t.Errorf("Unexpected compound alert: %v", resp.Alert)
+	}
+
+	invalid := []*pb.Condition{
+		nil,
+		{Op: pb.ConditionOp_CONDITION_OP_AND, Children: []*pb.Condition{compare("BTC", pb.Comparator_COMPARATOR_GT, 1)}},
+		{Op: pb.ConditionOp_CONDITION_OP_NOT},
+		compare("BTC", pb.Comparator_COMPARATOR_UNSPECIFIED, 1),
+		compare("", pb.Comparator_COMPARATOR_GT, 1),
+		{Children: []*pb.Condition{compare("BTC", pb.Comparator_COMPARATOR_GT, 1)}},
+	}
+	for _, c := range invalid {
+		_, err := s.CreateAlert(ctx, &pb.CreateAlertRequest{Kind: pb.AlertKind_ALERT_KIND_COMPOUND, Condition: c})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("CreateAlert(%v): expected InvalidArgument, got %v", c, err)
+		}
+	}
+
+	update := &pb.UpdateAlertRequest{Id: resp.Alert.Id, Condition: compare("SOL", pb.Comparator_COMPARATOR_GTE, 150)}
+	updated, err := s.UpdateAlert(ctx, update)
+	if err != nil {
+		t.Fatalf("UpdateAlert() error = %v", err)
+	}
+	if updated.Alert.Symbol != "SOL" {
+		t.Errorf("Expected symbol to follow the condition, got %q", updated.Alert.Symbol)
+	}
+
+	threshold := 5.0
+	if _, err := s.UpdateAlert(ctx, &pb.UpdateAlertRequest{Id: resp.Alert.Id, Threshold: &threshold}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a threshold on a compound alert, got %v", err)
+	}
+}
+
+func TestAlertService_ExpressionAlert(t *testing.T) {
+	s := NewCryptoAlertServiceServer(alerts.NewStore(), alerts.NewTriggerBus(), nil)
+	ctx := asUser("alice")
+
+	resp, err := s.CreateAlert(ctx, &pb.CreateAlertRequest{
+		Kind:       pb.AlertKind_ALERT_KIND_EXPRESSION,
+		Expression: "pct_change(BTC, 1h) > 5 && price(ETH) < sma(ETH, 50)",
+	})
+	if err != nil {
+		t.Fatalf("CreateAlert() error = %v", err)
+	}
+	if resp.Alert.Symbol != "BTC" || resp.Alert.Expression == "" {
+		t.Errorf("Unexpected expression alert: %v", resp.Alert)
+	}
+
+	_, err = s.CreateAlert(ctx, &pb.CreateAlertRequest{Kind: pb.AlertKind_ALERT_KIND_EXPRESSION, Expression: "price(BTC) > 1 AND price(ETH) < 2"})
+	if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), "col 16: unexpected \"AND\"; use &&") {
+		t.Errorf("Expected a positioned InvalidArgument error, got %v", err)
+	}
+
+	expression := "price(SOL) > 150"
+	updated, err := s.UpdateAlert(ctx, &pb.UpdateAlertRequest{Id: resp.Alert.Id, Expression: &expression})
+	if err != nil {
+		t.Fatalf("UpdateAlert() error = %v", err)
+	}
+	if updated.Alert.Symbol != "SOL" || updated.Alert.Expression != expression {
+		t.Errorf("Unexpected updated alert: %v", updated.Alert)
+	}
+}
+
+func TestAlertService_IndicatorAlert(t *testing.T) {
+	s := NewCryptoAlertServiceServer(alerts.NewStore(), alerts.NewTriggerBus(), nil)
+	ctx := asUser("alice")
+
+	resp, err := s.CreateAlert(ctx, &pb.CreateAlertRequest{
+		Kind:      pb.AlertKind_ALERT_KIND_RSI,
+		Symbol:    "BTC",
+		Threshold: 70,
+		Direction: pb.MoveDirection_MOVE_DIRECTION_UP,
+		Indicator: &pb.Indicator{Period: 14, Interval: durationpb.New(time.Hour)},
+	})
+	if err != nil {
+		t.Fatalf("CreateAlert() error = %v", err)
+	}
+	if resp.Alert.Indicator.GetPeriod() != 14 || resp.Alert.Indicator.Interval.AsDuration() != time.Hour {
+		t.Errorf("Unexpected RSI alert: %v", resp.Alert)
+	}
+
+	bollinger, err := s.CreateAlert(ctx, &pb.CreateAlertRequest{
+		Kind:      pb.AlertKind_ALERT_KIND_BOLLINGER,
+		Symbol:    "ETH",
+		Indicator: &pb.Indicator{Period: 20},
+	})
+	if err != nil {
+		t.Fatalf("CreateAlert() error = %v", err)
+	}
+	if bollinger.Alert.Indicator.GetStdDevs() != models.DefaultBollingerStdDevs {
+		t.Errorf("Expected the default band width, got %v", bollinger.Alert.Indicator.GetStdDevs())
+	}
+
+	invalid := []*pb.CreateAlertRequest{
+		{Kind: pb.AlertKind_ALERT_KIND_RSI, Symbol: "BTC", Threshold: 70},
+		{Kind: pb.AlertKind_ALERT_KIND_RSI, Symbol: "BTC", Threshold: 120, Indicator: &pb.Indicator{Period: 14}},
+		{Kind: pb.AlertKind_ALERT_KIND_MA_CROSSOVER, Symbol: "BTC", Indicator: &pb.Indicator{Period: 200, SlowPeriod: 50}},
+	}
+	for _, req := range invalid {
+		if _, err := s.CreateAlert(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("CreateAlert(%v): expected InvalidArgument, got %v", req, err)
+		}
+	}
+
+	level := 150.0
+	if _, err := s.UpdateAlert(ctx, &pb.UpdateAlertRequest{Id: resp.Alert.Id, Threshold: &level}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for an RSI level above 100, got %v", err)
+	}
+
+	updated, err := s.UpdateAlert(ctx, &pb.UpdateAlertRequest{Id: resp.Alert.Id, Indicator: &pb.Indicator{Period: 7}})
+	if err != nil {
+		t.Fatalf("UpdateAlert() error = %v", err)
+	}
+	if updated.Alert.Indicator.GetPeriod() != 7 || updated.Alert.Indicator.Interval != nil {
+		t.Errorf("Expected the indicator parameters to be replaced, got %v", updated.Alert.Indicator)
+	}
+}
+
+func TestAlertService_FiringLimits(t *testing.T) {
+	s := NewCryptoAlertServiceServer(alerts.NewStore(), alerts.NewTriggerBus(), nil)
+	ctx := asUser("alice")
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	resp, err := s.CreateAlert(ctx, &pb.CreateAlertRequest{
+		Symbol:     "BTC",
+		Comparator: pb.Comparator_COMPARATOR_GT,
+		Threshold:  100,
+		Cooldown:   durationpb.New(5 * time.Minute),
+		ExpiresAt:  timestamppb.New(expiresAt),
+		MaxFires:   3,
+	})
+	if err != nil {
+		t.Fatalf("CreateAlert() error = %v", err)
+	}
+	if resp.Alert.Cooldown.AsDuration() != 5*time.Minute || !resp.Alert.ExpiresAt.AsTime().Equal(expiresAt) || resp.Alert.MaxFires != 3 {
+		t.Errorf("Unexpected limits: %v", resp.Alert)
+	}
+
+	invalid := []*pb.CreateAlertRequest{
+		{Symbol: "BTC", Comparator: pb.Comparator_COMPARATOR_GT, Threshold: 100, Cooldown: durationpb.New(-time.Second)},
+		{Symbol: "BTC", Comparator: pb.Comparator_COMPARATOR_GT, Threshold: 100, ExpiresAt: timestamppb.New(time.Now().Add(-time.Minute))},
+		{Symbol: "BTC", Comparator: pb.Comparator_COMPARATOR_GT, Threshold: 100, MaxFires: -1},
+	}
+	for _, req := range invalid {
+		if _, err := s.CreateAlert(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("CreateAlert(%v): expected InvalidArgument, got %v", req, err)
+		}
+	}
+
+	fireOnce := true
+	updated, err := s.UpdateAlert(ctx, &pb.UpdateAlertRequest{
+		Id:        resp.Alert.Id,
+		Cooldown:  durationpb.New(0),
+		ExpiresAt: &timestamppb.Timestamp{},
+		FireOnce:  &fireOnce,
+	})
+	if err != nil {
+		t.Fatalf("UpdateAlert() error = %v", err)
+	}
+	if updated.Alert.Cooldown != nil || updated.Alert.ExpiresAt != nil || !updated.Alert.FireOnce || updated.Alert.MaxFires != 3 {
+		t.Errorf("Expected the cooldown and expiry to be cleared, got %v", updated.Alert)
+	}
+
+	// Expiry is checked against the service's clock, as the engine does.
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.SetClock(clock.NewFake(start))
+	for _, tt := range []struct {
+		expiresAt time.Time
+		code      codes.Code
+	}{
+		{start.Add(time.Hour), codes.OK},
+		{start.Add(-time.Hour), codes.InvalidArgument},
+	} {
+		_, err := s.CreateAlert(ctx, &pb.CreateAlertRequest{Symbol: "BTC", Comparator: pb.Comparator_COMPARATOR_GT, Threshold: 100, ExpiresAt: timestamppb.New(tt.expiresAt)})
+		if status.Code(err) != tt.code {
+			t.Errorf("CreateAlert(expires_at=%v) code = %v, expected %v", tt.expiresAt, status.Code(err), tt.code)
+		}
+	}
+}
+
+func TestAlertService_ListTriggers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := alerts.NewTriggerBus()
+	bus.Start(ctx)
+	defer bus.Stop()
+
+	history, err := triggerhistory.NewStore(bus, triggerhistory.DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	history.Start(ctx)
+
+	aliceAlert := models.NewAlert("BTC", models.ComparatorGT, 100, "")
+	aliceAlert.OwnerID = "alice"
+	bobAlert := models.NewAlert("ETH", models.ComparatorLT, 50, "")
+	bobAlert.OwnerID = "bob"
+
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		bus.Publish(models.NewAlertTrigger(aliceAlert, float64(101+i), now.Add(time.Duration(i)*time.Second)))
+	}
+	bus.Publish(models.NewAlertTrigger(bobAlert, 49, now))
+	history.Stop()
+
+	s := NewCryptoAlertServiceServer(alerts.NewStore(), bus, history)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		req      *pb.ListTriggersRequest
+		expected int
+		code     codes.Code
+	}{
+		{"user sees own triggers", asUser("alice"), &pb.ListTriggersRequest{}, 3, codes.OK},
+		{"user cannot see other tenant's alert", asUser("alice"), &pb.ListTriggersRequest{AlertId: bobAlert.ID}, 0, codes.OK},
+		{"user cannot list other tenant", asUser("alice"), &pb.ListTriggersRequest{OwnerId: "bob"}, 0, codes.PermissionDenied},
+		{"admin sees all", asAdmin(), &pb.ListTriggersRequest{}, 4, codes.OK},
+		{"admin filters by symbol", asAdmin(), &pb.ListTriggersRequest{Symbol: "ETH"}, 1, codes.OK},
+		{"time range", asUser("alice"), &pb.ListTriggersRequest{From: timestamppb.New(now.Add(time.Second))}, 2, codes.OK},
+		{"empty time range", asAdmin(), &pb.ListTriggersRequest{From: timestamppb.New(now), To: timestamppb.New(now)}, 0, codes.InvalidArgument},
+		{"page too large", asAdmin(), &pb.ListTriggersRequest{PageSize: triggerhistory.MaxPageSize + 1}, 0, codes.InvalidArgument},
+		{"invalid page token", asAdmin(), &pb.ListTriggersRequest{PageToken: "abc"}, 0, codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.ListTriggers(tt.ctx, tt.req)
+			if status.Code(err) != tt.code {
+				t.Fatalf("ListTriggers() code = %v, expected %v", status.Code(err), tt.code)
+			}
+			if err == nil && len(resp.Triggers) != tt.expected {
+				t.Errorf("Expected %d triggers, got %d", tt.expected, len(resp.Triggers))
+			}
+		})
+	}
+
+	resp, err := s.ListTriggers(asUser("alice"), &pb.ListTriggersRequest{PageSize: 2})
+	if err != nil || len(resp.Triggers) != 2 || resp.NextPageToken == "" {
+		t.Fatalf("Expected a first page of 2 with a page token, got %v, %v", resp, err)
+	}
+	if record := resp.Triggers[0]; record.Trigger.TriggeredPrice != 103 || record.Condition != aliceAlert.Rule() {
+		t.Errorf("Expected the newest trigger with its condition first, got %+v", record)
+	}
+
+	resp, err = s.ListTriggers(asUser("alice"), &pb.ListTriggersRequest{PageSize: 2, PageToken: resp.NextPageToken})
+	if err != nil || len(resp.Triggers) != 1 || resp.NextPageToken != "" {
+		t.Errorf("Expected a last page of 1 without a page token, got %v, %v", resp, err)
+	}
+
+	disabled := NewCryptoAlertServiceServer(alerts.NewStore(), bus, nil)
+	if _, err := disabled.ListTriggers(asAdmin(), &pb.ListTriggersRequest{}); status.Code(err) != codes.Unavailable {
+		t.Errorf("Expected Unavailable without a history, got %v", err)
+	}
+}
+
+func TestAlertService_WebhookHosts(t *testing.T) {
+	s := NewCryptoAlertServiceServer(alerts.NewStore(), alerts.NewTriggerBus(), nil)
+
+	create := func(url string) error {
+		_, err := s.CreateAlert(asUser("alice"), &pb.CreateAlertRequest{
+			Symbol:     "BTC",
+			Comparator: pb.Comparator_COMPARATOR_GT,
+			Threshold:  100,
+			WebhookUrl: url,
+		})
+		return err
+	}
+
+	if err := create("https://hooks.example.com/alerts"); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected per-alert webhooks to be disabled by default, got %v", err)
+	}
+
+	s.SetWebhookHosts([]string{"hooks.example.com"})
+
+	tests := []struct {
+		url  string
+		code codes.Code
+	}{
+		{"https://hooks.example.com/alerts", codes.OK},
+		{"http://169.254.169.254/latest/meta-data", codes.InvalidArgument},
+		{"http://localhost:9091/metrics", codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		if err := create(tt.url); status.Code(err) != tt.code {
+			t.Errorf("CreateAlert(%s) code = %v, expected %v", tt.url, status.Code(err), tt.code)
+		}
+	}
+}
+
+func TestAlertService_WebhookSecret(t *testing.T) {
+	store := alerts.NewStore()
+	s := NewCryptoAlertServiceServer(store, alerts.NewTriggerBus(), nil)
+	s.SetWebhookHosts([]string{"hooks.example.com"})
+	ctx := asUser("alice")
+
+	resp, err := s.CreateAlert(ctx, &pb.CreateAlertRequest{
+		Symbol:     "BTC",
+		Comparator: pb.Comparator_COMPARATOR_GT,
+		Threshold:  100,
+		WebhookUrl: "https://hooks.example.com/a",
+	})
+	if err != nil {
+		t.Fatalf("CreateAlert() error = %v", err)
+	}
+	id := resp.Alert.Id
+
+	secret := resp.WebhookSecret
+	if stored, _ := store.Get(id); len(secret) != 64 || stored.WebhookSecret != secret {
+		t.Fatalf("Expected a new secret to be returned and stored, got %q", secret)
+	}
+
+	update := func(url string) string {
+		t.Helper()
+		updated, err := s.UpdateAlert(ctx, &pb.UpdateAlertRequest{Id: id, WebhookUrl: &url})
+		if err != nil {
+			t.Fatalf("UpdateAlert(%q) error = %v", url, err)
+		}
+		return updated.WebhookSecret
+	}
+
+	if returned := update("https://hooks.example.com/b"); returned != "" {
+		t.Errorf("Expected a changed URL to keep its secret unshown, got %q", returned)
+	}
+	if stored, _ := store.Get(id); stored.WebhookSecret != secret {
+		t.Errorf("Expected the secret to be kept when the URL changes")
+	}
+
+	update("")
+	if stored, _ := store.Get(id); stored.WebhookSecret != "" {
+		t.Errorf("Expected removing the webhook to remove its secret")
+	}
+
+	if returned := update("https://hooks.example.com/c"); returned == "" || returned == secret {
+		t.Errorf("Expected a new secret for the new webhook, got %q", returned)
+	}
+}
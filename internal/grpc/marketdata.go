@@ -1,26 +1,52 @@
 package grpc
 
 import (
+	"context"
 	"log"
+	"time"
 
 	pb "crypto-price-alerts/api/gen/crypto-price-alerts/api/gen"
+	"crypto-price-alerts/internal/candles"
+	"crypto-price-alerts/internal/history"
 	"crypto-price-alerts/internal/pubsub"
 	"crypto-price-alerts/pkg/models"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const defaultCandleLimit = 100
+
 type CryptoMarketDataServer struct {
 	pb.UnimplementedCryptoMarketDataServer
-	broker *pubsub.Broker
+	broker        *pubsub.Broker
+	candles       *candles.Aggregator
+	history       *history.Store
+	defaultSource string
 }
 
-func NewCryptoMarketDataServer(broker *pubsub.Broker) *CryptoMarketDataServer {
+func NewCryptoMarketDataServer(broker *pubsub.Broker, candles *candles.Aggregator, history *history.Store) *CryptoMarketDataServer {
 	return &CryptoMarketDataServer{
-		broker: broker,
+		broker:  broker,
+		candles: candles,
+		history: history,
 	}
 }
 
+// SetDefaultSource sets which tick source subscriptions without an explicit
+// source receive. With the default of "" they receive every tick.
+func (s *CryptoMarketDataServer) SetDefaultSource(source string) {
+	s.defaultSource = source
+}
+
+func (s *CryptoMarketDataServer) wantsSource(requested, source string) bool {
+	if requested != "" {
+		return requested == source
+	}
+	return s.defaultSource == "" || s.defaultSource == source
+}
+
 func (s *CryptoMarketDataServer) SubscribePrices(req *pb.PriceSubscriptionRequest, stream pb.CryptoMarketData_SubscribePricesServer) error {
 	if len(req.Symbols) == 0 {
 		return stream.Context().Err()
@@ -30,7 +56,7 @@ func (s *CryptoMarketDataServer) SubscribePrices(req *pb.PriceSubscriptionReques
 	
 	log.Printf("Client subscribing to price updates for symbols: %v (subscriber: %s)", req.Symbols, subscriberID)
 
-	subscriber := s.broker.Subscribe(subscriberID, req.Symbols, 100)
+	subscriber := s.broker.Subscribe(subscriberID, req.Symbols, 0)
 	defer s.broker.Unsubscribe(subscriberID)
 
 	for {
@@ -43,12 +69,12 @@ func (s *CryptoMarketDataServer) SubscribePrices(req *pb.PriceSubscriptionReques
 				return nil
 			}
 
-			pbTick := &pb.PriceTick{
-				Symbol:    tick.Symbol,
-				Price:     tick.Price,
-				Timestamp: timestamppb.New(tick.Timestamp),
+			if !s.wantsSource(req.Source, tick.Source) {
+				continue
 			}
 
+			pbTick := convertTickToProto(tick)
+
 			if err := stream.Send(pbTick); err != nil {
 				log.Printf("Error sending price tick to client (subscriber: %s): %v", subscriberID, err)
 				return err
@@ -57,10 +83,204 @@ func (s *CryptoMarketDataServer) SubscribePrices(req *pb.PriceSubscriptionReques
 	}
 }
 
+func (s *CryptoMarketDataServer) SubscribeCandles(req *pb.CandleSubscriptionRequest, stream pb.CryptoMarketData_SubscribeCandlesServer) error {
+	if len(req.Symbols) == 0 {
+		return status.Error(codes.InvalidArgument, "at least one symbol is required")
+	}
+
+	interval, err := convertCandleIntervalFromProto(req.Interval)
+	if err != nil {
+		return err
+	}
+
+	subscriberID := generateSubscriberID()
+
+	log.Printf("Client subscribing to %v candles for symbols: %v (subscriber: %s)", interval, req.Symbols, subscriberID)
+
+	subscriber := s.candles.Subscribe(subscriberID, req.Symbols, interval, req.Source, 0)
+	defer s.candles.Unsubscribe(subscriberID)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			log.Printf("Client disconnected from candle stream (subscriber: %s)", subscriberID)
+			return stream.Context().Err()
+		case candle, ok := <-subscriber.CandleChan:
+			if !ok {
+				return nil
+			}
+
+			if err := stream.Send(convertCandleToProto(candle)); err != nil {
+				log.Printf("Error sending candle to client (subscriber: %s): %v", subscriberID, err)
+				return err
+			}
+		}
+	}
+}
+
+func (s *CryptoMarketDataServer) GetCandles(ctx context.Context, req *pb.GetCandlesRequest) (*pb.GetCandlesResponse, error) {
+	if req.Symbol == "" {
+		return nil, status.Error(codes.InvalidArgument, "symbol is required")
+	}
+
+	interval, err := convertCandleIntervalFromProto(req.Interval)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(req.Limit)
+	if limit == 0 {
+		limit = defaultCandleLimit
+	}
+
+	candles := s.candles.Candles(req.Symbol, req.Source, interval, limit, req.IncludePartial)
+
+	pbCandles := make([]*pb.Candle, 0, len(candles))
+	for i := range candles {
+		pbCandles = append(pbCandles, convertCandleToProto(&candles[i]))
+	}
+
+	return &pb.GetCandlesResponse{Candles: pbCandles}, nil
+}
+
+func (s *CryptoMarketDataServer) GetPriceHistory(ctx context.Context, req *pb.GetPriceHistoryRequest) (*pb.GetPriceHistoryResponse, error) {
+	if req.Symbol == "" {
+		return nil, status.Error(codes.InvalidArgument, "symbol is required")
+	}
+
+	// Default to the store's clock, which under replay follows the recorded
+	// ticks rather than the wall clock.
+	to := s.history.Clock().Now()
+	if req.To != nil {
+		to = req.To.AsTime()
+	}
+	from := to.Add(-time.Hour)
+	if req.From != nil {
+		from = req.From.AsTime()
+	}
+	if !from.Before(to) {
+		return nil, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+
+	var resolution time.Duration
+	switch req.Resolution {
+	case pb.HistoryResolution_HISTORY_RESOLUTION_UNSPECIFIED:
+		resolution = s.history.AutoResolution(from, to)
+	case pb.HistoryResolution_HISTORY_RESOLUTION_RAW:
+		resolution = 0
+	case pb.HistoryResolution_HISTORY_RESOLUTION_1M:
+		resolution = time.Minute
+	case pb.HistoryResolution_HISTORY_RESOLUTION_5M:
+		resolution = 5 * time.Minute
+	case pb.HistoryResolution_HISTORY_RESOLUTION_15M:
+		resolution = 15 * time.Minute
+	case pb.HistoryResolution_HISTORY_RESOLUTION_1H:
+		resolution = time.Hour
+	case pb.HistoryResolution_HISTORY_RESOLUTION_1D:
+		resolution = 24 * time.Hour
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown resolution %v", req.Resolution)
+	}
+
+	points, err := s.history.Query(req.Symbol, from, to, resolution)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp := &pb.GetPriceHistoryResponse{
+		Points:     make([]*pb.PricePoint, 0, len(points)),
+		Resolution: convertHistoryResolutionToProto(resolution),
+	}
+	for _, point := range points {
+		resp.Points = append(resp.Points, &pb.PricePoint{
+			Timestamp: timestamppb.New(point.Time),
+			Open:      point.Open,
+			High:      point.High,
+			Low:       point.Low,
+			Close:     point.Close,
+			Volume:    point.Volume,
+			TickCount: uint32(point.Ticks),
+		})
+	}
+
+	return resp, nil
+}
+
+func convertHistoryResolutionToProto(resolution time.Duration) pb.HistoryResolution {
+	switch resolution {
+	case 0:
+		return pb.HistoryResolution_HISTORY_RESOLUTION_RAW
+	case time.Minute:
+		return pb.HistoryResolution_HISTORY_RESOLUTION_1M
+	case 5 * time.Minute:
+		return pb.HistoryResolution_HISTORY_RESOLUTION_5M
+	case 15 * time.Minute:
+		return pb.HistoryResolution_HISTORY_RESOLUTION_15M
+	case time.Hour:
+		return pb.HistoryResolution_HISTORY_RESOLUTION_1H
+	case 24 * time.Hour:
+		return pb.HistoryResolution_HISTORY_RESOLUTION_1D
+	default:
+		return pb.HistoryResolution_HISTORY_RESOLUTION_UNSPECIFIED
+	}
+}
+
+func convertCandleIntervalFromProto(interval pb.CandleInterval) (time.Duration, error) {
+	switch interval {
+	case pb.CandleInterval_CANDLE_INTERVAL_UNSPECIFIED, pb.CandleInterval_CANDLE_INTERVAL_1M:
+		return time.Minute, nil
+	case pb.CandleInterval_CANDLE_INTERVAL_5M:
+		return 5 * time.Minute, nil
+	case pb.CandleInterval_CANDLE_INTERVAL_15M:
+		return 15 * time.Minute, nil
+	case pb.CandleInterval_CANDLE_INTERVAL_1H:
+		return time.Hour, nil
+	case pb.CandleInterval_CANDLE_INTERVAL_1D:
+		return 24 * time.Hour, nil
+	default:
+		return 0, status.Errorf(codes.InvalidArgument, "unknown candle interval %v", interval)
+	}
+}
+
+func convertCandleIntervalToProto(interval time.Duration) pb.CandleInterval {
+	switch interval {
+	case time.Minute:
+		return pb.CandleInterval_CANDLE_INTERVAL_1M
+	case 5 * time.Minute:
+		return pb.CandleInterval_CANDLE_INTERVAL_5M
+	case 15 * time.Minute:
+		return pb.CandleInterval_CANDLE_INTERVAL_15M
+	case time.Hour:
+		return pb.CandleInterval_CANDLE_INTERVAL_1H
+	case 24 * time.Hour:
+		return pb.CandleInterval_CANDLE_INTERVAL_1D
+	default:
+		return pb.CandleInterval_CANDLE_INTERVAL_UNSPECIFIED
+	}
+}
+
+func convertCandleToProto(candle *models.Candle) *pb.Candle {
+	return &pb.Candle{
+		Symbol:    candle.Symbol,
+		Source:    candle.Source,
+		Interval:  convertCandleIntervalToProto(candle.Interval),
+		OpenTime:  timestamppb.New(candle.Start),
+		CloseTime: timestamppb.New(candle.End()),
+		Open:      candle.Open,
+		High:      candle.High,
+		Low:       candle.Low,
+		Close:     candle.Close,
+		Volume:    candle.Volume,
+		TickCount: uint32(candle.Ticks),
+		Closed:    candle.Closed,
+	}
+}
+
 func convertTickToProto(tick *models.Tick) *pb.PriceTick {
 	return &pb.PriceTick{
 		Symbol:    tick.Symbol,
 		Price:     tick.Price,
 		Timestamp: timestamppb.New(tick.Timestamp),
+		Source:    tick.Source,
 	}
 }
@@ -0,0 +1,42 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "crypto-price-alerts/api/gen/crypto-price-alerts/api/gen"
+	"crypto-price-alerts/internal/history"
+	"crypto-price-alerts/pkg/clock"
+	"crypto-price-alerts/pkg/models"
+)
+
+func TestMarketData_PriceHistoryDefaultsToStoreClock(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cfg := history.DefaultConfig()
+	cfg.Clock = clock.NewFake(start.Add(30 * time.Minute))
+	store, err := history.NewStore(cfg)
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		store.Record(&models.Tick{Symbol: "BTC", Price: float64(100 + i), Volume: 1, Timestamp: start.Add(time.Duration(i) * 10 * time.Minute)})
+	}
+
+	s := NewCryptoMarketDataServer(nil, nil, store)
+	resp, err := s.GetPriceHistory(context.Background(), &pb.GetPriceHistoryRequest{
+		Symbol:     "BTC",
+		Resolution: pb.HistoryResolution_HISTORY_RESOLUTION_RAW,
+	})
+	if err != nil {
+		t.Fatalf("GetPriceHistory() error = %v", err)
+	}
+
+	// The last hour by the store's clock covers every tick, although the
+	// wall clock is long past them.
+	if len(resp.Points) != 3 {
+		t.Errorf("Expected 3 points in the last hour of recorded time, got %d", len(resp.Points))
+	}
+}
@@ -0,0 +1,160 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"crypto-price-alerts/pkg/clock"
+	"crypto-price-alerts/pkg/models"
+)
+
+// Check returns nil when its component is healthy.
+type Check func() error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker decides readiness from the registered component checks and from
+// how recently each symbol last ticked.
+type Checker struct {
+	mu         sync.RWMutex
+	checks     []namedCheck
+	lastTick   map[string]time.Time
+	maxTickAge time.Duration
+	clock      clock.Clock
+}
+
+func NewChecker(maxTickAge time.Duration) *Checker {
+	return &Checker{
+		lastTick:   make(map[string]time.Time),
+		maxTickAge: maxTickAge,
+		clock:      clock.Real,
+	}
+}
+
+func (c *Checker) AddCheck(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// ObserveTick records that tick's symbol is still receiving prices.
+func (c *Checker) ObserveTick(tick *models.Tick) {
+	now := c.clock.Now()
+
+	c.mu.Lock()
+	c.lastTick[tick.Symbol] = now
+	c.mu.Unlock()
+}
+
+// Report holds the outcome of every check: "ok" or the reason it failed.
+type Report struct {
+	Ready  bool              `json:"ready"`
+	Checks map[string]string `json:"checks"`
+}
+
+func (c *Checker) Report() Report {
+	c.mu.RLock()
+	checks := append([]namedCheck(nil), c.checks...)
+	c.mu.RUnlock()
+
+	report := Report{Ready: true, Checks: make(map[string]string)}
+	record := func(name string, err error) {
+		if err != nil {
+			report.Ready = false
+			report.Checks[name] = err.Error()
+			return
+		}
+		report.Checks[name] = "ok"
+	}
+
+	for _, check := range checks {
+		record(check.name, check.check())
+	}
+	record("ticks", c.checkTicks())
+
+	return report
+}
+
+// checkTicks fails until the first tick arrives and whenever a symbol that
+// has ticked goes quiet for longer than maxTickAge. Symbols that never tick,
+// such as ones the venue has delisted, do not hold readiness back.
+func (c *Checker) checkTicks() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.lastTick) == 0 {
+		return fmt.Errorf("no ticks received yet")
+	}
+
+	now := c.clock.Now()
+	var stale []string
+	for symbol, last := range c.lastTick {
+		if age := now.Sub(last); age > c.maxTickAge {
+			stale = append(stale, fmt.Sprintf("%s (%v)", symbol, age.Truncate(time.Second)))
+		}
+	}
+
+	if len(stale) > 0 {
+		sort.Strings(stale)
+		return fmt.Errorf("stale symbols: %s", strings.Join(stale, ", "))
+	}
+	return nil
+}
+
+// LivenessHandler serves /healthz: the process is up and serving HTTP.
+func (c *Checker) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok\n"))
+	})
+}
+
+// ReadinessHandler serves /readyz with the report as JSON, and status 503
+// when any check fails.
+func (c *Checker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := c.Report()
+
+		w.Header().Set("Content-Type", "application/json")
+		if !report.Ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	})
+}
+
+// SyncGRPC mirrors readiness into the grpc.health.v1 server for the overall
+// ("") service and the given service names until ctx is done.
+func (c *Checker) SyncGRPC(ctx context.Context, server *grpchealth.Server, interval time.Duration, services ...string) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		status := healthpb.HealthCheckResponse_NOT_SERVING
+		if c.Report().Ready {
+			status = healthpb.HealthCheckResponse_SERVING
+		}
+
+		server.SetServingStatus("", status)
+		for _, service := range services {
+			server.SetServingStatus(service, status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
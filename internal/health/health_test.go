@@ -0,0 +1,117 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"crypto-price-alerts/pkg/clock"
+	"crypto-price-alerts/pkg/models"
+)
+
+func TestChecker_Report(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		ticks     map[string]time.Duration // symbol -> offset from start
+		at        time.Duration
+		engineErr error
+		ready     bool
+		failing   string
+	}{
+		{"no ticks yet", nil, 0, nil, false, "ticks"},
+		{"fresh ticks", map[string]time.Duration{"BTC": 0, "ETH": 10 * time.Second}, 30 * time.Second, nil, true, ""},
+		{"one stale symbol", map[string]time.Duration{"BTC": 0, "ETH": 50 * time.Second}, 90 * time.Second, nil, false, "ticks"},
+		{"component down", map[string]time.Duration{"BTC": 0}, 0, errors.New("not running"), false, "engine"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := clock.NewFake(start)
+			checker := NewChecker(time.Minute)
+			checker.clock = fake
+			checker.AddCheck("engine", func() error { return tt.engineErr })
+
+			for symbol, offset := range tt.ticks {
+				fake.Set(start.Add(offset))
+				checker.ObserveTick(models.NewTick(symbol, 1, fake.Now()))
+			}
+			fake.Set(start.Add(tt.at))
+
+			report := checker.Report()
+			if report.Ready != tt.ready {
+				t.Errorf("Ready = %v, expected %v (%v)", report.Ready, tt.ready, report.Checks)
+			}
+			if tt.failing != "" && report.Checks[tt.failing] == "ok" {
+				t.Errorf("Expected check %q to fail: %v", tt.failing, report.Checks)
+			}
+		})
+	}
+}
+
+func TestChecker_ReportNamesStaleSymbols(t *testing.T) {
+	fake := clock.NewFake(time.Now())
+	checker := NewChecker(time.Minute)
+	checker.clock = fake
+	checker.ObserveTick(models.NewTick("BTC", 1, fake.Now()))
+	fake.Advance(2 * time.Minute)
+
+	if got := checker.Report().Checks["ticks"]; got != "stale symbols: BTC (2m0s)" {
+		t.Errorf("ticks check = %q", got)
+	}
+}
+
+func TestChecker_ReadinessHandler(t *testing.T) {
+	checker := NewChecker(time.Minute)
+
+	recorder := httptest.NewRecorder()
+	checker.ReadinessHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/readyz", nil))
+	if recorder.Code != http.StatusServiceUnavailable || !strings.Contains(recorder.Body.String(), `"ready":false`) {
+		t.Errorf("Not ready: got %d %s", recorder.Code, recorder.Body.String())
+	}
+
+	checker.ObserveTick(models.NewTick("BTC", 1, time.Now()))
+
+	recorder = httptest.NewRecorder()
+	checker.ReadinessHandler().ServeHTTP(recorder, httptest.NewRequest("GET", "/readyz", nil))
+	if recorder.Code != http.StatusOK {
+		t.Errorf("Ready: got %d %s", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestChecker_SyncGRPC(t *testing.T) {
+	checker := NewChecker(time.Minute)
+	server := grpchealth.NewServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go checker.SyncGRPC(ctx, server, 10*time.Millisecond, "cryptoalert.CryptoAlertService")
+
+	waitFor := func(service string, expected healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for {
+			resp, err := server.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+			if err == nil && resp.Status == expected {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("%q status = %v (err %v), expected %v", service, resp.GetStatus(), err, expected)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	waitFor("", healthpb.HealthCheckResponse_NOT_SERVING)
+	checker.ObserveTick(models.NewTick("BTC", 1, time.Now()))
+	waitFor("", healthpb.HealthCheckResponse_SERVING)
+	waitFor("cryptoalert.CryptoAlertService", healthpb.HealthCheckResponse_SERVING)
+}
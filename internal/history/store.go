@@ -0,0 +1,481 @@
+// Package history keeps recent prices per symbol: every tick for a short
+// while, and downsampled OHLC bars for longer.
+package history
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"crypto-price-alerts/pkg/clock"
+	"crypto-price-alerts/pkg/models"
+)
+
+// MaxPoints bounds the points one query may return.
+const MaxPoints = 10000
+
+// autoPoints is roughly how many points AutoResolution aims for.
+const autoPoints = 1000
+
+var ErrTooManyPoints = errors.New("too many points; use a coarser resolution or a shorter range")
+
+type Config struct {
+	// RawRetention is how long every tick is kept; zero keeps none.
+	RawRetention time.Duration
+	// MinuteRetention and HourRetention are how long 1m and 1h bars are
+	// kept; zero disables the tier.
+	MinuteRetention time.Duration
+	HourRetention   time.Duration
+	// CompactInterval is how often expired points are dropped and, with a
+	// Path, the bars are saved.
+	CompactInterval time.Duration
+	// Path, when set, is where the bars are saved so they survive restarts.
+	// Raw ticks are kept in memory only.
+	Path string
+	// Clock decides which points have expired; nil uses the system clock.
+	Clock clock.Clock
+}
+
+func DefaultConfig() Config {
+	return Config{
+		RawRetention:    24 * time.Hour,
+		MinuteRetention: 7 * 24 * time.Hour,
+		HourRetention:   365 * 24 * time.Hour,
+		CompactInterval: 5 * time.Minute,
+	}
+}
+
+// Point is one tick at the raw resolution, or an OHLC bar starting at Time.
+// Like a candle's, a bar's Volume is the last 24-hour volume reported in it.
+type Point struct {
+	Time   time.Time
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+	Ticks  int
+}
+
+func (p *Point) merge(q Point) {
+	p.High = max(p.High, q.High)
+	p.Low = min(p.Low, q.Low)
+	p.Close = q.Close
+	p.Volume = q.Volume
+	p.Ticks += q.Ticks
+}
+
+// rawPoint is a tick as kept at the raw resolution.
+type rawPoint struct {
+	unixNano int64
+	price    float64
+	volume   float64
+}
+
+func (r rawPoint) point() Point {
+	return Point{Time: time.Unix(0, r.unixNano).UTC(), Open: r.price, High: r.price, Low: r.price,
+		Close: r.price, Volume: r.volume, Ticks: 1}
+}
+
+type tier struct {
+	resolution time.Duration
+	retention  time.Duration
+}
+
+type series struct {
+	raw []rawPoint
+	// latest is the newest tick time seen; older ticks are late.
+	latest time.Time
+	// bars is indexed like Store.tiers; each slice is sorted by Time.
+	bars [][]Point
+}
+
+// Store records the ticks of one source per symbol.
+type Store struct {
+	cfg      Config
+	tiers    []tier
+	source   string
+	series   map[string]*series
+	running  bool
+	stopChan chan struct{}
+	mu       sync.RWMutex
+	wg       sync.WaitGroup
+	clock    clock.Clock
+}
+
+// NewStore creates a store, loading the bars saved at cfg.Path if any.
+func NewStore(cfg Config) (*Store, error) {
+	if cfg.CompactInterval <= 0 {
+		cfg.CompactInterval = DefaultConfig().CompactInterval
+	}
+
+	s := &Store{
+		cfg:      cfg,
+		series:   make(map[string]*series),
+		stopChan: make(chan struct{}),
+		clock:    clock.OrReal(cfg.Clock),
+	}
+	if cfg.MinuteRetention > 0 {
+		s.tiers = append(s.tiers, tier{resolution: time.Minute, retention: cfg.MinuteRetention})
+	}
+	if cfg.HourRetention > 0 {
+		s.tiers = append(s.tiers, tier{resolution: time.Hour, retention: cfg.HourRetention})
+	}
+
+	if cfg.Path != "" {
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// SetDefaultSource sets which tick source is recorded. With the default of
+// "" every tick is, which suits a single feed.
+func (s *Store) SetDefaultSource(source string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.source = source
+}
+
+// Clock is the clock the store ages its points by.
+func (s *Store) Clock() clock.Clock {
+	return s.clock
+}
+
+func (s *Store) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return nil
+	}
+	s.running = true
+
+	s.wg.Add(1)
+	go s.run(ctx)
+
+	log.Printf("Price history started (raw %v, 1m bars %v, 1h bars %v)",
+		s.cfg.RawRetention, s.cfg.MinuteRetention, s.cfg.HourRetention)
+	return nil
+}
+
+func (s *Store) Stop() {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return
+	}
+	s.running = false
+	close(s.stopChan)
+	s.mu.Unlock()
+
+	s.wg.Wait()
+
+	if err := s.save(); err != nil {
+		log.Printf("Failed to save price history: %v", err)
+	}
+}
+
+func (s *Store) run(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.cfg.CompactInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-ticker.C:
+			s.compact(s.clock.Now())
+			if err := s.save(); err != nil {
+				log.Printf("Failed to save price history: %v", err)
+			}
+		}
+	}
+}
+
+// Record stores a tick from the recorded source.
+func (s *Store) Record(tick *models.Tick) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.source != "" && tick.Source != s.source {
+		return
+	}
+
+	ser, exists := s.series[tick.Symbol]
+	if !exists {
+		ser = &series{bars: make([][]Point, len(s.tiers))}
+		s.series[tick.Symbol] = ser
+	}
+
+	if s.cfg.RawRetention > 0 {
+		raw := rawPoint{unixNano: tick.Timestamp.UnixNano(), price: tick.Price, volume: tick.Volume}
+		i := len(ser.raw)
+		if i > 0 && ser.raw[i-1].unixNano > raw.unixNano {
+			i = sort.Search(len(ser.raw), func(j int) bool { return ser.raw[j].unixNano > raw.unixNano })
+		}
+		ser.raw = append(ser.raw, rawPoint{})
+		copy(ser.raw[i+1:], ser.raw[i:])
+		ser.raw[i] = raw
+	}
+
+	late := tick.Timestamp.Before(ser.latest)
+	if !late {
+		ser.latest = tick.Timestamp
+	}
+
+	point := rawPoint{price: tick.Price, volume: tick.Volume}.point()
+	for i, t := range s.tiers {
+		point.Time = tick.Timestamp.Truncate(t.resolution).UTC()
+		ser.bars[i] = addBar(ser.bars[i], point, late)
+	}
+}
+
+// addBar merges p into the bar starting at p.Time, inserting one if needed.
+// A late point widens the bar's range but does not move its close or volume.
+func addBar(bars []Point, p Point, late bool) []Point {
+	n := len(bars)
+	i := n
+	if n > 0 && !bars[n-1].Time.Before(p.Time) {
+		i = sort.Search(n, func(j int) bool { return !bars[j].Time.Before(p.Time) })
+	}
+
+	if i < n && bars[i].Time.Equal(p.Time) {
+		close, volume := bars[i].Close, bars[i].Volume
+		bars[i].merge(p)
+		if late {
+			bars[i].Close, bars[i].Volume = close, volume
+		}
+		return bars
+	}
+	if i == n {
+		return append(bars, p)
+	}
+
+	bars = append(bars, Point{})
+	copy(bars[i+1:], bars[i:])
+	bars[i] = p
+	return bars
+}
+
+// compact drops points older than their retention.
+func (s *Store) compact(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rawCutoff := now.Add(-s.cfg.RawRetention).UnixNano()
+	for symbol, ser := range s.series {
+		if i := sort.Search(len(ser.raw), func(j int) bool { return ser.raw[j].unixNano >= rawCutoff }); i > 0 {
+			ser.raw = append([]rawPoint(nil), ser.raw[i:]...)
+		}
+
+		empty := len(ser.raw) == 0
+		for t, bars := range ser.bars {
+			cutoff := now.Add(-s.tiers[t].retention)
+			if i := sort.Search(len(bars), func(j int) bool { return !bars[j].Time.Before(cutoff) }); i > 0 {
+				ser.bars[t] = append([]Point(nil), bars[i:]...)
+			}
+			empty = empty && len(ser.bars[t]) == 0
+		}
+
+		if empty {
+			delete(s.series, symbol)
+		}
+	}
+}
+
+// AutoResolution picks the finest of the candle intervals that covers from
+// to with about a thousand points and that is kept back to from.
+func (s *Store) AutoResolution(from, to time.Time) time.Duration {
+	now := s.clock.Now()
+	for _, resolution := range models.CandleIntervals {
+		if to.Sub(from)/resolution > autoPoints {
+			continue
+		}
+		if _, retention := s.reader(resolution); !now.Add(-retention).After(from) {
+			return resolution
+		}
+	}
+	return models.CandleIntervals[len(models.CandleIntervals)-1]
+}
+
+// reader picks what a query at the resolution reads: the raw ticks (-1) or
+// a tier. It prefers the coarsest one that reaches back far enough and
+// otherwise the one that reaches back furthest.
+func (s *Store) reader(resolution time.Duration) (int, time.Duration) {
+	best, bestRetention := -1, s.cfg.RawRetention
+	for i, t := range s.tiers {
+		if resolution%t.resolution == 0 && t.retention >= bestRetention {
+			best, bestRetention = i, t.retention
+		}
+	}
+	return best, bestRetention
+}
+
+// Query returns the prices of symbol in [from, to), oldest first: every
+// tick with a zero resolution, or OHLC bars of the given length aligned to
+// it in UTC. Intervals without ticks have no bar.
+func (s *Store) Query(symbol string, from, to time.Time, resolution time.Duration) ([]Point, error) {
+	if resolution < 0 {
+		return nil, fmt.Errorf("resolution must not be negative")
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+	if resolution > 0 && to.Sub(from)/resolution > MaxPoints {
+		return nil, ErrTooManyPoints
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ser, exists := s.series[symbol]
+	if !exists {
+		return nil, nil
+	}
+
+	src := -1
+	if resolution > 0 {
+		src, _ = s.reader(resolution)
+	}
+
+	if src < 0 {
+		start := sort.Search(len(ser.raw), func(j int) bool { return ser.raw[j].unixNano >= from.UnixNano() })
+		end := sort.Search(len(ser.raw), func(j int) bool { return ser.raw[j].unixNano >= to.UnixNano() })
+		if resolution == 0 {
+			if end-start > MaxPoints {
+				return nil, ErrTooManyPoints
+			}
+			points := make([]Point, 0, end-start)
+			for _, raw := range ser.raw[start:end] {
+				points = append(points, raw.point())
+			}
+			return points, nil
+		}
+
+		var points []Point
+		for _, raw := range ser.raw[start:end] {
+			p := raw.point()
+			p.Time = p.Time.Truncate(resolution)
+			points = addBar(points, p, false)
+		}
+		return points, nil
+	}
+
+	bars := ser.bars[src]
+	start := sort.Search(len(bars), func(j int) bool { return !bars[j].Time.Before(from) })
+	end := sort.Search(len(bars), func(j int) bool { return !bars[j].Time.Before(to) })
+
+	var points []Point
+	for _, bar := range bars[start:end] {
+		bar.Time = bar.Time.Truncate(resolution)
+		points = addBar(points, bar, false)
+	}
+	return points, nil
+}
+
+// Stats reports how many symbols and points are held.
+func (s *Store) Stats() (symbols, rawPoints, bars int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, ser := range s.series {
+		rawPoints += len(ser.raw)
+		for _, tierBars := range ser.bars {
+			bars += len(tierBars)
+		}
+	}
+	return len(s.series), rawPoints, bars
+}
+
+// snapshot is the saved form of the bars, keyed by symbol and resolution.
+type snapshot struct {
+	Bars map[string]map[time.Duration][]Point
+}
+
+func (s *Store) save() error {
+	if s.cfg.Path == "" {
+		return nil
+	}
+
+	s.mu.RLock()
+	snap := snapshot{Bars: make(map[string]map[time.Duration][]Point)}
+	for symbol, ser := range s.series {
+		tiers := make(map[time.Duration][]Point)
+		for i, bars := range ser.bars {
+			if len(bars) > 0 {
+				tiers[s.tiers[i].resolution] = append([]Point(nil), bars...)
+			}
+		}
+		snap.Bars[symbol] = tiers
+	}
+	s.mu.RUnlock()
+
+	tmpPath := s.cfg.Path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", tmpPath, err)
+	}
+
+	zw := gzip.NewWriter(file)
+	if err := gob.NewEncoder(zw).Encode(snap); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to encode price history: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write price history: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write price history: %v", err)
+	}
+
+	return os.Rename(tmpPath, s.cfg.Path)
+}
+
+func (s *Store) load() error {
+	file, err := os.Open(s.cfg.Path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open price history: %v", err)
+	}
+	defer file.Close()
+
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to read price history %s: %v", s.cfg.Path, err)
+	}
+
+	var snap snapshot
+	if err := gob.NewDecoder(zr).Decode(&snap); err != nil {
+		return fmt.Errorf("failed to decode price history %s: %v", s.cfg.Path, err)
+	}
+
+	bars := 0
+	for symbol, tiers := range snap.Bars {
+		ser := &series{bars: make([][]Point, len(s.tiers))}
+		for i, t := range s.tiers {
+			ser.bars[i] = tiers[t.resolution]
+			bars += len(ser.bars[i])
+		}
+		s.series[symbol] = ser
+	}
+
+	log.Printf("Loaded %d price history bar(s) for %d symbol(s) from %s", bars, len(snap.Bars), s.cfg.Path)
+	return nil
+}
@@ -0,0 +1,156 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"crypto-price-alerts/pkg/clock"
+	"crypto-price-alerts/pkg/models"
+)
+
+var start = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestStore(t *testing.T, cfg Config) *Store {
+	t.Helper()
+
+	cfg.Clock = clock.NewFake(start.Add(2 * time.Hour))
+	s, err := NewStore(cfg)
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	return s
+}
+
+func record(s *Store, symbol string, price float64, offset time.Duration) {
+	s.Record(&models.Tick{Symbol: symbol, Price: price, Volume: price * 10, Timestamp: start.Add(offset)})
+}
+
+func TestStore_Query(t *testing.T) {
+	s := newTestStore(t, DefaultConfig())
+
+	record(s, "BTC", 100, 0)
+	record(s, "BTC", 120, 20*time.Second)
+	record(s, "BTC", 90, 40*time.Second)
+	record(s, "BTC", 110, 90*time.Second)
+	record(s, "BTC", 95, 10*time.Second) // late
+	record(s, "BTC", 130, 61*time.Minute)
+
+	to := start.Add(2 * time.Hour)
+
+	raw, err := s.Query("BTC", start, to, 0)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(raw) != 6 || raw[1].Close != 95 || raw[5].Close != 130 {
+		t.Errorf("Expected every tick in order, got %v", raw)
+	}
+
+	tests := []struct {
+		resolution time.Duration
+		expected   []Point
+	}{
+		{time.Minute, []Point{
+			{Time: start, Open: 100, High: 120, Low: 90, Close: 90, Volume: 900, Ticks: 4},
+			{Time: start.Add(time.Minute), Open: 110, High: 110, Low: 110, Close: 110, Volume: 1100, Ticks: 1},
+			{Time: start.Add(61 * time.Minute), Open: 130, High: 130, Low: 130, Close: 130, Volume: 1300, Ticks: 1},
+		}},
+		{time.Hour, []Point{
+			{Time: start, Open: 100, High: 120, Low: 90, Close: 110, Volume: 1100, Ticks: 5},
+			{Time: start.Add(time.Hour), Open: 130, High: 130, Low: 130, Close: 130, Volume: 1300, Ticks: 1},
+		}},
+	}
+
+	for _, tt := range tests {
+		points, err := s.Query("BTC", start, to, tt.resolution)
+		if err != nil {
+			t.Fatalf("Query(%v) error = %v", tt.resolution, err)
+		}
+		if len(points) != len(tt.expected) {
+			t.Errorf("Query(%v) = %v, expected %v", tt.resolution, points, tt.expected)
+			continue
+		}
+		for i := range points {
+			if points[i] != tt.expected[i] {
+				t.Errorf("Query(%v)[%d] = %+v, expected %+v", tt.resolution, i, points[i], tt.expected[i])
+			}
+		}
+	}
+
+	if points, _ := s.Query("BTC", start.Add(time.Minute), start.Add(2*time.Minute), 0); len(points) != 1 || points[0].Close != 110 {
+		t.Errorf("Expected the range to be half-open, got %v", points)
+	}
+	if _, err := s.Query("BTC", start, start.Add(365*24*time.Hour), time.Minute); err != ErrTooManyPoints {
+		t.Errorf("Expected ErrTooManyPoints, got %v", err)
+	}
+	if _, err := s.Query("BTC", to, start, time.Minute); err == nil {
+		t.Error("Expected an error for an empty range")
+	}
+}
+
+func TestStore_DownsamplesPastRawRetention(t *testing.T) {
+	s := newTestStore(t, Config{RawRetention: 30 * time.Minute, MinuteRetention: 90 * time.Minute, HourRetention: 24 * time.Hour})
+
+	record(s, "ETH", 10, 0)
+	record(s, "ETH", 12, 30*time.Second)
+	record(s, "ETH", 11, 100*time.Minute)
+	s.compact(s.clock.Now())
+
+	if symbols, rawPoints, bars := s.Stats(); symbols != 1 || rawPoints != 1 || bars != 3 {
+		t.Errorf("Stats() = %d symbols, %d raw points, %d bars after compaction", symbols, rawPoints, bars)
+	}
+
+	// The early ticks and minute bars have expired; the hour bars remain.
+	points, err := s.Query("ETH", start, s.clock.Now(), time.Hour)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(points) != 2 || points[0].High != 12 || points[0].Ticks != 2 || points[1].Close != 11 {
+		t.Errorf("Expected hour bars to outlive the ticks, got %v", points)
+	}
+
+	if resolution := s.AutoResolution(start, s.clock.Now()); resolution != time.Hour {
+		t.Errorf("AutoResolution() = %v, expected 1h for a range older than the minute bars", resolution)
+	}
+	if resolution := s.AutoResolution(s.clock.Now().Add(-20*time.Minute), s.clock.Now()); resolution != time.Minute {
+		t.Errorf("AutoResolution() = %v, expected 1m for a recent range", resolution)
+	}
+}
+
+func TestStore_RecordsDefaultSource(t *testing.T) {
+	s := newTestStore(t, DefaultConfig())
+	s.SetDefaultSource(models.SourceConsolidated)
+
+	s.Record(&models.Tick{Symbol: "BTC", Price: 1, Source: "binance", Timestamp: start})
+	s.Record(&models.Tick{Symbol: "BTC", Price: 2, Source: models.SourceConsolidated, Timestamp: start})
+
+	points, _ := s.Query("BTC", start, start.Add(time.Minute), 0)
+	if len(points) != 1 || points[0].Close != 2 {
+		t.Errorf("Expected only consolidated ticks, got %v", points)
+	}
+}
+
+func TestStore_SavesBars(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Path = filepath.Join(t.TempDir(), "history.gob.gz")
+
+	s := newTestStore(t, cfg)
+	record(s, "SOL", 150, 0)
+	record(s, "SOL", 155, time.Minute)
+	if err := s.save(); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+
+	reopened := newTestStore(t, cfg)
+	if _, rawPoints, bars := reopened.Stats(); rawPoints != 0 || bars != 3 {
+		t.Errorf("Expected only the bars to be reloaded, got %d raw points and %d bars", rawPoints, bars)
+	}
+
+	points, err := reopened.Query("SOL", start, start.Add(time.Hour), 5*time.Minute)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(points) != 1 || points[0].Open != 150 || points[0].Close != 155 || points[0].Ticks != 2 {
+		t.Errorf("Unexpected bars after reload: %v", points)
+	}
+}
@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "crypto_alerts"
+
+// Stages at which a tick can be dropped because a channel is full.
+const (
+	StageFeed       = "feed"
+	StageBroker     = "broker"
+	StageEngine     = "engine"
+	StageSubscriber = "subscriber"
+	StageRecorder   = "recorder"
+)
+
+// Registry holds every metric the server exposes. It is separate from the
+// Prometheus default registry so that tests and embedders start clean.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	TicksReceived = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ticks_received_total",
+		Help:      "Ticks received from the data feed.",
+	}, []string{"symbol", "source"})
+
+	TicksDropped = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ticks_dropped_total",
+		Help:      "Ticks dropped because a channel was full, by pipeline stage.",
+	}, []string{"stage"})
+
+	AlertsEvaluated = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "alerts_evaluated_total",
+		Help:      "Alert conditions evaluated against a tick.",
+	})
+
+	EvaluationDuration = factory.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "evaluation_duration_seconds",
+		Help:      "Time to evaluate every matching alert against one tick.",
+		Buckets:   prometheus.ExponentialBuckets(0.000001, 4, 12),
+	})
+
+	TriggersFired = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "triggers_fired_total",
+		Help:      "Alert triggers published, by symbol.",
+	}, []string{"symbol"})
+
+	TriggerDeliveriesDropped = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "trigger_deliveries_dropped_total",
+		Help:      "Trigger deliveries skipped because a subscriber was full; subscribers recover them from the trigger log.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	// Export every stage from the start so rate() works before the first drop.
+	for _, stage := range []string{StageFeed, StageBroker, StageEngine, StageSubscriber, StageRecorder} {
+		TicksDropped.WithLabelValues(stage)
+	}
+}
+
+// RegisterSubscribers exports count as the number of active subscribers of
+// the given kind, read on every scrape.
+func RegisterSubscribers(kind string, count func() int) {
+	factory.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Name:        "active_subscribers",
+		Help:        "Active subscribers, by kind.",
+		ConstLabels: prometheus.Labels{"kind": kind},
+	}, func() float64 { return float64(count()) })
+}
+
+// RegisterQueue exports the length and capacity of a stage's channel so
+// saturation is visible before anything is dropped.
+func RegisterQueue(stage string, length func() int, capacity int) {
+	labels := prometheus.Labels{"stage": stage}
+
+	factory.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Name:        "queue_length",
+		Help:        "Items waiting in a pipeline channel, by stage.",
+		ConstLabels: labels,
+	}, func() float64 { return float64(length()) })
+
+	factory.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Name:        "queue_capacity",
+		Help:        "Capacity of a pipeline channel, by stage.",
+		ConstLabels: labels,
+	}, func() float64 { return float64(capacity) })
+}
+
+// RegisterGauge exports a value computed elsewhere, read on every scrape.
+func RegisterGauge(name, help string, value func() float64) {
+	factory.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      name,
+		Help:      help,
+	}, value)
+}
+
+// RegisterCounter exports a counter maintained elsewhere, read on every
+// scrape.
+func RegisterCounter(name, help string, value func() uint64) {
+	factory.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      name,
+		Help:      help,
+	}, func() float64 { return float64(value()) })
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
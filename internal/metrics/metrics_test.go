@@ -0,0 +1,60 @@
+package metrics_test
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"crypto-price-alerts/internal/metrics"
+	"crypto-price-alerts/internal/pubsub"
+	"crypto-price-alerts/pkg/models"
+)
+
+func TestTicksDropped_Broker(t *testing.T) {
+	dropped := metrics.TicksDropped.WithLabelValues(metrics.StageBroker)
+	before := testutil.ToFloat64(dropped)
+
+	// The broker is not started, so only the first tick fits.
+	broker := pubsub.NewBrokerWithConfig(pubsub.BrokerConfig{TickBuffer: 1})
+	for i := 0; i < 3; i++ {
+		broker.Publish(models.NewTick("BTC", 50000, time.Now()))
+	}
+
+	if got := testutil.ToFloat64(dropped) - before; got != 2 {
+		t.Errorf("broker drops = %v, expected 2", got)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	metrics.RegisterQueue("test", func() int { return 3 }, 10)
+
+	recorder := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(recorder.Body)
+
+	for _, expected := range []string{
+		`crypto_alerts_ticks_dropped_total{stage="subscriber"} `,
+		`crypto_alerts_queue_length{stage="test"} 3`,
+		`crypto_alerts_queue_capacity{stage="test"} 10`,
+		`crypto_alerts_evaluation_duration_seconds_bucket`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("Expected /metrics to contain %q", expected)
+		}
+	}
+
+	problems, err := testutil.GatherAndLint(metrics.Registry)
+	if err != nil {
+		t.Fatalf("GatherAndLint() error = %v", err)
+	}
+	for _, problem := range problems {
+		if strings.HasPrefix(problem.Metric, "crypto_alerts_") {
+			t.Errorf("lint: %s: %s", problem.Metric, problem.Text)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package notify
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// errBlockedAddress is returned when an alert's webhook resolves to an
+// address the server must not reach on a tenant's behalf.
+var errBlockedAddress = errors.New("webhook address is not public")
+
+// sharedAddressSpace is the carrier-grade NAT range, which IsPrivate does not
+// cover.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// ValidateAlertURL checks a webhook URL set on an alert. Alerts are created
+// by tenants, so their URLs must be on one of the operator's allowed hosts:
+// either an exact host name or "*.example.com" for its subdomains. With no
+// allowed hosts, per-alert webhooks are disabled.
+func ValidateAlertURL(raw string, allowedHosts []string) error {
+	if err := ValidateURL(raw); err != nil {
+		return err
+	}
+	if len(allowedHosts) == 0 {
+		return fmt.Errorf("per-alert webhooks are not enabled on this server")
+	}
+
+	parsed, _ := url.Parse(raw)
+	host := strings.ToLower(parsed.Hostname())
+	for _, allowed := range allowedHosts {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if suffix, ok := strings.CutPrefix(allowed, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return nil
+			}
+		} else if host == allowed {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("webhook host %q is not allowed", host)
+}
+
+// NewAlertSecret returns a random secret to sign an alert's own webhook
+// deliveries with, so its owner can verify them.
+func NewAlertSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// newAlertClient returns the client used for alert webhooks. It ignores
+// proxy settings and refuses to connect to loopback, private, link-local and
+// other non-public addresses. The check runs on the address being dialed,
+// after DNS resolution, so a public name pointing at an internal address is
+// refused too.
+func newAlertClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil || !isPublicAddr(addr) {
+				return fmt.Errorf("%w: %s", errBlockedAddress, host)
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		// A redirect could point anywhere, including hosts that are not
+		// allowed.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() &&
+		!addr.IsPrivate() &&
+		!addr.IsLoopback() &&
+		!addr.IsLinkLocalUnicast() &&
+		!sharedAddressSpace.Contains(addr)
+}
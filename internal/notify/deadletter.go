@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const defaultDeadLetterSize = 1000
+
+// DeadLetter records a webhook delivery that failed after every attempt.
+type DeadLetter struct {
+	Endpoint  string          `json:"endpoint"`
+	Sequence  uint64          `json:"sequence"`
+	AlertID   string          `json:"alert_id"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"last_error"`
+	FailedAt  time.Time       `json:"failed_at"`
+}
+
+// DeadLetterLog keeps the most recent dead letters in memory and, when
+// opened with a path, appends every one to a JSON-lines file so failed
+// deliveries can be inspected and replayed by hand.
+type DeadLetterLog struct {
+	entries  []DeadLetter
+	capacity int
+	file     *os.File
+	mu       sync.Mutex
+}
+
+func NewDeadLetterLog(path string, capacity int) (*DeadLetterLog, error) {
+	if capacity <= 0 {
+		capacity = defaultDeadLetterSize
+	}
+
+	dl := &DeadLetterLog{capacity: capacity}
+
+	if path != "" {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open dead letter log: %v", err)
+		}
+		dl.file = file
+	}
+
+	return dl, nil
+}
+
+func (dl *DeadLetterLog) Record(letter DeadLetter) error {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+
+	if len(dl.entries) >= dl.capacity {
+		dl.entries = append(dl.entries[:0], dl.entries[1:]...)
+	}
+	dl.entries = append(dl.entries, letter)
+
+	if dl.file == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to encode dead letter: %v", err)
+	}
+	data = append(data, '\n')
+
+	if _, err := dl.file.Write(data); err != nil {
+		return fmt.Errorf("failed to write dead letter: %v", err)
+	}
+
+	return dl.file.Sync()
+}
+
+// List returns the retained dead letters, oldest first.
+func (dl *DeadLetterLog) List() []DeadLetter {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+
+	letters := make([]DeadLetter, len(dl.entries))
+	copy(letters, dl.entries)
+	return letters
+}
+
+func (dl *DeadLetterLog) Close() error {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+
+	if dl.file == nil {
+		return nil
+	}
+
+	err := dl.file.Close()
+	dl.file = nil
+	return err
+}
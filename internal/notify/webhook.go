@@ -0,0 +1,478 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"crypto-price-alerts/internal/alerts"
+	"crypto-price-alerts/pkg/models"
+)
+
+const (
+	SignatureHeader = "X-Alert-Signature"
+	TimestampHeader = "X-Alert-Timestamp"
+	SequenceHeader  = "X-Alert-Sequence"
+
+	catchUpInterval = time.Second
+)
+
+// Endpoint is a webhook target. Zero Timeout and empty Secret fall back to
+// the notifier's defaults.
+type Endpoint struct {
+	URL     string
+	Secret  string
+	Timeout time.Duration
+	// alert marks a URL set on an alert by its owner rather than by the
+	// operator. It is only reached through the guarded client and signed
+	// with the alert's own secret, never the global one.
+	alert bool
+}
+
+// ParseEndpoint parses "URL[;timeout=5s][;secret=...]".
+func ParseEndpoint(spec string) (Endpoint, error) {
+	parts := strings.Split(strings.TrimSpace(spec), ";")
+
+	endpoint := Endpoint{URL: strings.TrimSpace(parts[0])}
+	if err := ValidateURL(endpoint.URL); err != nil {
+		return Endpoint{}, err
+	}
+
+	for _, option := range parts[1:] {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		switch key {
+		case "timeout":
+			timeout, err := time.ParseDuration(value)
+			if err != nil || timeout <= 0 {
+				return Endpoint{}, fmt.Errorf("invalid webhook timeout %q", value)
+			}
+			endpoint.Timeout = timeout
+		case "secret":
+			endpoint.Secret = value
+		default:
+			return Endpoint{}, fmt.Errorf("unknown webhook option %q", key)
+		}
+	}
+
+	return endpoint, nil
+}
+
+func ValidateURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return fmt.Errorf("invalid webhook URL %q", raw)
+	}
+	return nil
+}
+
+type Config struct {
+	// Endpoints receive every trigger; alerts may add their own URL.
+	Endpoints []Endpoint
+	// AlertHosts are the hosts alerts may send their own webhooks to; see
+	// ValidateAlertURL. Empty disables per-alert webhooks.
+	AlertHosts  []string
+	Secret      string
+	Timeout     time.Duration
+	MaxAttempts int
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+	// Workers bounds the number of concurrent deliveries.
+	Workers int
+	// SubscriberBuffer is how many triggers may queue before the notifier
+	// falls back to the trigger log.
+	SubscriberBuffer int
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Timeout:     5 * time.Second,
+		MaxAttempts: 5,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+		Workers:     8,
+
+		SubscriberBuffer: 1000,
+	}
+}
+
+// Payload is the JSON body posted to webhook endpoints.
+type Payload struct {
+	Sequence    uint64    `json:"sequence"`
+	Event       string    `json:"event"`
+	AlertID     string    `json:"alert_id"`
+	OwnerID     string    `json:"owner_id,omitempty"`
+	Symbol      string    `json:"symbol"`
+	Source      string    `json:"source,omitempty"`
+	Rule        string    `json:"rule"`
+	Note        string    `json:"note,omitempty"`
+	Labels      []string  `json:"labels,omitempty"`
+	Severity    string    `json:"severity"`
+	Price       float64   `json:"price"`
+	TriggeredAt time.Time `json:"triggered_at"`
+}
+
+func NewPayload(trigger *models.AlertTrigger) Payload {
+	alert := trigger.Alert
+	return Payload{
+		Sequence:    trigger.Sequence,
+		Event:       trigger.Event.String(),
+		AlertID:     alert.ID,
+		OwnerID:     alert.OwnerID,
+		Symbol:      alert.Symbol,
+		Source:      alert.Source,
+		Rule:        alert.Rule(),
+		Note:        alert.Note,
+		Labels:      alert.Labels,
+		Severity:    alert.Severity.String(),
+		Price:       trigger.TriggeredPrice,
+		TriggeredAt: trigger.Timestamp,
+	}
+}
+
+// Sign returns the X-Alert-Signature value for a request: the hex HMAC-SHA256
+// of "<timestamp>.<body>" keyed with secret. Receivers should recompute it
+// and reject stale timestamps.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Notifier posts every trigger on the TriggerBus to the configured webhooks.
+// Failed deliveries are retried with backoff and end up in the dead letter
+// log once the attempts are exhausted.
+type Notifier struct {
+	cfg         Config
+	triggerBus  *alerts.TriggerBus
+	deadLetters *DeadLetterLog
+	client      *http.Client
+	alertClient *http.Client
+	workers     chan struct{}
+	cursor      uint64
+	running     bool
+	stopChan    chan struct{}
+	mu          sync.Mutex
+	wg          sync.WaitGroup
+
+	delivered    atomic.Uint64
+	retries      atomic.Uint64
+	deadLettered atomic.Uint64
+}
+
+func NewNotifier(triggerBus *alerts.TriggerBus, cfg Config, deadLetters *DeadLetterLog) *Notifier {
+	defaults := DefaultConfig()
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaults.Timeout
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaults.MaxAttempts
+	}
+	if cfg.MinBackoff <= 0 {
+		cfg.MinBackoff = defaults.MinBackoff
+	}
+	if cfg.MaxBackoff < cfg.MinBackoff {
+		cfg.MaxBackoff = cfg.MinBackoff
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaults.Workers
+	}
+	if cfg.SubscriberBuffer <= 0 {
+		cfg.SubscriberBuffer = defaults.SubscriberBuffer
+	}
+	if deadLetters == nil {
+		deadLetters, _ = NewDeadLetterLog("", 0)
+	}
+
+	return &Notifier{
+		cfg:         cfg,
+		triggerBus:  triggerBus,
+		deadLetters: deadLetters,
+		client:      &http.Client{},
+		alertClient: newAlertClient(),
+		workers:     make(chan struct{}, cfg.Workers),
+		stopChan:    make(chan struct{}),
+	}
+}
+
+func (n *Notifier) Start(ctx context.Context) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.running {
+		return nil
+	}
+
+	n.running = true
+	subscriber := n.triggerBus.Subscribe("webhook-notifier", n.cfg.SubscriberBuffer)
+	n.cursor = n.triggerBus.LastSequence()
+
+	n.wg.Add(1)
+	go n.run(ctx, subscriber)
+
+	log.Printf("Webhook notifier started with %d global endpoint(s)", len(n.cfg.Endpoints))
+
+	return nil
+}
+
+// Stop waits for in-flight requests; deliveries still waiting to retry are
+// dead-lettered.
+func (n *Notifier) Stop() {
+	n.mu.Lock()
+	if !n.running {
+		n.mu.Unlock()
+		return
+	}
+	n.running = false
+	close(n.stopChan)
+	n.mu.Unlock()
+
+	n.triggerBus.Unsubscribe("webhook-notifier")
+	n.wg.Wait()
+
+	log.Println("Webhook notifier stopped")
+}
+
+func (n *Notifier) DeadLetters() []DeadLetter {
+	return n.deadLetters.List()
+}
+
+func (n *Notifier) GetStats() NotifierStats {
+	return NotifierStats{
+		Delivered:    n.delivered.Load(),
+		Retries:      n.retries.Load(),
+		DeadLettered: n.deadLettered.Load(),
+	}
+}
+
+type NotifierStats struct {
+	Delivered    uint64 `json:"delivered"`
+	Retries      uint64 `json:"retries"`
+	DeadLettered uint64 `json:"dead_lettered"`
+}
+
+func (n *Notifier) run(ctx context.Context, subscriber *alerts.TriggerSubscriber) {
+	defer n.wg.Done()
+
+	// Like the gRPC alert stream, gaps in the sequence are filled from the
+	// trigger log so a burst that overflows the subscriber is not lost.
+	ticker := time.NewTicker(catchUpInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-n.stopChan:
+			return
+		case <-ticker.C:
+			if n.triggerBus.LastSequence() > n.cursor {
+				n.catchUp()
+			}
+		case trigger, ok := <-subscriber.TriggerChan:
+			if !ok {
+				return
+			}
+
+			switch {
+			case trigger.Sequence <= n.cursor:
+			case trigger.Sequence == n.cursor+1:
+				n.dispatch(trigger)
+			default:
+				n.catchUp()
+			}
+		}
+	}
+}
+
+func (n *Notifier) catchUp() {
+	for _, trigger := range n.triggerBus.TriggersSince(n.cursor) {
+		n.dispatch(trigger)
+	}
+}
+
+func (n *Notifier) dispatch(trigger *models.AlertTrigger) {
+	n.cursor = trigger.Sequence
+
+	endpoints := n.endpointsFor(trigger.Alert)
+	if len(endpoints) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(NewPayload(trigger))
+	if err != nil {
+		log.Printf("Error encoding webhook payload for alert %s: %v", trigger.Alert.ID, err)
+		return
+	}
+
+	// Waiting for a worker here, rather than in each delivery, keeps the
+	// number of goroutines bounded; the trigger log covers what queues up on
+	// the bus meanwhile.
+	for _, endpoint := range endpoints {
+		select {
+		case n.workers <- struct{}{}:
+		case <-n.stopChan:
+			n.deadLetter(endpoint, trigger, body, 0, fmt.Errorf("notifier stopped before delivery"))
+			continue
+		}
+
+		n.wg.Add(1)
+		go n.deliver(endpoint, trigger, body)
+	}
+}
+
+func (n *Notifier) endpointsFor(alert *models.Alert) []Endpoint {
+	endpoints := n.cfg.Endpoints
+	if alert.WebhookURL == "" {
+		return endpoints
+	}
+
+	for _, endpoint := range endpoints {
+		if endpoint.URL == alert.WebhookURL {
+			return endpoints
+		}
+	}
+
+	// The allowed hosts may have changed since the alert was created.
+	if err := ValidateAlertURL(alert.WebhookURL, n.cfg.AlertHosts); err != nil {
+		log.Printf("Skipping webhook for alert %s: %v", alert.ID, err)
+		return endpoints
+	}
+
+	return append(endpoints[:len(endpoints):len(endpoints)],
+		Endpoint{URL: alert.WebhookURL, Secret: alert.WebhookSecret, alert: true})
+}
+
+// deliver runs on a worker slot taken by dispatch and releases it when done.
+func (n *Notifier) deliver(endpoint Endpoint, trigger *models.AlertTrigger, body []byte) {
+	defer n.wg.Done()
+	defer func() { <-n.workers }()
+
+	var err error
+	for attempt := 1; attempt <= n.cfg.MaxAttempts; attempt++ {
+		var retry bool
+		retry, err = n.post(endpoint, trigger, body)
+		if err == nil {
+			n.delivered.Add(1)
+			return
+		}
+
+		if !retry || attempt == n.cfg.MaxAttempts {
+			n.deadLetter(endpoint, trigger, body, attempt, err)
+			return
+		}
+
+		n.retries.Add(1)
+		delay := n.backoff(attempt - 1)
+		log.Printf("Webhook delivery to %s failed: %v (retrying in %v)", endpoint.URL, err, delay)
+
+		select {
+		case <-time.After(delay):
+		case <-n.stopChan:
+			n.deadLetter(endpoint, trigger, body, attempt, err)
+			return
+		}
+	}
+}
+
+// post makes a single delivery attempt and reports whether a failure is
+// worth retrying.
+func (n *Notifier) post(endpoint Endpoint, trigger *models.AlertTrigger, body []byte) (bool, error) {
+	timeout := endpoint.Timeout
+	if timeout <= 0 {
+		timeout = n.cfg.Timeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SequenceHeader, strconv.FormatUint(trigger.Sequence, 10))
+
+	client := n.client
+	secret := endpoint.Secret
+	if endpoint.alert {
+		// Signing a tenant's URL with the global secret would hand them
+		// signatures the operator's own endpoints accept, so only the
+		// alert's own secret is used.
+		client = n.alertClient
+	} else if secret == "" {
+		secret = n.cfg.Secret
+	}
+	if secret != "" {
+		req.Header.Set(SignatureHeader, Sign(secret, timestamp, body))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return !errors.Is(err, errBlockedAddress), err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusRequestTimeout,
+		resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode >= 500:
+		return true, fmt.Errorf("endpoint returned %s", resp.Status)
+	default:
+		return false, fmt.Errorf("endpoint returned %s", resp.Status)
+	}
+}
+
+func (n *Notifier) deadLetter(endpoint Endpoint, trigger *models.AlertTrigger, body []byte, attempts int, err error) {
+	n.deadLettered.Add(1)
+	log.Printf("Webhook delivery to %s for alert %s failed after %d attempt(s): %v",
+		endpoint.URL, trigger.Alert.ID, attempts, err)
+
+	letter := DeadLetter{
+		Endpoint:  endpoint.URL,
+		Sequence:  trigger.Sequence,
+		AlertID:   trigger.Alert.ID,
+		Payload:   json.RawMessage(body),
+		Attempts:  attempts,
+		LastError: err.Error(),
+		FailedAt:  time.Now(),
+	}
+	if err := n.deadLetters.Record(letter); err != nil {
+		log.Printf("Error recording dead letter: %v", err)
+	}
+}
+
+// backoff returns an exponential delay with up to 50% jitter.
+func (n *Notifier) backoff(attempt int) time.Duration {
+	delay := n.cfg.MaxBackoff
+	if attempt < 30 {
+		if d := n.cfg.MinBackoff << uint(attempt); d > 0 && d < n.cfg.MaxBackoff {
+			delay = d
+		}
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
@@ -0,0 +1,337 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"crypto-price-alerts/internal/alerts"
+	"crypto-price-alerts/pkg/models"
+)
+
+type receiver struct {
+	server   *httptest.Server
+	requests atomic.Int32
+	received chan *http.Request
+	bodies   chan []byte
+}
+
+// newReceiver starts a webhook endpoint that answers each request with the
+// next status from statuses, repeating the last one.
+func newReceiver(t *testing.T, delay time.Duration, statuses ...int) *receiver {
+	r := &receiver{
+		received: make(chan *http.Request, 100),
+		bodies:   make(chan []byte, 100),
+	}
+
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		n := int(r.requests.Add(1))
+		body, _ := io.ReadAll(req.Body)
+
+		time.Sleep(delay)
+
+		status := statuses[len(statuses)-1]
+		if n <= len(statuses) {
+			status = statuses[n-1]
+		}
+		w.WriteHeader(status)
+
+		r.received <- req
+		r.bodies <- body
+	}))
+	t.Cleanup(r.server.Close)
+
+	return r
+}
+
+func testConfig(endpoints ...Endpoint) Config {
+	return Config{
+		Endpoints:   endpoints,
+		Timeout:     time.Second,
+		MaxAttempts: 3,
+		MinBackoff:  5 * time.Millisecond,
+		MaxBackoff:  20 * time.Millisecond,
+		Workers:     2,
+	}
+}
+
+func startNotifier(t *testing.T, cfg Config) (*Notifier, *alerts.TriggerBus) {
+	bus := alerts.NewTriggerBus()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	bus.Start(ctx)
+	t.Cleanup(bus.Stop)
+
+	notifier := NewNotifier(bus, cfg, nil)
+	notifier.Start(ctx)
+	t.Cleanup(notifier.Stop)
+
+	return notifier, bus
+}
+
+func waitFor(t *testing.T, timeout time.Duration, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("Timed out waiting for condition")
+}
+
+func TestNotifier_DeliversSignedPayload(t *testing.T) {
+	r := newReceiver(t, 0, http.StatusOK)
+	notifier, bus := startNotifier(t, testConfig(Endpoint{URL: r.server.URL, Secret: "s3cret"}))
+
+	alert := models.NewAlert("BTC", models.ComparatorGT, 100000, "breakout")
+	alert.Severity = models.SeverityCritical
+	bus.Publish(models.NewAlertTrigger(alert, 100500, time.Now()))
+
+	var req *http.Request
+	var body []byte
+	select {
+	case req = <-r.received:
+		body = <-r.bodies
+	case <-time.After(2 * time.Second):
+		t.Fatal("Webhook was not called")
+	}
+
+	expected := Sign("s3cret", req.Header.Get(TimestampHeader), body)
+	if got := req.Header.Get(SignatureHeader); got != expected {
+		t.Errorf("Signature = %q, expected %q", got, expected)
+	}
+	if got := req.Header.Get(SequenceHeader); got != "1" {
+		t.Errorf("Sequence header = %q, expected 1", got)
+	}
+
+	var payload Payload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("Invalid payload: %v", err)
+	}
+	if payload.AlertID != alert.ID || payload.Price != 100500 || payload.Severity != "critical" {
+		t.Errorf("Unexpected payload: %+v", payload)
+	}
+
+	waitFor(t, time.Second, func() bool { return notifier.GetStats().Delivered == 1 })
+}
+
+func TestNotifier_Retries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		delay        time.Duration
+		requests     int32
+		deadLettered bool
+	}{
+		{"succeeds after server errors", []int{500, 503, 200}, 0, 3, false},
+		{"rate limited then accepted", []int{429, 200}, 0, 2, false},
+		{"gives up after max attempts", []int{500}, 0, 3, true},
+		{"client error is not retried", []int{400}, 0, 1, true},
+		{"timeout is retried", []int{200}, 100 * time.Millisecond, 3, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newReceiver(t, tt.delay, tt.statuses...)
+			notifier, bus := startNotifier(t, testConfig(Endpoint{URL: r.server.URL, Timeout: 20 * time.Millisecond}))
+
+			bus.Publish(models.NewAlertTrigger(models.NewAlert("ETH", models.ComparatorLT, 3000, ""), 2900, time.Now()))
+
+			waitFor(t, 2*time.Second, func() bool {
+				stats := notifier.GetStats()
+				return stats.Delivered+stats.DeadLettered == 1
+			})
+
+			if got := r.requests.Load(); got != tt.requests {
+				t.Errorf("Expected %d request(s), got %d", tt.requests, got)
+			}
+
+			letters := notifier.DeadLetters()
+			if tt.deadLettered != (len(letters) == 1) {
+				t.Fatalf("Expected dead lettered = %v, got %d dead letter(s)", tt.deadLettered, len(letters))
+			}
+			if tt.deadLettered && (letters[0].Endpoint != r.server.URL || letters[0].Sequence != 1) {
+				t.Errorf("Unexpected dead letter: %+v", letters[0])
+			}
+		})
+	}
+}
+
+func TestNotifier_PerAlertWebhook(t *testing.T) {
+	global := newReceiver(t, 0, http.StatusOK)
+	perAlert := newReceiver(t, 0, http.StatusOK)
+	cfg := testConfig(Endpoint{URL: global.server.URL})
+	cfg.Secret = "s3cret"
+	cfg.AlertHosts = []string{"127.0.0.1"}
+	notifier, bus := startNotifier(t, cfg)
+	// The receivers listen on loopback, which the guarded client refuses.
+	notifier.alertClient = &http.Client{}
+
+	alert := models.NewAlert("SOL", models.ComparatorGT, 200, "")
+	alert.WebhookURL = perAlert.server.URL
+	alert.WebhookSecret = "alert-secret"
+	bus.Publish(models.NewAlertTrigger(alert, 210, time.Now()))
+	bus.Publish(models.NewAlertTrigger(models.NewAlert("SOL", models.ComparatorGT, 100, ""), 210, time.Now()))
+
+	waitFor(t, 2*time.Second, func() bool { return notifier.GetStats().Delivered == 3 })
+
+	if got := global.requests.Load(); got != 2 {
+		t.Errorf("Expected 2 global deliveries, got %d", got)
+	}
+	if got := perAlert.requests.Load(); got != 1 {
+		t.Errorf("Expected 1 per-alert delivery, got %d", got)
+	}
+	req, body := <-perAlert.received, <-perAlert.bodies
+	if req.Header.Get(SignatureHeader) != Sign("alert-secret", req.Header.Get(TimestampHeader), body) {
+		t.Errorf("Expected the per-alert webhook to be signed with the alert's secret, not the global one")
+	}
+}
+
+func TestNotifier_AlertWebhookGuards(t *testing.T) {
+	r := newReceiver(t, 0, http.StatusOK)
+	cfg := testConfig()
+	cfg.AlertHosts = []string{"127.0.0.1"}
+	notifier, bus := startNotifier(t, cfg)
+
+	alert := models.NewAlert("BTC", models.ComparatorGT, 100, "")
+	alert.WebhookURL = r.server.URL
+	bus.Publish(models.NewAlertTrigger(alert, 110, time.Now()))
+
+	waitFor(t, 2*time.Second, func() bool { return notifier.GetStats().DeadLettered == 1 })
+
+	if got := r.requests.Load(); got != 0 {
+		t.Errorf("Expected loopback to be refused, got %d request(s)", got)
+	}
+	if letters := notifier.DeadLetters(); letters[0].Attempts != 1 || !strings.Contains(letters[0].LastError, "not public") {
+		t.Errorf("Expected a single refused attempt, got %+v", letters[0])
+	}
+
+	// Without the host on the allowlist the URL is not used at all.
+	notifier.cfg.AlertHosts = nil
+	bus.Publish(models.NewAlertTrigger(alert, 110, time.Now()))
+	time.Sleep(50 * time.Millisecond)
+	if stats := notifier.GetStats(); stats.DeadLettered != 1 || stats.Delivered != 0 {
+		t.Errorf("Expected the alert webhook to be skipped, got %+v", stats)
+	}
+}
+
+func TestValidateAlertURL(t *testing.T) {
+	hosts := []string{"hooks.example.com", "*.tenant.io"}
+
+	tests := []struct {
+		url     string
+		hosts   []string
+		wantErr bool
+	}{
+		{"https://hooks.example.com/alerts", hosts, false},
+		{"https://HOOKS.example.com:8443/alerts", hosts, false},
+		{"https://a.tenant.io/hook", hosts, false},
+		{"https://tenant.io/hook", hosts, true},
+		{"https://evil.example.com/hook", hosts, true},
+		{"http://169.254.169.254/latest/meta-data", hosts, true},
+		{"https://hooks.example.com/alerts", nil, true},
+		{"ftp://hooks.example.com", hosts, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if err := ValidateAlertURL(tt.url, tt.hosts); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAlertURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr     string
+		expected bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"10.0.0.8", false},
+		{"172.16.5.4", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::1", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := isPublicAddr(netip.MustParseAddr(tt.addr)); got != tt.expected {
+				t.Errorf("isPublicAddr(%s) = %v, expected %v", tt.addr, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseEndpoint(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected Endpoint
+		wantErr  bool
+	}{
+		{"https://example.com/hook", Endpoint{URL: "https://example.com/hook"}, false},
+		{"https://example.com/hook;timeout=2s;secret=abc", Endpoint{URL: "https://example.com/hook", Secret: "abc", Timeout: 2 * time.Second}, false},
+		{"ftp://example.com", Endpoint{}, true},
+		{"https://example.com;timeout=soon", Endpoint{}, true},
+		{"https://example.com;retries=3", Endpoint{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			endpoint, err := ParseEndpoint(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseEndpoint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if endpoint != tt.expected {
+				t.Errorf("ParseEndpoint() = %+v, expected %+v", endpoint, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDeadLetterLog_WritesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deadletters.log")
+
+	dl, err := NewDeadLetterLog(path, 2)
+	if err != nil {
+		t.Fatalf("NewDeadLetterLog() error = %v", err)
+	}
+	for seq := uint64(1); seq <= 3; seq++ {
+		dl.Record(DeadLetter{Endpoint: "https://example.com", Sequence: seq, Payload: json.RawMessage(`{}`)})
+	}
+	dl.Close()
+
+	if letters := dl.List(); len(letters) != 2 || letters[0].Sequence != 2 {
+		t.Errorf("Expected the 2 most recent dead letters, got %+v", letters)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 3 {
+		t.Errorf("Expected 3 lines in dead letter file, got %d", lines)
+	}
+}
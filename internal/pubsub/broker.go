@@ -4,6 +4,7 @@ import (
 	"context"
 	"sync"
 
+	"crypto-price-alerts/internal/metrics"
 	"crypto-price-alerts/pkg/models"
 )
 
@@ -41,19 +42,50 @@ func (s *Subscriber) IsInterestedIn(symbol string) bool {
 	return s.Symbols[symbol]
 }
 
+type BrokerConfig struct {
+	// TickBuffer is how many published ticks may wait for fan-out before
+	// new ones are dropped.
+	TickBuffer int
+	// SubscriberBuffer is the per-subscriber buffer used when Subscribe is
+	// called with a non-positive size.
+	SubscriberBuffer int
+}
+
+func DefaultBrokerConfig() BrokerConfig {
+	return BrokerConfig{
+		TickBuffer:       10000,
+		SubscriberBuffer: 100,
+	}
+}
+
 type Broker struct {
 	subscribers map[string]*Subscriber
 	mu          sync.RWMutex
 	tickChan    chan *models.Tick
 	stopChan    chan struct{}
 	running     bool
+	stopped     bool
+	subBuffer   int
 }
 
 func NewBroker() *Broker {
+	return NewBrokerWithConfig(DefaultBrokerConfig())
+}
+
+func NewBrokerWithConfig(cfg BrokerConfig) *Broker {
+	defaults := DefaultBrokerConfig()
+	if cfg.TickBuffer <= 0 {
+		cfg.TickBuffer = defaults.TickBuffer
+	}
+	if cfg.SubscriberBuffer <= 0 {
+		cfg.SubscriberBuffer = defaults.SubscriberBuffer
+	}
+
 	return &Broker{
 		subscribers: make(map[string]*Subscriber),
-		tickChan:    make(chan *models.Tick, 10000),
+		tickChan:    make(chan *models.Tick, cfg.TickBuffer),
 		stopChan:    make(chan struct{}),
+		subBuffer:   cfg.SubscriberBuffer,
 	}
 }
 
@@ -79,11 +111,13 @@ func (b *Broker) Stop() {
 	}
 	
 	b.running = false
+	b.stopped = true
 	close(b.stopChan)
 	
 	for _, subscriber := range b.subscribers {
 		subscriber.Close()
 	}
+	b.subscribers = make(map[string]*Subscriber)
 	
 	close(b.tickChan)
 }
@@ -92,6 +126,10 @@ func (b *Broker) Subscribe(subscriberID string, symbols []string, bufferSize int
 	b.mu.Lock()
 	defer b.mu.Unlock()
 	
+	if bufferSize <= 0 {
+		bufferSize = b.subBuffer
+	}
+	
 	if existing, exists := b.subscribers[subscriberID]; exists {
 		existing.Close()
 	}
@@ -113,12 +151,30 @@ func (b *Broker) Unsubscribe(subscriberID string) {
 }
 
 func (b *Broker) Publish(tick *models.Tick) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.stopped {
+		return
+	}
+
 	select {
 	case b.tickChan <- tick:
 	default:
+		metrics.TicksDropped.WithLabelValues(metrics.StageBroker).Inc()
 	}
 }
 
+func (b *Broker) IsRunning() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.running
+}
+
+func (b *Broker) QueuedTicks() int {
+	return len(b.tickChan)
+}
+
 func (b *Broker) GetSubscriberCount() int {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
@@ -145,7 +201,10 @@ func (b *Broker) distributeTicks(ctx context.Context) {
 			return
 		case <-b.stopChan:
 			return
-		case tick := <-b.tickChan:
+		case tick, ok := <-b.tickChan:
+			if !ok {
+				return
+			}
 			b.fanOutTick(tick)
 		}
 	}
@@ -161,6 +220,7 @@ func (b *Broker) fanOutTick(tick *models.Tick) {
 			case subscriber.TickChan <- tick:
 			case <-subscriber.ctx.Done():
 			default:
+				metrics.TicksDropped.WithLabelValues(metrics.StageSubscriber).Inc()
 			}
 		}
 	}
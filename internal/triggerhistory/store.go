@@ -0,0 +1,406 @@
+// Package triggerhistory keeps every alert trigger, with the rule that fired,
+// so past triggers can be audited after the live stream has moved on.
+package triggerhistory
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"crypto-price-alerts/internal/alerts"
+	"crypto-price-alerts/pkg/clock"
+	"crypto-price-alerts/pkg/models"
+)
+
+const (
+	// MaxPageSize bounds the triggers one List call returns.
+	MaxPageSize     = 1000
+	defaultPageSize = 50
+	catchUpInterval = time.Second
+	subscriberID    = "trigger-history"
+)
+
+type Config struct {
+	// Retention is how long triggers are kept; zero keeps them until
+	// MaxRecords is reached.
+	Retention time.Duration
+	// MaxRecords bounds how many triggers are kept, dropping the oldest
+	// first; zero keeps every trigger within Retention.
+	MaxRecords int
+	// CompactInterval is how often expired triggers are dropped and, with a
+	// Path, the file is rewritten without them.
+	CompactInterval time.Duration
+	// Path, when set, is a JSON Lines file every trigger is appended to so
+	// the history survives restarts.
+	Path string
+	// SubscriberBuffer is how many triggers may queue before the history
+	// falls back to the trigger log to catch up.
+	SubscriberBuffer int
+	// Clock decides which triggers have expired; nil uses the system clock.
+	Clock clock.Clock
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Retention:        30 * 24 * time.Hour,
+		MaxRecords:       100000,
+		CompactInterval:  5 * time.Minute,
+		SubscriberBuffer: 1000,
+	}
+}
+
+// Record is one trigger as it happened.
+type Record struct {
+	Trigger *models.AlertTrigger `json:"trigger"`
+	// Condition is the alert's rule at the time it fired.
+	Condition string `json:"condition"`
+	// id numbers records in arrival order for paging; it is not persisted.
+	id uint64
+}
+
+// Query selects triggers. Empty fields match every trigger.
+type Query struct {
+	AlertID string
+	Symbol  string
+	// OwnerID restricts the result to one owner's alerts. System events
+	// belong to no owner and are always included.
+	OwnerID string
+	// From is inclusive and To exclusive; a zero time leaves that end open.
+	From time.Time
+	To   time.Time
+	// Cursor continues a previous List from its next cursor; zero starts
+	// from the newest trigger.
+	Cursor uint64
+	// Limit is the page size; zero uses the default and it is capped at
+	// MaxPageSize.
+	Limit int
+}
+
+// Store records the triggers published on a trigger bus. Like the webhook
+// notifier it follows the bus by sequence number and fills gaps from the
+// trigger log, so triggers dropped for a slow subscriber are still recorded.
+type Store struct {
+	cfg        Config
+	clock      clock.Clock
+	triggerBus *alerts.TriggerBus
+	records    []*Record
+	nextID     uint64
+	cursor     uint64
+	file       *os.File
+	lines      int
+	running    bool
+	stopChan   chan struct{}
+	wg         sync.WaitGroup
+	mu         sync.RWMutex
+}
+
+func NewStore(triggerBus *alerts.TriggerBus, cfg Config) (*Store, error) {
+	defaults := DefaultConfig()
+	if cfg.CompactInterval <= 0 {
+		cfg.CompactInterval = defaults.CompactInterval
+	}
+	if cfg.SubscriberBuffer <= 0 {
+		cfg.SubscriberBuffer = defaults.SubscriberBuffer
+	}
+
+	s := &Store{
+		cfg:        cfg,
+		clock:      clock.OrReal(cfg.Clock),
+		triggerBus: triggerBus,
+		nextID:     1,
+		stopChan:   make(chan struct{}),
+	}
+
+	if cfg.Path != "" {
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+
+		file, err := os.OpenFile(cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open trigger history: %v", err)
+		}
+		s.file = file
+	}
+
+	return s, nil
+}
+
+func (s *Store) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return nil
+	}
+	s.running = true
+
+	// Subscribe before catching up so nothing published in between is
+	// missed. A trigger log that restarted its numbering below the history
+	// is followed from where it is now.
+	subscriber := s.triggerBus.Subscribe(subscriberID, s.cfg.SubscriberBuffer)
+	if len(s.records) > 0 {
+		s.cursor = min(s.records[len(s.records)-1].Trigger.Sequence, s.triggerBus.LastSequence())
+	}
+
+	s.wg.Add(1)
+	go s.run(ctx, subscriber)
+
+	log.Printf("Trigger history started with %d trigger(s)", len(s.records))
+	return nil
+}
+
+// Stop records the triggers published up to now and closes the file.
+func (s *Store) Stop() {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return
+	}
+	s.running = false
+	close(s.stopChan)
+	s.mu.Unlock()
+
+	s.triggerBus.Unsubscribe(subscriberID)
+	s.wg.Wait()
+	s.catchUp()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file != nil {
+		if err := s.file.Close(); err != nil {
+			log.Printf("Error closing trigger history: %v", err)
+		}
+		s.file = nil
+	}
+}
+
+func (s *Store) run(ctx context.Context, subscriber *alerts.TriggerSubscriber) {
+	defer s.wg.Done()
+
+	s.catchUp()
+
+	catchUp := time.NewTicker(catchUpInterval)
+	defer catchUp.Stop()
+	compact := time.NewTicker(s.cfg.CompactInterval)
+	defer compact.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-compact.C:
+			s.Compact()
+		case <-catchUp.C:
+			if s.triggerBus.LastSequence() > s.cursor {
+				s.catchUp()
+			}
+		case trigger, ok := <-subscriber.TriggerChan:
+			if !ok {
+				return
+			}
+
+			switch {
+			case trigger.Sequence <= s.cursor:
+			case trigger.Sequence == s.cursor+1:
+				s.add(trigger)
+			default:
+				s.catchUp()
+			}
+		}
+	}
+}
+
+func (s *Store) catchUp() {
+	for _, trigger := range s.triggerBus.TriggersSince(s.cursor) {
+		s.add(trigger)
+	}
+}
+
+func (s *Store) add(trigger *models.AlertTrigger) {
+	s.cursor = trigger.Sequence
+	record := &Record{Trigger: trigger, Condition: trigger.Alert.Rule()}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.append(record)
+	if s.cfg.MaxRecords > 0 && len(s.records) > s.cfg.MaxRecords {
+		s.records = s.records[len(s.records)-s.cfg.MaxRecords:]
+	}
+
+	if s.file == nil {
+		return
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		log.Printf("Error encoding trigger %d for history: %v", trigger.Sequence, err)
+		return
+	}
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		log.Printf("Error writing trigger history: %v", err)
+		return
+	}
+	s.lines++
+}
+
+func (s *Store) append(record *Record) {
+	record.id = s.nextID
+	s.nextID++
+	s.records = append(s.records, record)
+}
+
+// List returns the triggers matching q, newest first, and the cursor for
+// the next page, which is zero after the last one.
+func (s *Store) List(q Query) ([]*Record, uint64) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	limit = min(limit, MaxPageSize)
+
+	filter := &alerts.TriggerFilter{OwnerID: q.OwnerID}
+	if q.AlertID != "" {
+		filter.AlertIDs = map[string]bool{q.AlertID: true}
+	}
+	if q.Symbol != "" {
+		filter.Symbols = map[string]bool{q.Symbol: true}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var page []*Record
+	for i := len(s.records) - 1; i >= 0; i-- {
+		record := s.records[i]
+		if q.Cursor != 0 && record.id >= q.Cursor {
+			continue
+		}
+
+		timestamp := record.Trigger.Timestamp
+		if (!q.From.IsZero() && timestamp.Before(q.From)) || (!q.To.IsZero() && !timestamp.Before(q.To)) {
+			continue
+		}
+		if !filter.Matches(record.Trigger) {
+			continue
+		}
+
+		if len(page) == limit {
+			return page, page[len(page)-1].id
+		}
+		page = append(page, record)
+	}
+
+	return page, 0
+}
+
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.records)
+}
+
+// Compact drops the triggers older than the retention and, with a Path,
+// rewrites the file once it holds records that are no longer kept.
+func (s *Store) Compact() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cfg.Retention > 0 {
+		cutoff := s.clock.Now().Add(-s.cfg.Retention)
+		kept := 0
+		for kept < len(s.records) && s.records[kept].Trigger.Timestamp.Before(cutoff) {
+			kept++
+		}
+		s.records = s.records[kept:]
+	}
+
+	if s.file != nil && s.lines > len(s.records) {
+		if err := s.rewrite(); err != nil {
+			log.Printf("Error compacting trigger history: %v", err)
+		}
+	}
+}
+
+func (s *Store) load() error {
+	file, err := os.Open(s.cfg.Path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open trigger history: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	for scanner.Scan() {
+		s.lines++
+
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil || record.Trigger == nil || record.Trigger.Alert == nil {
+			log.Printf("Warning: Ignoring corrupt trigger history entry %d", s.lines)
+			continue
+		}
+		s.append(&record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read trigger history: %v", err)
+	}
+
+	if s.cfg.MaxRecords > 0 && len(s.records) > s.cfg.MaxRecords {
+		s.records = s.records[len(s.records)-s.cfg.MaxRecords:]
+	}
+
+	return nil
+}
+
+func (s *Store) rewrite() error {
+	tmpPath := s.cfg.Path + ".tmp"
+	tmpFile, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create trigger history: %v", err)
+	}
+
+	writer := bufio.NewWriter(tmpFile)
+	encoder := json.NewEncoder(writer)
+	for _, record := range s.records {
+		if err := encoder.Encode(record); err != nil {
+			tmpFile.Close()
+			return fmt.Errorf("failed to encode trigger: %v", err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("failed to write trigger history: %v", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to write trigger history: %v", err)
+	}
+
+	if err := os.Rename(tmpPath, s.cfg.Path); err != nil {
+		return fmt.Errorf("failed to install trigger history: %v", err)
+	}
+
+	file, err := os.OpenFile(s.cfg.Path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to reopen trigger history: %v", err)
+	}
+
+	s.file.Close()
+	s.file = file
+	s.lines = len(s.records)
+
+	return nil
+}
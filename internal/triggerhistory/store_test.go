@@ -0,0 +1,201 @@
+package triggerhistory
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"crypto-price-alerts/internal/alerts"
+	"crypto-price-alerts/pkg/clock"
+	"crypto-price-alerts/pkg/models"
+)
+
+var start = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newBus(t *testing.T) *alerts.TriggerBus {
+	bus := alerts.NewTriggerBus()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	bus.Start(ctx)
+	t.Cleanup(bus.Stop)
+
+	return bus
+}
+
+func newAlert(symbol, owner string) *models.Alert {
+	alert := models.NewAlert(symbol, models.ComparatorGT, 100, "")
+	alert.OwnerID = owner
+	return alert
+}
+
+func waitFor(t *testing.T, timeout time.Duration, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("Timed out waiting for condition")
+}
+
+// record publishes count triggers for alert, one minute apart from at.
+func record(t *testing.T, bus *alerts.TriggerBus, alert *models.Alert, count int, at time.Time) {
+	t.Helper()
+
+	for i := 0; i < count; i++ {
+		bus.Publish(models.NewAlertTrigger(alert, float64(101+i), at.Add(time.Duration(i)*time.Minute)))
+	}
+}
+
+func TestStore_ListFiltersAndPages(t *testing.T) {
+	bus := newBus(t)
+	store, err := NewStore(bus, DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	store.Start(context.Background())
+
+	btc := newAlert("BTC", "alice")
+	eth := newAlert("ETH", "bob")
+	record(t, bus, btc, 5, start)
+	record(t, bus, eth, 3, start.Add(time.Hour))
+	store.Stop()
+
+	if store.Len() != 8 {
+		t.Fatalf("Expected 8 triggers, got %d", store.Len())
+	}
+
+	tests := []struct {
+		name     string
+		query    Query
+		expected int
+	}{
+		{"all", Query{}, 8},
+		{"by alert", Query{AlertID: btc.ID}, 5},
+		{"by symbol", Query{Symbol: "ETH"}, 3},
+		{"by owner", Query{OwnerID: "bob"}, 3},
+		{"other owner's alert", Query{OwnerID: "bob", AlertID: btc.ID}, 0},
+		{"from", Query{From: start.Add(3 * time.Minute)}, 5},
+		{"to", Query{To: start.Add(3 * time.Minute)}, 3},
+		{"range", Query{Symbol: "BTC", From: start.Add(time.Minute), To: start.Add(4 * time.Minute)}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, next := store.List(tt.query)
+			if len(records) != tt.expected || next != 0 {
+				t.Errorf("List() returned %d triggers and cursor %d, expected %d and 0", len(records), next, tt.expected)
+			}
+			for _, r := range records {
+				if r.Condition != r.Trigger.Alert.Rule() {
+					t.Errorf("Condition = %q, expected %q", r.Condition, r.Trigger.Alert.Rule())
+				}
+			}
+		})
+	}
+
+	var prices []float64
+	query := Query{Symbol: "BTC", Limit: 2}
+	for page := 0; ; page++ {
+		if page > 5 {
+			t.Fatal("Paging did not end")
+		}
+
+		records, next := store.List(query)
+		for _, r := range records {
+			prices = append(prices, r.Trigger.TriggeredPrice)
+		}
+		if next == 0 {
+			break
+		}
+		query.Cursor = next
+	}
+
+	expected := []float64{105, 104, 103, 102, 101}
+	if len(prices) != len(expected) {
+		t.Fatalf("Expected pages to cover %v, got %v", expected, prices)
+	}
+	for i := range expected {
+		if prices[i] != expected[i] {
+			t.Errorf("Expected triggers newest first %v, got %v", expected, prices)
+			break
+		}
+	}
+}
+
+func TestStore_Retention(t *testing.T) {
+	bus := newBus(t)
+	fake := clock.NewFake(start)
+	store, err := NewStore(bus, Config{Retention: time.Hour, MaxRecords: 4, Clock: fake})
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	store.Start(context.Background())
+
+	alert := newAlert("BTC", "")
+	record(t, bus, alert, 6, start)
+	store.Stop()
+
+	records, _ := store.List(Query{})
+	if len(records) != 4 || records[3].Trigger.TriggeredPrice != 103 {
+		t.Fatalf("Expected the 4 newest triggers to be kept, got %d", len(records))
+	}
+
+	fake.Set(start.Add(time.Hour + 4*time.Minute + 30*time.Second))
+	store.Compact()
+
+	records, _ = store.List(Query{})
+	if len(records) != 1 || records[0].Trigger.TriggeredPrice != 106 {
+		t.Errorf("Expected triggers older than an hour to be dropped, got %d", len(records))
+	}
+}
+
+func TestStore_PersistsAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trigger_history.jsonl")
+	fake := clock.NewFake(start)
+	cfg := Config{Retention: time.Hour, Path: path, Clock: fake}
+
+	bus := newBus(t)
+	store, err := NewStore(bus, cfg)
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	store.Start(context.Background())
+
+	alert := newAlert("BTC", "alice")
+	record(t, bus, alert, 3, start)
+	waitFor(t, time.Second, func() bool { return store.Len() == 3 })
+
+	fake.Set(start.Add(time.Hour + 30*time.Second))
+	store.Compact()
+	store.Stop()
+
+	// The restarted server numbers triggers from 1 again; they must still be
+	// recorded after the ones already in the history.
+	bus = newBus(t)
+	store, err = NewStore(bus, cfg)
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	if store.Len() != 2 || store.lines != 2 {
+		t.Fatalf("Expected the compacted history to be reloaded, got %d triggers in %d lines", store.Len(), store.lines)
+	}
+	store.Start(context.Background())
+
+	record(t, bus, alert, 1, start.Add(2*time.Hour))
+	store.Stop()
+
+	records, _ := store.List(Query{AlertID: alert.ID})
+	if len(records) != 3 || !records[0].Trigger.Timestamp.Equal(start.Add(2*time.Hour)) {
+		t.Fatalf("Expected the new trigger after the reloaded ones, got %d", len(records))
+	}
+	if records[1].Condition != alert.Rule() || records[1].Trigger.Alert.OwnerID != "alice" {
+		t.Errorf("Reloaded trigger lost its details: %+v", records[1])
+	}
+}
@@ -0,0 +1,36 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	fake := NewFake(start)
+
+	if got := fake.Now(); !got.Equal(start) {
+		t.Errorf("Now() = %v, expected %v", got, start)
+	}
+
+	fake.Advance(90 * time.Second)
+	if got := fake.Now(); !got.Equal(start.Add(90 * time.Second)) {
+		t.Errorf("Now() after Advance = %v", got)
+	}
+
+	fake.Set(start)
+	if got := fake.Now(); !got.Equal(start) {
+		t.Errorf("Now() after Set = %v", got)
+	}
+}
+
+func TestOrReal(t *testing.T) {
+	if OrReal(nil) != Real {
+		t.Error("Expected a nil clock to fall back to Real")
+	}
+
+	fake := NewFake(time.Time{})
+	if OrReal(fake) != Clock(fake) {
+		t.Error("Expected a set clock to be kept")
+	}
+}
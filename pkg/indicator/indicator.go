@@ -0,0 +1,218 @@
+// Package indicator implements technical indicators that update
+// incrementally, one closing price at a time, in constant time per update.
+package indicator
+
+import (
+	"math"
+	"time"
+)
+
+// MovingAverage is implemented by SMA and EMA.
+type MovingAverage interface {
+	Update(price float64)
+	// Value returns the average once enough prices have been seen.
+	Value() (float64, bool)
+}
+
+// ring holds the last n prices with their running sum and sum of squares.
+type ring struct {
+	values []float64
+	next   int
+	count  int
+	sum    float64
+	sumSq  float64
+}
+
+func newRing(n int) *ring {
+	return &ring{values: make([]float64, n)}
+}
+
+func (r *ring) add(x float64) {
+	if r.count == len(r.values) {
+		old := r.values[r.next]
+		r.sum -= old
+		r.sumSq -= old * old
+	} else {
+		r.count++
+	}
+
+	r.values[r.next] = x
+	r.next = (r.next + 1) % len(r.values)
+	r.sum += x
+	r.sumSq += x * x
+}
+
+func (r *ring) full() bool {
+	return r.count == len(r.values)
+}
+
+// SMA is the simple moving average of the last period prices.
+type SMA struct {
+	window *ring
+}
+
+func NewSMA(period int) *SMA {
+	return &SMA{window: newRing(period)}
+}
+
+func (s *SMA) Update(price float64) {
+	s.window.add(price)
+}
+
+func (s *SMA) Value() (float64, bool) {
+	if !s.window.full() {
+		return 0, false
+	}
+	return s.window.sum / float64(s.window.count), true
+}
+
+// EMA is the exponential moving average over period prices, seeded with
+// the SMA of the first period prices.
+type EMA struct {
+	alpha float64
+	seed  *SMA
+	value float64
+	ready bool
+}
+
+func NewEMA(period int) *EMA {
+	return &EMA{alpha: 2 / float64(period+1), seed: NewSMA(period)}
+}
+
+func (e *EMA) Update(price float64) {
+	if e.ready {
+		e.value += e.alpha * (price - e.value)
+		return
+	}
+
+	e.seed.Update(price)
+	e.value, e.ready = e.seed.Value()
+}
+
+func (e *EMA) Value() (float64, bool) {
+	return e.value, e.ready
+}
+
+// RSI is Wilder's relative strength index over period price changes, from
+// 0 to 100.
+type RSI struct {
+	period  int
+	last    float64
+	changes int
+	avgGain float64
+	avgLoss float64
+}
+
+func NewRSI(period int) *RSI {
+	return &RSI{period: period, changes: -1}
+}
+
+func (r *RSI) Update(price float64) {
+	r.changes++
+	change := price - r.last
+	r.last = price
+	if r.changes == 0 {
+		return
+	}
+
+	gain, loss := math.Max(change, 0), math.Max(-change, 0)
+	n := float64(r.period)
+
+	if r.changes <= r.period {
+		// Seed the averages with the mean of the first period changes.
+		r.avgGain += gain / n
+		r.avgLoss += loss / n
+		return
+	}
+
+	r.avgGain = (r.avgGain*(n-1) + gain) / n
+	r.avgLoss = (r.avgLoss*(n-1) + loss) / n
+}
+
+func (r *RSI) Value() (float64, bool) {
+	if r.changes < r.period {
+		return 0, false
+	}
+	if r.avgLoss == 0 {
+		if r.avgGain == 0 {
+			return 50, true
+		}
+		return 100, true
+	}
+	return 100 - 100/(1+r.avgGain/r.avgLoss), true
+}
+
+// Bollinger bands sit stdDevs population standard deviations either side of
+// the SMA of the last period prices.
+type Bollinger struct {
+	window  *ring
+	stdDevs float64
+}
+
+func NewBollinger(period int, stdDevs float64) *Bollinger {
+	return &Bollinger{window: newRing(period), stdDevs: stdDevs}
+}
+
+func (b *Bollinger) Update(price float64) {
+	b.window.add(price)
+}
+
+func (b *Bollinger) Bands() (lower, middle, upper float64, ok bool) {
+	if !b.window.full() {
+		return 0, 0, 0, false
+	}
+
+	n := float64(b.window.count)
+	middle = b.window.sum / n
+	variance := math.Max(b.window.sumSq/n-middle*middle, 0)
+	width := b.stdDevs * math.Sqrt(variance)
+
+	return middle - width, middle, middle + width, true
+}
+
+// PercentB places price relative to the bands: 0 at the lower band, 1 at
+// the upper band. It is undefined while the bands have no width.
+func (b *Bollinger) PercentB(price float64) (float64, bool) {
+	lower, _, upper, ok := b.Bands()
+	if !ok || upper == lower {
+		return 0, false
+	}
+	return (price - lower) / (upper - lower), true
+}
+
+// Bars turns a stream of prices into the closing prices of fixed-length
+// bars aligned to the interval.
+type Bars struct {
+	interval time.Duration
+	bucket   time.Time
+	last     float64
+	started  bool
+}
+
+func NewBars(interval time.Duration) *Bars {
+	return &Bars{interval: interval}
+}
+
+// Add records a price and returns the close of the bar it finishes, if any.
+// A bar finishes when the first price of the next one arrives. With a zero
+// interval every price closes a bar of its own.
+func (b *Bars) Add(price float64, timestamp time.Time) (float64, bool) {
+	if b.interval <= 0 {
+		return price, true
+	}
+
+	bucket := timestamp.Truncate(b.interval)
+	if !b.started {
+		b.bucket, b.last, b.started = bucket, price, true
+		return 0, false
+	}
+
+	if !bucket.After(b.bucket) {
+		b.last = price
+		return 0, false
+	}
+
+	close := b.last
+	b.bucket, b.last = bucket, price
+	return close, true
+}
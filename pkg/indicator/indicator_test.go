@@ -0,0 +1,122 @@
+package indicator
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestMovingAverages(t *testing.T) {
+	tests := []struct {
+		name     string
+		average  MovingAverage
+		prices   []float64
+		expected []float64 // NaN until ready
+	}{
+		{"sma", NewSMA(3), []float64{1, 2, 3, 4, 8}, []float64{math.NaN(), math.NaN(), 2, 3, 5}},
+		{"ema", NewEMA(3), []float64{1, 2, 3, 4, 8}, []float64{math.NaN(), math.NaN(), 2, 3, 5.5}},
+	}
+
+	for _, tt := range tests {
+		for i, price := range tt.prices {
+			tt.average.Update(price)
+			value, ok := tt.average.Value()
+
+			if math.IsNaN(tt.expected[i]) {
+				if ok {
+					t.Errorf("%s: ready after %d prices", tt.name, i+1)
+				}
+				continue
+			}
+			if !ok || !near(value, tt.expected[i]) {
+				t.Errorf("%s after %v: got %v (%v), expected %v", tt.name, tt.prices[:i+1], value, ok, tt.expected[i])
+			}
+		}
+	}
+}
+
+func TestRSI(t *testing.T) {
+	tests := []struct {
+		name     string
+		prices   []float64
+		ready    bool
+		expected float64
+	}{
+		{"not enough changes", []float64{1, 2, 3}, false, 0},
+		{"only gains", []float64{1, 2, 3, 4}, true, 100},
+		{"only losses", []float64{4, 3, 2, 1}, true, 0},
+		{"balanced", []float64{10, 11, 10, 11}, true, 66.66666666666667},
+		{"flat", []float64{5, 5, 5, 5}, true, 50},
+		// Seed gain 2/3, loss 1/3; then a loss of 3: gain 4/9, loss 11/9.
+		{"smoothed", []float64{10, 11, 10, 11, 8}, true, 100 - 100/(1+4.0/11)},
+	}
+
+	for _, tt := range tests {
+		rsi := NewRSI(3)
+		for _, price := range tt.prices {
+			rsi.Update(price)
+		}
+
+		value, ok := rsi.Value()
+		if ok != tt.ready || (ok && !near(value, tt.expected)) {
+			t.Errorf("%s: RSI = %v (%v), expected %v (%v)", tt.name, value, ok, tt.expected, tt.ready)
+		}
+	}
+}
+
+func TestBollinger(t *testing.T) {
+	bands := NewBollinger(4, 2)
+	for _, price := range []float64{2, 4, 4, 4} {
+		bands.Update(price)
+	}
+	bands.Update(5) // the 2 drops out: 4 4 4 5
+
+	lower, middle, upper, ok := bands.Bands()
+	if !ok || !near(middle, 4.25) || !near(upper-middle, 2*math.Sqrt(0.1875)) || !near(middle-lower, upper-middle) {
+		t.Errorf("Bands() = %v %v %v %v", lower, middle, upper, ok)
+	}
+
+	if b, ok := bands.PercentB(upper); !ok || !near(b, 1) {
+		t.Errorf("PercentB(upper) = %v %v, expected 1", b, ok)
+	}
+
+	flat := NewBollinger(2, 2)
+	flat.Update(3)
+	flat.Update(3)
+	if _, ok := flat.PercentB(3); ok {
+		t.Error("Expected PercentB to be undefined for bands with no width")
+	}
+}
+
+func TestBars(t *testing.T) {
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	bars := NewBars(time.Minute)
+
+	steps := []struct {
+		offset time.Duration
+		price  float64
+		closed bool
+		close  float64
+	}{
+		{10 * time.Second, 1, false, 0},
+		{50 * time.Second, 2, false, 0},
+		{70 * time.Second, 3, true, 2},
+		{5 * time.Minute, 4, true, 3},
+		{5*time.Minute + time.Second, 5, false, 0},
+	}
+
+	for _, step := range steps {
+		close, closed := bars.Add(step.price, start.Add(step.offset))
+		if closed != step.closed || close != step.close {
+			t.Errorf("Add(%v at +%v) = %v %v, expected %v %v", step.price, step.offset, close, closed, step.close, step.closed)
+		}
+	}
+
+	if close, closed := NewBars(0).Add(7, start); !closed || close != 7 {
+		t.Errorf("Expected every price to close a bar with no interval")
+	}
+}
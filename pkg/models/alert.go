@@ -1,8 +1,12 @@
 package models
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 
+	"crypto-price-alerts/pkg/rules"
+
 	"github.com/google/uuid"
 )
 
@@ -34,14 +38,179 @@ func (c Comparator) String() string {
 	}
 }
 
+// Compare reports whether price stands in relation c to threshold.
+func (c Comparator) Compare(price, threshold float64) bool {
+	switch c {
+	case ComparatorGT:
+		return price > threshold
+	case ComparatorGTE:
+		return price >= threshold
+	case ComparatorLT:
+		return price < threshold
+	case ComparatorLTE:
+		return price <= threshold
+	case ComparatorEQ:
+		const epsilon = 0.001
+		return abs(price-threshold) < epsilon
+	default:
+		return false
+	}
+}
+
+type AlertKind int
+
+const (
+	AlertKindThreshold AlertKind = iota
+	AlertKindPercentMove
+	// AlertKindFeedSilence describes the watchdog's system alerts; users
+	// cannot create it.
+	AlertKindFeedSilence
+	// AlertKindCompound fires while its Condition tree over one or more
+	// symbols holds.
+	AlertKindCompound
+	// AlertKindExpression fires while its Expression, written in the rules
+	// language, holds.
+	AlertKindExpression
+	// Indicator alerts fire when a signal derived from the price crosses a
+	// level; see IndicatorConfig.
+	AlertKindMACrossover
+	AlertKindRSI
+	AlertKindBollinger
+)
+
+func (k AlertKind) String() string {
+	switch k {
+	case AlertKindThreshold:
+		return "threshold"
+	case AlertKindPercentMove:
+		return "percent_move"
+	case AlertKindFeedSilence:
+		return "feed_silence"
+	case AlertKindCompound:
+		return "compound"
+	case AlertKindExpression:
+		return "expression"
+	case AlertKindMACrossover:
+		return "ma_crossover"
+	case AlertKindRSI:
+		return "rsi"
+	case AlertKindBollinger:
+		return "bollinger"
+	default:
+		return "unknown"
+	}
+}
+
+type Direction int
+
+const (
+	DirectionEither Direction = iota
+	DirectionUp
+	DirectionDown
+)
+
+func (d Direction) String() string {
+	switch d {
+	case DirectionEither:
+		return "either"
+	case DirectionUp:
+		return "up"
+	case DirectionDown:
+		return "down"
+	default:
+		return "unknown"
+	}
+}
+
+// TriggerMode selects between level-triggered threshold alerts, which fire
+// whenever the comparison holds, and edge-triggered ones, which fire only
+// when the price crosses the threshold.
+type TriggerMode int
+
+const (
+	TriggerModeLevel TriggerMode = iota
+	TriggerModeCrossUp
+	TriggerModeCrossDown
+	TriggerModeCrossAny
+)
+
+func (m TriggerMode) String() string {
+	switch m {
+	case TriggerModeLevel:
+		return "level"
+	case TriggerModeCrossUp:
+		return "cross_up"
+	case TriggerModeCrossDown:
+		return "cross_down"
+	case TriggerModeCrossAny:
+		return "cross_any"
+	default:
+		return "unknown"
+	}
+}
+
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// Alert is a rule on a symbol's price. Threshold alerts compare the last price
+// against Threshold; percent-move alerts fire when the price moves at least
+// Threshold percent in Direction within Window; compound alerts evaluate
+// Condition over the latest price of every symbol it references.
+//
+// Cooldown overrides the engine's minimum time between triggers when set.
+// The alert disables itself once ExpiresAt passes, or once FireCount reaches
+// one with FireOnce or MaxFires when that is set.
 type Alert struct {
-	ID          string     `json:"id"`
-	Symbol      string     `json:"symbol"`
-	Comparator  Comparator `json:"comparator"`
-	Threshold   float64    `json:"threshold"`
-	Note        string     `json:"note"`
-	Enabled     bool       `json:"enabled"`
-	LastTrigger *time.Time `json:"last_trigger,omitempty"`
+	ID          string           `json:"id"`
+	OwnerID     string           `json:"owner_id,omitempty"`
+	Symbol      string           `json:"symbol"`
+	Source      string           `json:"source,omitempty"`
+	Kind        AlertKind        `json:"kind"`
+	Comparator  Comparator       `json:"comparator"`
+	Threshold   float64          `json:"threshold"`
+	Mode        TriggerMode      `json:"mode,omitempty"`
+	Direction   Direction        `json:"direction,omitempty"`
+	Window      time.Duration    `json:"window,omitempty"`
+	Condition   *Condition       `json:"condition,omitempty"`
+	Expression  string           `json:"expression,omitempty"`
+	Indicator   *IndicatorConfig `json:"indicator,omitempty"`
+	Note        string           `json:"note"`
+	Labels      []string         `json:"labels,omitempty"`
+	Severity    Severity         `json:"severity,omitempty"`
+	WebhookURL  string           `json:"webhook_url,omitempty"`
+	Cooldown    time.Duration    `json:"cooldown,omitempty"`
+	ExpiresAt   *time.Time       `json:"expires_at,omitempty"`
+	FireOnce    bool             `json:"fire_once,omitempty"`
+	MaxFires    int              `json:"max_fires,omitempty"`
+	FireCount   int              `json:"fire_count,omitempty"`
+	Enabled     bool             `json:"enabled"`
+	LastTrigger *time.Time       `json:"last_trigger,omitempty"`
+
+	// WebhookSecret signs deliveries to WebhookURL. It is generated along
+	// with the URL and shown to the owner only then.
+	WebhookSecret string `json:"webhook_secret,omitempty"`
+
+	// symbols caches the symbols Expression references, so Symbols does not
+	// parse it on every trigger. SetExpression and decoding keep it current.
+	symbols []string
 }
 
 func NewAlert(symbol string, comparator Comparator, threshold float64, note string) *Alert {
@@ -55,31 +224,230 @@ func NewAlert(symbol string, comparator Comparator, threshold float64, note stri
 	}
 }
 
+func NewCrossingAlert(symbol string, mode TriggerMode, threshold float64, note string) *Alert {
+	return &Alert{
+		ID:        uuid.New().String(),
+		Symbol:    symbol,
+		Threshold: threshold,
+		Mode:      mode,
+		Note:      note,
+		Enabled:   true,
+	}
+}
+
+func NewPercentMoveAlert(symbol string, percent float64, window time.Duration, direction Direction, note string) *Alert {
+	return &Alert{
+		ID:        uuid.New().String(),
+		Symbol:    symbol,
+		Kind:      AlertKindPercentMove,
+		Threshold: percent,
+		Direction: direction,
+		Window:    window,
+		Note:      note,
+		Enabled:   true,
+	}
+}
+
+// NewCompoundAlert creates an alert on cond. Its Symbol is the first symbol
+// cond references.
+func NewCompoundAlert(cond *Condition, note string) *Alert {
+	alert := &Alert{
+		ID:        uuid.New().String(),
+		Kind:      AlertKindCompound,
+		Condition: cond,
+		Note:      note,
+		Enabled:   true,
+	}
+	if symbols := cond.Symbols(); len(symbols) > 0 {
+		alert.Symbol = symbols[0]
+	}
+	return alert
+}
+
+// NewExpressionAlert creates an alert on a compiled rule. Its Symbol is the
+// first symbol the rule references.
+func NewExpressionAlert(program *rules.Program, note string) *Alert {
+	alert := &Alert{
+		ID:      uuid.New().String(),
+		Kind:    AlertKindExpression,
+		Note:    note,
+		Enabled: true,
+	}
+	alert.SetExpression(program)
+	return alert
+}
+
+// SetExpression replaces the alert's rule. Its Symbol becomes the first
+// symbol the rule references.
+func (a *Alert) SetExpression(program *rules.Program) {
+	a.Expression = program.String()
+	a.symbols = program.Symbols()
+	a.Symbol = a.symbols[0]
+}
+
+// UnmarshalJSON decodes an alert and compiles its expression once, for the
+// symbols it references.
+func (a *Alert) UnmarshalJSON(data []byte) error {
+	type plain Alert
+	if err := json.Unmarshal(data, (*plain)(a)); err != nil {
+		return err
+	}
+
+	a.symbols = nil
+	if a.Kind == AlertKindExpression {
+		if program, err := rules.Compile(a.Expression); err == nil {
+			a.symbols = program.Symbols()
+		}
+	}
+	return nil
+}
+
+// Symbols returns every symbol whose ticks can change the alert's outcome.
+// The result must not be modified.
+func (a *Alert) Symbols() []string {
+	switch {
+	case a.Kind == AlertKindCompound && a.Condition != nil:
+		return a.Condition.Symbols()
+	case a.Kind == AlertKindExpression && a.symbols != nil:
+		return a.symbols
+	}
+	return []string{a.Symbol}
+}
+
 func (a *Alert) ShouldTrigger(price float64) bool {
-	if !a.Enabled {
+	if !a.Enabled || a.Kind != AlertKindThreshold {
 		return false
 	}
 
-	switch a.Comparator {
-	case ComparatorGT:
-		return price > a.Threshold
-	case ComparatorGTE:
-		return price >= a.Threshold
-	case ComparatorLT:
-		return price < a.Threshold
-	case ComparatorLTE:
-		return price <= a.Threshold
-	case ComparatorEQ:
-		const epsilon = 0.001
-		return abs(price-a.Threshold) < epsilon
+	return a.Comparator.Compare(price, a.Threshold)
+}
+
+// ShouldTriggerCross reports whether a crossing alert fires for a move from
+// previous to price.
+func (a *Alert) ShouldTriggerCross(previous, price float64) bool {
+	if !a.Enabled || a.Kind != AlertKindThreshold {
+		return false
+	}
+
+	crossedUp := previous < a.Threshold && price >= a.Threshold
+	crossedDown := previous > a.Threshold && price <= a.Threshold
+
+	switch a.Mode {
+	case TriggerModeCrossUp:
+		return crossedUp
+	case TriggerModeCrossDown:
+		return crossedDown
+	case TriggerModeCrossAny:
+		return crossedUp || crossedDown
 	default:
 		return false
 	}
 }
 
-func (a *Alert) MarkTriggered() {
-	now := time.Now()
-	a.LastTrigger = &now
+func (a *Alert) IsCrossing() bool {
+	return a.Kind == AlertKindThreshold && a.Mode != TriggerModeLevel
+}
+
+// ShouldTriggerMove reports whether a percent-move alert fires given the rise
+// from the window low and the fall from the window high, both in percent.
+func (a *Alert) ShouldTriggerMove(risePct, fallPct float64) bool {
+	if !a.Enabled || a.Kind != AlertKindPercentMove {
+		return false
+	}
+
+	switch a.Direction {
+	case DirectionUp:
+		return risePct >= a.Threshold
+	case DirectionDown:
+		return fallPct >= a.Threshold
+	case DirectionEither:
+		return risePct >= a.Threshold || fallPct >= a.Threshold
+	default:
+		return false
+	}
+}
+
+// ShouldTriggerCompound reports whether a compound alert's condition holds
+// given the latest prices. A condition that depends on a symbol with no price
+// yet does not hold.
+func (a *Alert) ShouldTriggerCompound(price PriceLookup) bool {
+	if !a.Enabled || a.Kind != AlertKindCompound || a.Condition == nil {
+		return false
+	}
+
+	result, known := a.Condition.Evaluate(price)
+	return known && result
+}
+
+func (a *Alert) Rule() string {
+	rule := a.rule()
+	if a.Source != "" {
+		rule += " on " + a.Source
+	}
+	return rule
+}
+
+func (a *Alert) rule() string {
+	switch a.Kind {
+	case AlertKindPercentMove:
+		sign := "±"
+		switch a.Direction {
+		case DirectionUp:
+			sign = "+"
+		case DirectionDown:
+			sign = "-"
+		}
+		return fmt.Sprintf("%s moves %s%.2f%% within %v", a.Symbol, sign, a.Threshold, a.Window)
+	case AlertKindFeedSilence:
+		return fmt.Sprintf("%s silent for %v", a.Symbol, a.Window)
+	case AlertKindCompound:
+		return a.Condition.String()
+	case AlertKindExpression:
+		return a.Expression
+	case AlertKindMACrossover, AlertKindRSI, AlertKindBollinger:
+		return a.indicatorRule()
+	}
+
+	switch a.Mode {
+	case TriggerModeCrossUp:
+		return fmt.Sprintf("%s crosses above %.2f", a.Symbol, a.Threshold)
+	case TriggerModeCrossDown:
+		return fmt.Sprintf("%s crosses below %.2f", a.Symbol, a.Threshold)
+	case TriggerModeCrossAny:
+		return fmt.Sprintf("%s crosses %.2f", a.Symbol, a.Threshold)
+	default:
+		return fmt.Sprintf("%s %s %.2f", a.Symbol, a.Comparator.String(), a.Threshold)
+	}
+}
+
+func (a *Alert) HasLabel(label string) bool {
+	for _, l := range a.Labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkTriggered records that the alert fired at the given time, which is the
+// time of the tick that fired it rather than the wall clock, and disables it
+// once it has fired as often as it may.
+func (a *Alert) MarkTriggered(at time.Time) {
+	a.LastTrigger = &at
+	a.FireCount++
+	if a.Exhausted() {
+		a.Enabled = false
+	}
+}
+
+// Exhausted reports whether the alert has fired as often as FireOnce or
+// MaxFires allow.
+func (a *Alert) Exhausted() bool {
+	return (a.FireOnce && a.FireCount >= 1) || (a.MaxFires > 0 && a.FireCount >= a.MaxFires)
+}
+
+func (a *Alert) Expired(now time.Time) bool {
+	return a.ExpiresAt != nil && !now.Before(*a.ExpiresAt)
 }
 
 func abs(x float64) float64 {
@@ -89,16 +457,65 @@ func abs(x float64) float64 {
 	return x
 }
 
+// TriggerEvent tells alert triggers apart from the system events published
+// on the same bus.
+type TriggerEvent int
+
+const (
+	TriggerEventAlert TriggerEvent = iota
+	TriggerEventFeedStale
+	TriggerEventFeedRecovered
+)
+
+func (e TriggerEvent) String() string {
+	switch e {
+	case TriggerEventAlert:
+		return "alert"
+	case TriggerEventFeedStale:
+		return "feed_stale"
+	case TriggerEventFeedRecovered:
+		return "feed_recovered"
+	default:
+		return "unknown"
+	}
+}
+
 type AlertTrigger struct {
-	Alert          *Alert    `json:"alert"`
-	TriggeredPrice float64   `json:"triggered_price"`
-	Timestamp      time.Time `json:"timestamp"`
+	Sequence       uint64       `json:"sequence"`
+	Event          TriggerEvent `json:"event,omitempty"`
+	Alert          *Alert       `json:"alert"`
+	TriggeredPrice float64      `json:"triggered_price"`
+	Timestamp      time.Time    `json:"timestamp"`
+}
+
+// SystemLabel marks the alerts behind system events so subscribers can
+// filter for them.
+const SystemLabel = "system"
+
+// NewFeedSilenceAlert describes symbol going without ticks for longer than
+// threshold. Its ID is stable so stale and recovery events pair up.
+func NewFeedSilenceAlert(symbol string, threshold time.Duration) *Alert {
+	return &Alert{
+		ID:       "feed-silence:" + symbol,
+		Symbol:   symbol,
+		Kind:     AlertKindFeedSilence,
+		Window:   threshold,
+		Labels:   []string{SystemLabel},
+		Severity: SeverityCritical,
+		Enabled:  true,
+	}
+}
+
+// IsSystem reports whether the trigger is a system event rather than a
+// user alert firing.
+func (t *AlertTrigger) IsSystem() bool {
+	return t.Event != TriggerEventAlert
 }
 
-func NewAlertTrigger(alert *Alert, triggeredPrice float64) *AlertTrigger {
+func NewAlertTrigger(alert *Alert, triggeredPrice float64, at time.Time) *AlertTrigger {
 	return &AlertTrigger{
 		Alert:          alert,
 		TriggeredPrice: triggeredPrice,
-		Timestamp:      time.Now(),
+		Timestamp:      at,
 	}
 }
@@ -1,7 +1,11 @@
 package models
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
+
+	"crypto-price-alerts/pkg/rules"
 )
 
 func TestAlert_ShouldTrigger(t *testing.T) {
@@ -147,3 +151,99 @@ func TestComparator_String(t *testing.T) {
 		})
 	}
 }
+
+func TestAlert_ShouldTriggerMove(t *testing.T) {
+	tests := []struct {
+		name      string
+		direction Direction
+		rise      float64
+		fall      float64
+		expected  bool
+	}{
+		{"Either triggers on rise", DirectionEither, 3.5, 0, true},
+		{"Either triggers on fall", DirectionEither, 0, 3.0, true},
+		{"Either no trigger below threshold", DirectionEither, 2.9, 2.9, false},
+		{"Up ignores fall", DirectionUp, 0, 5.0, false},
+		{"Up triggers on rise", DirectionUp, 3.0, 0, true},
+		{"Down ignores rise", DirectionDown, 5.0, 0, false},
+		{"Down triggers on fall", DirectionDown, 0, 4.0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := NewPercentMoveAlert("BTC", 3.0, 15*time.Minute, tt.direction, "")
+			result := alert.ShouldTriggerMove(tt.rise, tt.fall)
+			if result != tt.expected {
+				t.Errorf("ShouldTriggerMove() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAlert_PercentMoveIgnoresThreshold(t *testing.T) {
+	alert := NewPercentMoveAlert("BTC", 3.0, 15*time.Minute, DirectionEither, "")
+
+	if alert.ShouldTrigger(100.0) {
+		t.Error("Expected percent-move alert not to trigger on a plain price")
+	}
+}
+
+func TestAlert_ShouldTriggerCross(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     TriggerMode
+		previous float64
+		price    float64
+		expected bool
+	}{
+		{"Up fires on upward crossing", TriggerModeCrossUp, 99.0, 101.0, true},
+		{"Up fires when reaching threshold", TriggerModeCrossUp, 99.0, 100.0, true},
+		{"Up ignores staying above", TriggerModeCrossUp, 101.0, 102.0, false},
+		{"Up ignores downward crossing", TriggerModeCrossUp, 101.0, 99.0, false},
+		{"Down fires on downward crossing", TriggerModeCrossDown, 101.0, 99.0, true},
+		{"Down ignores staying below", TriggerModeCrossDown, 99.0, 98.0, false},
+		{"Any fires on upward crossing", TriggerModeCrossAny, 99.0, 101.0, true},
+		{"Any fires on downward crossing", TriggerModeCrossAny, 101.0, 99.0, true},
+		{"Level never fires as crossing", TriggerModeLevel, 99.0, 101.0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alert := NewCrossingAlert("BTC", tt.mode, 100.0, "")
+			result := alert.ShouldTriggerCross(tt.previous, tt.price)
+			if result != tt.expected {
+				t.Errorf("ShouldTriggerCross() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAlert_ExpressionSymbols(t *testing.T) {
+	program, err := rules.Compile("price(ETH) / price(BTC) > 0.05")
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	alert := NewExpressionAlert(program, "")
+
+	if symbols := alert.Symbols(); len(symbols) != 2 || symbols[0] != "ETH" || symbols[1] != "BTC" || alert.Symbol != "ETH" {
+		t.Errorf("Symbols() = %v with symbol %s, expected [ETH BTC] and ETH", symbols, alert.Symbol)
+	}
+
+	data, err := json.Marshal(alert)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded Alert
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if symbols := decoded.Symbols(); len(symbols) != 2 || symbols[1] != "BTC" {
+		t.Errorf("Expected the decoded alert to keep its symbols, got %v", symbols)
+	}
+
+	program, _ = rules.Compile("price(SOL) > 200")
+	decoded.SetExpression(program)
+	if symbols := decoded.Symbols(); len(symbols) != 1 || symbols[0] != "SOL" || decoded.Symbol != "SOL" {
+		t.Errorf("Expected SetExpression to replace the symbols, got %v", symbols)
+	}
+}
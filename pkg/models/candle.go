@@ -0,0 +1,79 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// CandleIntervals are the candle lengths the server aggregates ticks into.
+var CandleIntervals = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	15 * time.Minute,
+	time.Hour,
+	24 * time.Hour,
+}
+
+// Candle summarises the ticks of one symbol and source within one interval.
+// Candles are aligned to the interval in UTC, so daily candles run from
+// midnight to midnight UTC. Volume is the last rolling 24-hour volume
+// reported within the interval; ticks carry no per-trade volume to sum.
+type Candle struct {
+	Symbol   string        `json:"symbol"`
+	Source   string        `json:"source,omitempty"`
+	Interval time.Duration `json:"interval"`
+	Start    time.Time     `json:"start"`
+	Open     float64       `json:"open"`
+	High     float64       `json:"high"`
+	Low      float64       `json:"low"`
+	Close    float64       `json:"close"`
+	Volume   float64       `json:"volume,omitempty"`
+	Ticks    int           `json:"ticks"`
+	// Closed is false while ticks may still change the candle.
+	Closed bool `json:"closed"`
+}
+
+// NewCandle starts the candle of the given interval that the tick falls in.
+func NewCandle(tick *Tick, interval time.Duration) *Candle {
+	return &Candle{
+		Symbol:   tick.Symbol,
+		Source:   tick.Source,
+		Interval: interval,
+		Start:    tick.Timestamp.Truncate(interval),
+		Open:     tick.Price,
+		High:     tick.Price,
+		Low:      tick.Price,
+		Close:    tick.Price,
+		Volume:   tick.Volume,
+		Ticks:    1,
+	}
+}
+
+func (c *Candle) End() time.Time {
+	return c.Start.Add(c.Interval)
+}
+
+// Contains reports whether t falls within the candle's interval.
+func (c *Candle) Contains(t time.Time) bool {
+	return !t.Before(c.Start) && t.Before(c.End())
+}
+
+// Add folds a tick from within the candle's interval into it.
+func (c *Candle) Add(tick *Tick) {
+	c.High = max(c.High, tick.Price)
+	c.Low = min(c.Low, tick.Price)
+	c.Close = tick.Price
+	c.Volume = tick.Volume
+	c.Ticks++
+}
+
+// IsCandleInterval reports whether candles are kept for the interval.
+func IsCandleInterval(interval time.Duration) bool {
+	return slices.Contains(CandleIntervals, interval)
+}
+
+func (c *Candle) String() string {
+	return fmt.Sprintf("%s %v %s O:%.2f H:%.2f L:%.2f C:%.2f (%d ticks)",
+		c.Symbol, c.Interval, c.Start.UTC().Format(time.RFC3339), c.Open, c.High, c.Low, c.Close, c.Ticks)
+}
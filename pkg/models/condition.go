@@ -0,0 +1,224 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type ConditionOp int
+
+const (
+	ConditionCompare ConditionOp = iota
+	ConditionAnd
+	ConditionOr
+	ConditionNot
+)
+
+func (op ConditionOp) String() string {
+	switch op {
+	case ConditionCompare:
+		return "compare"
+	case ConditionAnd:
+		return "AND"
+	case ConditionOr:
+		return "OR"
+	case ConditionNot:
+		return "NOT"
+	default:
+		return "unknown"
+	}
+}
+
+// maxConditionNodes bounds the size of a condition tree so one alert cannot
+// make every tick expensive to evaluate.
+const maxConditionNodes = 64
+
+// Condition is a node in a compound alert's condition tree. Compare nodes
+// test one symbol's latest price against Threshold; And, Or and Not nodes
+// combine their Children.
+type Condition struct {
+	Op         ConditionOp  `json:"op"`
+	Symbol     string       `json:"symbol,omitempty"`
+	Comparator Comparator   `json:"comparator,omitempty"`
+	Threshold  float64      `json:"threshold,omitempty"`
+	Children   []*Condition `json:"children,omitempty"`
+}
+
+// PriceLookup returns the latest price of symbol, or false if none has been
+// seen yet.
+type PriceLookup func(symbol string) (float64, bool)
+
+func Compare(symbol string, comparator Comparator, threshold float64) *Condition {
+	return &Condition{Op: ConditionCompare, Symbol: symbol, Comparator: comparator, Threshold: threshold}
+}
+
+func And(children ...*Condition) *Condition {
+	return &Condition{Op: ConditionAnd, Children: children}
+}
+
+func Or(children ...*Condition) *Condition {
+	return &Condition{Op: ConditionOr, Children: children}
+}
+
+func Not(child *Condition) *Condition {
+	return &Condition{Op: ConditionNot, Children: []*Condition{child}}
+}
+
+// Between holds while symbol's price is within [low, high].
+func Between(symbol string, low, high float64) *Condition {
+	return And(Compare(symbol, ComparatorGTE, low), Compare(symbol, ComparatorLTE, high))
+}
+
+func (c *Condition) Validate() error {
+	nodes := 0
+	return c.validate(&nodes)
+}
+
+func (c *Condition) validate(nodes *int) error {
+	if c == nil {
+		return fmt.Errorf("condition is required")
+	}
+
+	*nodes++
+	if *nodes > maxConditionNodes {
+		return fmt.Errorf("condition has more than %d nodes", maxConditionNodes)
+	}
+
+	switch c.Op {
+	case ConditionCompare:
+		if c.Symbol == "" {
+			return fmt.Errorf("comparison needs a symbol")
+		}
+		if c.Comparator < ComparatorGT || c.Comparator > ComparatorEQ {
+			return fmt.Errorf("comparison on %s needs a comparator", c.Symbol)
+		}
+		if c.Threshold <= 0 {
+			return fmt.Errorf("threshold for %s must be positive", c.Symbol)
+		}
+		if len(c.Children) > 0 {
+			return fmt.Errorf("comparison on %s cannot have children", c.Symbol)
+		}
+		return nil
+	case ConditionAnd, ConditionOr:
+		if len(c.Children) < 2 {
+			return fmt.Errorf("%s needs at least two conditions", c.Op)
+		}
+	case ConditionNot:
+		if len(c.Children) != 1 {
+			return fmt.Errorf("NOT needs exactly one condition")
+		}
+	default:
+		return fmt.Errorf("unknown condition operator %d", c.Op)
+	}
+
+	for _, child := range c.Children {
+		if err := child.validate(nodes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Symbols returns the symbols the condition references, in the order they
+// first appear.
+func (c *Condition) Symbols() []string {
+	var symbols []string
+	seen := make(map[string]bool)
+
+	var walk func(*Condition)
+	walk = func(node *Condition) {
+		if node == nil {
+			return
+		}
+		if node.Op == ConditionCompare && !seen[node.Symbol] {
+			seen[node.Symbol] = true
+			symbols = append(symbols, node.Symbol)
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(c)
+
+	return symbols
+}
+
+// Evaluate returns the condition's outcome and whether it is known. A
+// comparison on a symbol with no price is unknown; AND is still false if any
+// known child is false, and OR still true if any known child is true.
+func (c *Condition) Evaluate(price PriceLookup) (result, known bool) {
+	switch c.Op {
+	case ConditionCompare:
+		p, ok := price(c.Symbol)
+		if !ok {
+			return false, false
+		}
+		return c.Comparator.Compare(p, c.Threshold), true
+	case ConditionAnd:
+		known = true
+		for _, child := range c.Children {
+			r, k := child.Evaluate(price)
+			if k && !r {
+				return false, true
+			}
+			known = known && k
+		}
+		return known, known
+	case ConditionOr:
+		known = true
+		for _, child := range c.Children {
+			r, k := child.Evaluate(price)
+			if k && r {
+				return true, true
+			}
+			known = known && k
+		}
+		return false, known
+	case ConditionNot:
+		if len(c.Children) != 1 {
+			return false, false
+		}
+		r, k := c.Children[0].Evaluate(price)
+		return !r, k
+	default:
+		return false, false
+	}
+}
+
+// String renders the condition in the syntax ParseCondition accepts.
+func (c *Condition) String() string {
+	if c == nil {
+		return ""
+	}
+
+	switch c.Op {
+	case ConditionCompare:
+		return fmt.Sprintf("%s %s %s", c.Symbol, c.Comparator, strconv.FormatFloat(c.Threshold, 'f', -1, 64))
+	case ConditionAnd, ConditionOr:
+		parts := make([]string, len(c.Children))
+		for i, child := range c.Children {
+			parts[i] = child.operand(c.Op)
+		}
+		return strings.Join(parts, " "+c.Op.String()+" ")
+	case ConditionNot:
+		if len(c.Children) != 1 {
+			return "NOT ?"
+		}
+		return "NOT " + c.Children[0].operand(ConditionNot)
+	default:
+		return "?"
+	}
+}
+
+// operand renders c as a child of parent, parenthesised unless it binds at
+// least as tightly.
+func (c *Condition) operand(parent ConditionOp) string {
+	if c == nil {
+		return "?"
+	}
+	if c.Op == ConditionCompare || c.Op == parent || c.Op == ConditionNot {
+		return c.String()
+	}
+	return "(" + c.String() + ")"
+}
@@ -0,0 +1,230 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseCondition parses a condition such as
+//
+//	BTC > 100000 AND (ETH < 3000 OR NOT SOL BETWEEN 100 AND 200)
+//
+// Keywords are case-insensitive, NOT binds tighter than AND, and AND tighter
+// than OR. Symbols are upper-cased.
+func ParseCondition(text string) (*Condition, error) {
+	tokens, err := tokenizeCondition(text)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &conditionParser{tokens: tokens}
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("unexpected %q", p.peek())
+	}
+
+	if err := cond.Validate(); err != nil {
+		return nil, err
+	}
+	return cond, nil
+}
+
+func tokenizeCondition(text string) ([]string, error) {
+	var tokens []string
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, string(r))
+			i++
+		case strings.ContainsRune("<>=!", r):
+			j := i + 1
+			if j < len(runes) && runes[j] == '=' {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_' || r == '-' || r == '/':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || strings.ContainsRune("._-/", runes[j])) {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q", r)
+		}
+	}
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("condition is empty")
+	}
+	return tokens, nil
+}
+
+type conditionParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *conditionParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *conditionParser) peek() string {
+	if p.done() {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *conditionParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *conditionParser) keyword(word string) bool {
+	if strings.EqualFold(p.peek(), word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *conditionParser) parseOr() (*Condition, error) {
+	return p.parseChain(ConditionOr, "OR", p.parseAnd)
+}
+
+func (p *conditionParser) parseAnd() (*Condition, error) {
+	return p.parseChain(ConditionAnd, "AND", p.parseNot)
+}
+
+func (p *conditionParser) parseChain(op ConditionOp, word string, operand func() (*Condition, error)) (*Condition, error) {
+	first, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []*Condition{first}
+	for p.keyword(word) {
+		child, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	if len(children) == 1 {
+		return first, nil
+	}
+	return &Condition{Op: op, Children: children}, nil
+}
+
+func (p *conditionParser) parseNot() (*Condition, error) {
+	if p.keyword("NOT") {
+		child, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return Not(child), nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *conditionParser) parsePrimary() (*Condition, error) {
+	if p.peek() == "(" {
+		p.next()
+		cond, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		return cond, nil
+	}
+
+	symbol := p.next()
+	if symbol == "" {
+		return nil, fmt.Errorf("expected a comparison, got end of condition")
+	}
+	if !isConditionSymbol(symbol) {
+		return nil, fmt.Errorf("expected a symbol, got %q", symbol)
+	}
+	symbol = strings.ToUpper(symbol)
+
+	if p.keyword("BETWEEN") {
+		low, err := p.parseNumber()
+		if err != nil {
+			return nil, err
+		}
+		if !p.keyword("AND") {
+			return nil, fmt.Errorf("expected AND in %s BETWEEN", symbol)
+		}
+		high, err := p.parseNumber()
+		if err != nil {
+			return nil, err
+		}
+		if low > high {
+			return nil, fmt.Errorf("%s BETWEEN %v AND %v is empty", symbol, low, high)
+		}
+		return Between(symbol, low, high), nil
+	}
+
+	comparator, err := parseComparator(p.next())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", symbol, err)
+	}
+	threshold, err := p.parseNumber()
+	if err != nil {
+		return nil, err
+	}
+	return Compare(symbol, comparator, threshold), nil
+}
+
+func (p *conditionParser) parseNumber() (float64, error) {
+	token := p.next()
+	value, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		return 0, fmt.Errorf("expected a number, got %q", token)
+	}
+	return value, nil
+}
+
+func isConditionSymbol(token string) bool {
+	if !unicode.IsLetter([]rune(token)[0]) {
+		return false
+	}
+	switch strings.ToUpper(token) {
+	case "AND", "OR", "NOT", "BETWEEN":
+		return false
+	}
+	return true
+}
+
+func parseComparator(token string) (Comparator, error) {
+	switch token {
+	case ">":
+		return ComparatorGT, nil
+	case ">=":
+		return ComparatorGTE, nil
+	case "<":
+		return ComparatorLT, nil
+	case "<=":
+		return ComparatorLTE, nil
+	case "==", "=":
+		return ComparatorEQ, nil
+	default:
+		return ComparatorUnspecified, fmt.Errorf("expected a comparator, got %q", token)
+	}
+}
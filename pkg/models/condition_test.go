@@ -0,0 +1,108 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		symbols  []string
+	}{
+		{"BTC > 100000", "BTC > 100000", []string{"BTC"}},
+		{"btc >= 1 and eth < 2", "BTC >= 1 AND ETH < 2", []string{"BTC", "ETH"}},
+		{"BTC > 1 OR ETH > 2 AND SOL > 3", "BTC > 1 OR (ETH > 2 AND SOL > 3)", []string{"BTC", "ETH", "SOL"}},
+		{"(BTC > 1 OR ETH > 2) AND SOL > 3", "(BTC > 1 OR ETH > 2) AND SOL > 3", []string{"BTC", "ETH", "SOL"}},
+		{"NOT (BTC > 1 AND ETH = 2)", "NOT (BTC > 1 AND ETH == 2)", []string{"BTC", "ETH"}},
+		{"ETH BETWEEN 2000 AND 3000 AND BTC<90000.5", "ETH >= 2000 AND ETH <= 3000 AND BTC < 90000.5", []string{"ETH", "BTC"}},
+	}
+
+	for _, tt := range tests {
+		cond, err := ParseCondition(tt.input)
+		if err != nil {
+			t.Errorf("ParseCondition(%q) error: %v", tt.input, err)
+			continue
+		}
+		if got := cond.String(); got != tt.expected {
+			t.Errorf("ParseCondition(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+
+		reparsed, err := ParseCondition(cond.String())
+		if err != nil || reparsed.String() != cond.String() {
+			t.Errorf("ParseCondition(%q) does not round-trip: %v %v", cond.String(), reparsed, err)
+		}
+
+		symbols := cond.Symbols()
+		if len(symbols) != len(tt.symbols) {
+			t.Errorf("Symbols() of %q = %v, expected %v", tt.input, symbols, tt.symbols)
+			continue
+		}
+		for i := range symbols {
+			if symbols[i] != tt.symbols[i] {
+				t.Errorf("Symbols() of %q = %v, expected %v", tt.input, symbols, tt.symbols)
+			}
+		}
+	}
+}
+
+func TestParseCondition_Errors(t *testing.T) {
+	inputs := []string{
+		"",
+		"BTC",
+		"BTC >",
+		"BTC > -5",
+		"BTC ~ 5",
+		"BTC > 5 AND",
+		"(BTC > 5",
+		"BTC > 5)",
+		"AND > 5",
+		"ETH BETWEEN 3000 AND 2000",
+	}
+
+	for _, input := range inputs {
+		if cond, err := ParseCondition(input); err == nil {
+			t.Errorf("ParseCondition(%q) = %v, expected an error", input, cond)
+		}
+	}
+}
+
+func TestCondition_Evaluate(t *testing.T) {
+	cond, err := ParseCondition("BTC > 100 AND (ETH < 10 OR NOT SOL > 5)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		prices  map[string]float64
+		result  bool
+		known   bool
+		trigger bool
+	}{
+		{"all true", map[string]float64{"BTC": 101, "ETH": 9, "SOL": 6}, true, true, true},
+		{"NOT branch", map[string]float64{"BTC": 101, "ETH": 11, "SOL": 4}, true, true, true},
+		{"BTC false", map[string]float64{"BTC": 99, "ETH": 9, "SOL": 4}, false, true, false},
+		{"missing BTC", map[string]float64{"ETH": 9}, false, false, false},
+		{"false despite missing SOL", map[string]float64{"BTC": 99}, false, true, false},
+		{"OR short-circuits missing SOL", map[string]float64{"BTC": 101, "ETH": 9}, true, true, true},
+		{"unknown OR", map[string]float64{"BTC": 101, "ETH": 11}, false, false, false},
+	}
+
+	for _, tt := range tests {
+		lookup := func(symbol string) (float64, bool) {
+			price, ok := tt.prices[symbol]
+			return price, ok
+		}
+
+		result, known := cond.Evaluate(lookup)
+		if result != tt.result || known != tt.known {
+			t.Errorf("%s: Evaluate() = %v, %v; expected %v, %v", tt.name, result, known, tt.result, tt.known)
+		}
+
+		alert := NewCompoundAlert(cond, "")
+		if got := alert.ShouldTriggerCompound(lookup); got != tt.trigger {
+			t.Errorf("%s: ShouldTriggerCompound() = %v, expected %v", tt.name, got, tt.trigger)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type MovingAverage int
+
+const (
+	MovingAverageSMA MovingAverage = iota
+	MovingAverageEMA
+)
+
+func (m MovingAverage) String() string {
+	switch m {
+	case MovingAverageSMA:
+		return "sma"
+	case MovingAverageEMA:
+		return "ema"
+	default:
+		return "unknown"
+	}
+}
+
+// Limits on indicator parameters, so one alert cannot hold unbounded state.
+const (
+	MaxIndicatorPeriod = 1000
+	MinBarInterval     = time.Second
+)
+
+// IndicatorConfig parameterises indicator alerts. Indicators are computed
+// on the closing price of each Interval-long bar, or on every tick when
+// Interval is zero.
+//
+// A crossover alert compares the Average over Period bars against the one
+// over SlowPeriod bars and fires when the fast one crosses the slow one in
+// the alert's Direction. An RSI alert fires when the RSI over Period bars
+// crosses the alert's Threshold. A Bollinger alert fires when the price
+// leaves the bands StdDevs standard deviations around the Period-bar SMA.
+type IndicatorConfig struct {
+	Average    MovingAverage `json:"average,omitempty"`
+	Period     int           `json:"period"`
+	SlowPeriod int           `json:"slow_period,omitempty"`
+	StdDevs    float64       `json:"std_devs,omitempty"`
+	Interval   time.Duration `json:"interval,omitempty"`
+}
+
+const DefaultBollingerStdDevs = 2
+
+func newIndicatorAlert(symbol string, kind AlertKind, indicator IndicatorConfig, direction Direction, note string) *Alert {
+	return &Alert{
+		ID:        uuid.New().String(),
+		Symbol:    symbol,
+		Kind:      kind,
+		Direction: direction,
+		Indicator: &indicator,
+		Note:      note,
+		Enabled:   true,
+	}
+}
+
+// NewMACrossoverAlert fires when the fast moving average crosses the slow
+// one: upwards (a golden cross), downwards, or either way.
+func NewMACrossoverAlert(symbol string, average MovingAverage, fast, slow int, interval time.Duration, direction Direction, note string) *Alert {
+	return newIndicatorAlert(symbol, AlertKindMACrossover,
+		IndicatorConfig{Average: average, Period: fast, SlowPeriod: slow, Interval: interval}, direction, note)
+}
+
+// NewRSIAlert fires when the RSI rises above level (DirectionUp), falls
+// below it (DirectionDown), or crosses it either way.
+func NewRSIAlert(symbol string, period int, level float64, interval time.Duration, direction Direction, note string) *Alert {
+	alert := newIndicatorAlert(symbol, AlertKindRSI, IndicatorConfig{Period: period, Interval: interval}, direction, note)
+	alert.Threshold = level
+	return alert
+}
+
+// NewBollingerAlert fires when the price closes above the upper band
+// (DirectionUp), below the lower band (DirectionDown), or outside either.
+func NewBollingerAlert(symbol string, period int, stdDevs float64, interval time.Duration, direction Direction, note string) *Alert {
+	return newIndicatorAlert(symbol, AlertKindBollinger,
+		IndicatorConfig{Period: period, StdDevs: stdDevs, Interval: interval}, direction, note)
+}
+
+func (a *Alert) IsIndicator() bool {
+	switch a.Kind {
+	case AlertKindMACrossover, AlertKindRSI, AlertKindBollinger:
+		return true
+	default:
+		return false
+	}
+}
+
+// ValidateIndicator checks an indicator alert's parameters.
+func (a *Alert) ValidateIndicator() error {
+	c := a.Indicator
+	if c == nil {
+		return fmt.Errorf("indicator parameters are required")
+	}
+
+	if c.Period < 1 || c.Period > MaxIndicatorPeriod {
+		return fmt.Errorf("period must be between 1 and %d", MaxIndicatorPeriod)
+	}
+	if c.Interval < 0 || (c.Interval > 0 && c.Interval < MinBarInterval) {
+		return fmt.Errorf("bar interval must be zero (every tick) or at least %v", MinBarInterval)
+	}
+
+	switch a.Kind {
+	case AlertKindMACrossover:
+		if c.SlowPeriod <= c.Period || c.SlowPeriod > MaxIndicatorPeriod {
+			return fmt.Errorf("slow period must be longer than the fast period and at most %d", MaxIndicatorPeriod)
+		}
+		if c.Average != MovingAverageSMA && c.Average != MovingAverageEMA {
+			return fmt.Errorf("unknown moving average")
+		}
+	case AlertKindRSI:
+		if c.Period < 2 {
+			return fmt.Errorf("RSI period must be at least 2")
+		}
+		if a.Threshold <= 0 || a.Threshold >= 100 {
+			return fmt.Errorf("RSI level must be between 0 and 100")
+		}
+	case AlertKindBollinger:
+		if c.Period < 2 {
+			return fmt.Errorf("bollinger period must be at least 2")
+		}
+		if c.StdDevs <= 0 {
+			return fmt.Errorf("bollinger width must be a positive number of standard deviations")
+		}
+	default:
+		return fmt.Errorf("%s alerts have no indicator", a.Kind)
+	}
+
+	return nil
+}
+
+// ShouldTriggerIndicator reports whether an indicator alert fires for its
+// signal moving from previous to current. The signal is fast minus slow
+// average for crossovers, the RSI, and %B for Bollinger bands, where the
+// price is above the upper band above 1 and below the lower band below 0.
+func (a *Alert) ShouldTriggerIndicator(previous, current float64) bool {
+	if !a.Enabled || !a.IsIndicator() {
+		return false
+	}
+
+	upLevel, downLevel := 0.0, 0.0
+	switch a.Kind {
+	case AlertKindRSI:
+		upLevel, downLevel = a.Threshold, a.Threshold
+	case AlertKindBollinger:
+		upLevel, downLevel = 1, 0
+	}
+
+	up := previous <= upLevel && current > upLevel
+	down := previous >= downLevel && current < downLevel
+
+	switch a.Direction {
+	case DirectionUp:
+		return up
+	case DirectionDown:
+		return down
+	default:
+		return up || down
+	}
+}
+
+func (a *Alert) indicatorRule() string {
+	c := a.Indicator
+	if c == nil {
+		return a.Symbol + " indicator"
+	}
+
+	var rule string
+	switch a.Kind {
+	case AlertKindMACrossover:
+		verb := map[Direction]string{DirectionUp: "crosses above", DirectionDown: "crosses below"}[a.Direction]
+		if verb == "" {
+			verb = "crosses"
+		}
+		rule = fmt.Sprintf("%s %s(%d) %s %s(%d)", a.Symbol, c.Average, c.Period, verb, c.Average, c.SlowPeriod)
+	case AlertKindRSI:
+		verb := map[Direction]string{DirectionUp: "rises above", DirectionDown: "falls below"}[a.Direction]
+		if verb == "" {
+			verb = "crosses"
+		}
+		rule = fmt.Sprintf("%s rsi(%d) %s %s", a.Symbol, c.Period, verb, strconv.FormatFloat(a.Threshold, 'f', -1, 64))
+	case AlertKindBollinger:
+		side := map[Direction]string{DirectionUp: "above", DirectionDown: "below"}[a.Direction]
+		if side == "" {
+			side = "outside"
+		}
+		rule = fmt.Sprintf("%s closes %s bollinger(%d, %s)", a.Symbol, side, c.Period, strconv.FormatFloat(c.StdDevs, 'f', -1, 64))
+	}
+
+	if c.Interval > 0 {
+		rule += fmt.Sprintf(" on %v bars", c.Interval)
+	}
+	return rule
+}
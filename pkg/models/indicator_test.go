@@ -0,0 +1,60 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAlert_ValidateIndicator(t *testing.T) {
+	tests := []struct {
+		name  string
+		alert *Alert
+		valid bool
+	}{
+		{"crossover", NewMACrossoverAlert("BTC", MovingAverageEMA, 12, 26, time.Hour, DirectionUp, ""), true},
+		{"slow not slower", NewMACrossoverAlert("BTC", MovingAverageSMA, 50, 50, 0, DirectionUp, ""), false},
+		{"rsi", NewRSIAlert("BTC", 14, 70, 0, DirectionUp, ""), true},
+		{"rsi level out of range", NewRSIAlert("BTC", 14, 100, 0, DirectionUp, ""), false},
+		{"bollinger", NewBollingerAlert("BTC", 20, 2, 5*time.Minute, DirectionEither, ""), true},
+		{"bollinger without width", NewBollingerAlert("BTC", 20, 0, 0, DirectionEither, ""), false},
+		{"sub-second bars", NewRSIAlert("BTC", 14, 30, time.Millisecond, DirectionDown, ""), false},
+		{"period too long", NewRSIAlert("BTC", MaxIndicatorPeriod+1, 30, 0, DirectionDown, ""), false},
+		{"not an indicator", NewAlert("BTC", ComparatorGT, 1, ""), false},
+	}
+
+	for _, tt := range tests {
+		if err := tt.alert.ValidateIndicator(); (err == nil) != tt.valid {
+			t.Errorf("%s: ValidateIndicator() = %v, expected valid %v", tt.name, err, tt.valid)
+		}
+	}
+}
+
+func TestAlert_ShouldTriggerIndicator(t *testing.T) {
+	tests := []struct {
+		name     string
+		alert    *Alert
+		previous float64
+		current  float64
+		expected bool
+	}{
+		{"golden cross", NewMACrossoverAlert("BTC", MovingAverageSMA, 5, 20, 0, DirectionUp, ""), -1, 1, true},
+		{"no cross", NewMACrossoverAlert("BTC", MovingAverageSMA, 5, 20, 0, DirectionUp, ""), 1, 2, false},
+		{"death cross", NewMACrossoverAlert("BTC", MovingAverageSMA, 5, 20, 0, DirectionEither, ""), 1, -1, true},
+		{"rsi rises above", NewRSIAlert("BTC", 14, 70, 0, DirectionUp, ""), 65, 71, true},
+		{"rsi stays above", NewRSIAlert("BTC", 14, 70, 0, DirectionUp, ""), 71, 75, false},
+		{"rsi falls below", NewRSIAlert("BTC", 14, 30, 0, DirectionDown, ""), 31, 29, true},
+		{"leaves upper band", NewBollingerAlert("BTC", 20, 2, 0, DirectionEither, ""), 0.9, 1.1, true},
+		{"leaves lower band", NewBollingerAlert("BTC", 20, 2, 0, DirectionUp, ""), 0.1, -0.1, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.alert.ShouldTriggerIndicator(tt.previous, tt.current); got != tt.expected {
+			t.Errorf("%s: ShouldTriggerIndicator(%v, %v) = %v, expected %v", tt.name, tt.previous, tt.current, got, tt.expected)
+		}
+	}
+
+	alert := NewRSIAlert("ETH", 14, 70, time.Hour, DirectionUp, "")
+	if rule := alert.Rule(); rule != "ETH rsi(14) rises above 70 on 1h0m0s bars" {
+		t.Errorf("Rule() = %q", rule)
+	}
+}
@@ -2,16 +2,24 @@ package models
 
 import "time"
 
+// SourceConsolidated marks ticks produced by combining several venues.
+const SourceConsolidated = "consolidated"
+
+// Tick is one price update. Volume is the rolling 24-hour volume the venue
+// reported with the price, or zero when the feed reports none; it is a
+// running total, not the volume traded since the previous tick.
 type Tick struct {
 	Symbol    string    `json:"symbol"`
 	Price     float64   `json:"price"`
+	Volume    float64   `json:"volume,omitempty"`
+	Source    string    `json:"source,omitempty"`
 	Timestamp time.Time `json:"timestamp"`
 }
 
-func NewTick(symbol string, price float64) *Tick {
+func NewTick(symbol string, price float64, at time.Time) *Tick {
 	return &Tick{
 		Symbol:    symbol,
 		Price:     price,
-		Timestamp: time.Now(),
+		Timestamp: at,
 	}
 }
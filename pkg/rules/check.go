@@ -0,0 +1,313 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+type valueType int
+
+const (
+	typeNumber valueType = iota
+	typeBool
+	typeDuration
+	typeSymbol
+	typeCount
+)
+
+func (t valueType) String() string {
+	switch t {
+	case typeNumber:
+		return "number"
+	case typeBool:
+		return "condition"
+	case typeDuration:
+		return "duration"
+	case typeSymbol:
+		return "symbol"
+	case typeCount:
+		return "sample count"
+	default:
+		return "unknown"
+	}
+}
+
+// builtin describes a function rules can call. Symbol, duration and count
+// parameters must be literals so the history a rule needs is known when it
+// is compiled.
+type builtin struct {
+	params []valueType
+	names  []string
+	doc    string
+	eval   func(env Env, args []value) (float64, bool)
+}
+
+var builtins = map[string]builtin{
+	"price": {
+		params: []valueType{typeSymbol},
+		names:  []string{"symbol"},
+		doc:    "latest price",
+		eval: func(env Env, args []value) (float64, bool) {
+			return env.Price(args[0].symbol)
+		},
+	},
+	"change": {
+		params: []valueType{typeSymbol, typeDuration},
+		names:  []string{"symbol", "window"},
+		doc:    "price change over the window",
+		eval: func(env Env, args []value) (float64, bool) {
+			prices := env.Window(args[0].symbol, args[1].duration)
+			if len(prices) == 0 {
+				return 0, false
+			}
+			return prices[len(prices)-1] - prices[0], true
+		},
+	},
+	"pct_change": {
+		params: []valueType{typeSymbol, typeDuration},
+		names:  []string{"symbol", "window"},
+		doc:    "price change over the window, in percent",
+		eval: func(env Env, args []value) (float64, bool) {
+			prices := env.Window(args[0].symbol, args[1].duration)
+			if len(prices) == 0 || prices[0] == 0 {
+				return 0, false
+			}
+			return (prices[len(prices)-1] - prices[0]) / prices[0] * 100, true
+		},
+	},
+	"high": {
+		params: []valueType{typeSymbol, typeDuration},
+		names:  []string{"symbol", "window"},
+		doc:    "highest price within the window",
+		eval: func(env Env, args []value) (float64, bool) {
+			prices := env.Window(args[0].symbol, args[1].duration)
+			if len(prices) == 0 {
+				return 0, false
+			}
+			high := prices[0]
+			for _, p := range prices {
+				high = math.Max(high, p)
+			}
+			return high, true
+		},
+	},
+	"low": {
+		params: []valueType{typeSymbol, typeDuration},
+		names:  []string{"symbol", "window"},
+		doc:    "lowest price within the window",
+		eval: func(env Env, args []value) (float64, bool) {
+			prices := env.Window(args[0].symbol, args[1].duration)
+			if len(prices) == 0 {
+				return 0, false
+			}
+			low := prices[0]
+			for _, p := range prices {
+				low = math.Min(low, p)
+			}
+			return low, true
+		},
+	},
+	"sma": {
+		params: []valueType{typeSymbol, typeCount},
+		names:  []string{"symbol", "samples"},
+		doc:    "average of the last samples prices",
+		eval: func(env Env, args []value) (float64, bool) {
+			prices := env.Samples(args[0].symbol, args[1].count)
+			if len(prices) < args[1].count {
+				return 0, false
+			}
+			var sum float64
+			for _, p := range prices {
+				sum += p
+			}
+			return sum / float64(len(prices)), true
+		},
+	},
+	"abs": {
+		params: []valueType{typeNumber},
+		names:  []string{"x"},
+		doc:    "absolute value",
+		eval: func(env Env, args []value) (float64, bool) {
+			return math.Abs(args[0].number), true
+		},
+	},
+	"min": {
+		params: []valueType{typeNumber, typeNumber},
+		names:  []string{"x", "y"},
+		doc:    "smaller of two numbers",
+		eval: func(env Env, args []value) (float64, bool) {
+			return math.Min(args[0].number, args[1].number), true
+		},
+	},
+	"max": {
+		params: []valueType{typeNumber, typeNumber},
+		names:  []string{"x", "y"},
+		doc:    "larger of two numbers",
+		eval: func(env Env, args []value) (float64, bool) {
+			return math.Max(args[0].number, args[1].number), true
+		},
+	},
+}
+
+func (b builtin) signature(name string) string {
+	return fmt.Sprintf("%s(%s)", name, strings.Join(b.names, ", "))
+}
+
+// Functions lists the built-in functions with a short description of each.
+func Functions() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	functions := make([]string, len(names))
+	for i, name := range names {
+		functions[i] = fmt.Sprintf("%s: %s", builtins[name].signature(name), builtins[name].doc)
+	}
+	return functions
+}
+
+// checker infers the type of every node and collects what the rule needs
+// from the price history.
+type checker struct {
+	symbols []string
+	seen    map[string]bool
+	window  time.Duration
+	samples int
+}
+
+func (c *checker) check(n node) (valueType, error) {
+	switch n := n.(type) {
+	case *numberLit:
+		return typeNumber, nil
+	case *durationLit:
+		return typeDuration, nil
+	case *symbolRef:
+		return typeSymbol, nil
+	case *unary:
+		t, err := c.check(n.x)
+		if err != nil {
+			return 0, err
+		}
+		want := typeNumber
+		if n.op == "!" {
+			want = typeBool
+		}
+		if err := expect(n.x, t, want, "operand of "+n.op); err != nil {
+			return 0, err
+		}
+		return want, nil
+	case *binary:
+		return c.checkBinary(n)
+	case *call:
+		return c.checkCall(n)
+	default:
+		return 0, errorf(n.column(), "unsupported expression")
+	}
+}
+
+func (c *checker) checkBinary(n *binary) (valueType, error) {
+	x, err := c.check(n.x)
+	if err != nil {
+		return 0, err
+	}
+	y, err := c.check(n.y)
+	if err != nil {
+		return 0, err
+	}
+
+	operand, result := typeNumber, typeNumber
+	switch n.op {
+	case "&&", "||":
+		operand, result = typeBool, typeBool
+	case "<", "<=", ">", ">=", "==", "!=":
+		result = typeBool
+	}
+
+	if err := expect(n.x, x, operand, "left side of "+n.op); err != nil {
+		return 0, err
+	}
+	if err := expect(n.y, y, operand, "right side of "+n.op); err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+func (c *checker) checkCall(n *call) (valueType, error) {
+	fn, ok := builtins[n.name]
+	if !ok {
+		names := make([]string, 0, len(builtins))
+		for name := range builtins {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return 0, errorf(n.col, "unknown function %q; available: %s", n.name, strings.Join(names, ", "))
+	}
+
+	if len(n.args) != len(fn.params) {
+		return 0, errorf(n.col, "%s takes %d argument(s), got %d; use %s",
+			n.name, len(fn.params), len(n.args), fn.signature(n.name))
+	}
+
+	for i, arg := range n.args {
+		what := fmt.Sprintf("%s argument of %s", fn.names[i], n.name)
+
+		switch fn.params[i] {
+		case typeSymbol:
+			symbol, ok := arg.(*symbolRef)
+			if !ok {
+				return 0, errorf(arg.column(), "%s must be a symbol such as BTC", what)
+			}
+			if !c.seen[symbol.name] {
+				c.seen[symbol.name] = true
+				c.symbols = append(c.symbols, symbol.name)
+			}
+		case typeDuration:
+			window, ok := arg.(*durationLit)
+			if !ok || window.value <= 0 {
+				return 0, errorf(arg.column(), "%s must be a positive duration such as 15m or 1h", what)
+			}
+			if window.value > MaxWindow {
+				return 0, errorf(arg.column(), "%s must be at most %dd", what, MaxWindow/(24*time.Hour))
+			}
+			if window.value > c.window {
+				c.window = window.value
+			}
+		case typeCount:
+			count, ok := arg.(*numberLit)
+			if !ok || count.value < 1 || count.value != math.Trunc(count.value) {
+				return 0, errorf(arg.column(), "%s must be a whole number of samples such as 50", what)
+			}
+			if count.value > MaxSamples {
+				return 0, errorf(arg.column(), "%s must be at most %d samples", what, MaxSamples)
+			}
+			if int(count.value) > c.samples {
+				c.samples = int(count.value)
+			}
+		default:
+			t, err := c.check(arg)
+			if err != nil {
+				return 0, err
+			}
+			if err := expect(arg, t, fn.params[i], what); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return typeNumber, nil
+}
+
+func expect(n node, got, want valueType, what string) error {
+	if got == want {
+		return nil
+	}
+	if symbol, ok := n.(*symbolRef); ok && want == typeNumber {
+		return errorf(n.column(), "%s is a symbol; use price(%s) for its latest price", symbol.name, symbol.name)
+	}
+	return errorf(n.column(), "%s must be a %s, got a %s", what, want, got)
+}
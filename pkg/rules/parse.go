@@ -0,0 +1,388 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Error reports a problem in a rule at a 1-based column.
+type Error struct {
+	Col int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("col %d: %s", e.Col, e.Msg)
+}
+
+func errorf(col int, format string, args ...interface{}) *Error {
+	return &Error{Col: col, Msg: fmt.Sprintf(format, args...)}
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokDuration
+	tokIdent
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind     tokenKind
+	col      int
+	text     string
+	number   float64
+	duration time.Duration
+}
+
+func (t token) describe() string {
+	if t.kind == tokEOF {
+		return "end of rule"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "+", "-", "*", "/", "!"}
+
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	runes := []rune(src)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		col := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, col: col, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, col: col, text: ")"})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokComma, col: col, text: ","})
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			k := j
+			for k < len(runes) && (unicode.IsLetter(runes[k]) || unicode.IsDigit(runes[k]) || runes[k] == '.') {
+				k++
+			}
+			text := string(runes[i:k])
+
+			if k == j {
+				number, err := strconv.ParseFloat(text, 64)
+				if err != nil {
+					return nil, errorf(col, "invalid number %q", text)
+				}
+				tokens = append(tokens, token{kind: tokNumber, col: col, text: text, number: number})
+			} else {
+				duration, err := parseDuration(text)
+				if err != nil {
+					return nil, errorf(col, "invalid duration %q; use units s, m, h or d, as in 15m or 1h30m", text)
+				}
+				tokens = append(tokens, token{kind: tokDuration, col: col, text: text, duration: duration})
+			}
+			i = k
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokIdent, col: col, text: string(runes[i:j])})
+			i = j
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				switch r {
+				case '=':
+					return nil, errorf(col, "unexpected \"=\"; use == to compare")
+				case '&', '|':
+					return nil, errorf(col, "unexpected %q; use && or ||", r)
+				}
+				return nil, errorf(col, "unexpected character %q", r)
+			}
+			tokens = append(tokens, token{kind: tokOp, col: col, text: op})
+			i += len([]rune(op))
+		}
+	}
+
+	return append(tokens, token{kind: tokEOF, col: len(runes) + 1}), nil
+}
+
+// parseDuration accepts Go durations plus whole days, such as 2d.
+func parseDuration(text string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(text, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		if n > int(math.MaxInt64/(24*time.Hour)) {
+			return 0, fmt.Errorf("duration %q out of range", text)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(text)
+}
+
+var keywordHints = map[string]string{
+	"and": "use && to combine conditions",
+	"or":  "use || to combine conditions",
+	"not": "use ! to negate a condition",
+}
+
+func isKeyword(tok token) bool {
+	_, ok := keywordHints[strings.ToLower(tok.text)]
+	return tok.kind == tokIdent && ok
+}
+
+func unexpected(tok token) *Error {
+	if isKeyword(tok) {
+		return errorf(tok.col, "unexpected %s; %s", tok.describe(), keywordHints[strings.ToLower(tok.text)])
+	}
+	return errorf(tok.col, "unexpected %s", tok.describe())
+}
+
+type node interface {
+	column() int
+}
+
+type numberLit struct {
+	col   int
+	value float64
+	text  string
+}
+
+type durationLit struct {
+	col   int
+	value time.Duration
+	text  string
+}
+
+type symbolRef struct {
+	col  int
+	name string
+}
+
+type call struct {
+	col  int
+	name string
+	args []node
+}
+
+type unary struct {
+	col int
+	op  string
+	x   node
+}
+
+type binary struct {
+	col  int
+	op   string
+	x, y node
+}
+
+func (n *numberLit) column() int   { return n.col }
+func (n *durationLit) column() int { return n.col }
+func (n *symbolRef) column() int   { return n.col }
+func (n *call) column() int        { return n.col }
+func (n *unary) column() int       { return n.col }
+func (n *binary) column() int      { return n.col }
+
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+}
+
+func parse(src string) (node, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return nil, errorf(1, "rule is empty")
+	}
+
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, unexpected(tok)
+	}
+	return expr, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) acceptOp(ops ...string) (token, bool) {
+	tok := p.peek()
+	if tok.kind != tokOp {
+		return tok, false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			return p.next(), true
+		}
+	}
+	return tok, false
+}
+
+func (p *parser) parseBinary(operand func() (node, error), ops ...string) (node, error) {
+	x, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok, ok := p.acceptOp(ops...)
+		if !ok {
+			return x, nil
+		}
+		y, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		x = &binary{col: tok.col, op: tok.text, x: x, y: y}
+	}
+}
+
+func (p *parser) parseOr() (node, error) {
+	return p.parseBinary(p.parseAnd, "||")
+}
+
+func (p *parser) parseAnd() (node, error) {
+	return p.parseBinary(p.parseComparison, "&&")
+}
+
+func (p *parser) parseComparison() (node, error) {
+	x, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	tok, ok := p.acceptOp("<", "<=", ">", ">=", "==", "!=")
+	if !ok {
+		return x, nil
+	}
+	y, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	if next, chained := p.acceptOp("<", "<=", ">", ">=", "==", "!="); chained {
+		return nil, errorf(next.col, "comparisons cannot be chained; combine them with &&")
+	}
+	return &binary{col: tok.col, op: tok.text, x: x, y: y}, nil
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	return p.parseBinary(p.parseMultiplicative, "+", "-")
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	return p.parseBinary(p.parseUnary, "*", "/")
+}
+
+// parseUnary is where every nested operand, parenthesis and call argument
+// recurses through, so it bounds how deep a rule can nest.
+func (p *parser) parseUnary() (node, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > MaxDepth {
+		return nil, errorf(p.peek().col, "rule is nested more than %d levels deep", MaxDepth)
+	}
+
+	if tok, ok := p.acceptOp("!", "-"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unary{col: tok.col, op: tok.text, x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokNumber:
+		return &numberLit{col: tok.col, value: tok.number, text: tok.text}, nil
+	case tokDuration:
+		return &durationLit{col: tok.col, value: tok.duration, text: tok.text}, nil
+	case tokLParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, errorf(closing.col, "expected \")\", got %s", closing.describe())
+		}
+		return expr, nil
+	case tokIdent:
+		if isKeyword(tok) {
+			return nil, unexpected(tok)
+		}
+
+		if p.peek().kind != tokLParen {
+			return &symbolRef{col: tok.col, name: strings.ToUpper(tok.text)}, nil
+		}
+		p.next()
+
+		c := &call{col: tok.col, name: strings.ToLower(tok.text)}
+		if p.peek().kind == tokRParen {
+			p.next()
+			return c, nil
+		}
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			c.args = append(c.args, arg)
+
+			sep := p.next()
+			if sep.kind == tokRParen {
+				return c, nil
+			}
+			if sep.kind != tokComma {
+				return nil, errorf(sep.col, "expected \",\" or \")\" in call to %s, got %s", c.name, sep.describe())
+			}
+		}
+	default:
+		return nil, errorf(tok.col, "expected a value, got %s", tok.describe())
+	}
+}
@@ -0,0 +1,214 @@
+// Package rules implements the alert rule language: expressions such as
+//
+//	pct_change(BTC, 1h) > 5 && price(ETH) < sma(ETH, 50)
+//
+// built from numbers, durations (15m, 1h, 2d), symbols, the arithmetic and
+// comparison operators, && || !, and a fixed set of built-in functions over
+// each symbol's recent prices.
+package rules
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Limits on rules, so one alert cannot exhaust the parser's stack or hold
+// unbounded price history.
+const (
+	MaxLength  = 4096
+	MaxDepth   = 32
+	MaxSamples = 1000
+	MaxWindow  = 7 * 24 * time.Hour
+)
+
+// Env supplies the price history built-in functions read.
+type Env interface {
+	// Price returns the latest price of symbol.
+	Price(symbol string) (float64, bool)
+	// Window returns the prices of symbol within window of now, oldest
+	// first, always including the latest one.
+	Window(symbol string, window time.Duration) []float64
+	// Samples returns up to the last n prices of symbol, oldest first.
+	Samples(symbol string, n int) []float64
+}
+
+// Program is a parsed and type-checked rule.
+type Program struct {
+	source  string
+	root    node
+	symbols []string
+	window  time.Duration
+	samples int
+}
+
+// Compile parses and type-checks a rule. Errors are *Error values that
+// point at the offending column.
+func Compile(source string) (*Program, error) {
+	source = strings.TrimSpace(source)
+	if length := utf8.RuneCountInString(source); length > MaxLength {
+		return nil, errorf(MaxLength+1, "rule is %d characters long; the limit is %d", length, MaxLength)
+	}
+
+	root, err := parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &checker{seen: make(map[string]bool)}
+	t, err := c.check(root)
+	if err != nil {
+		return nil, err
+	}
+	if t != typeBool {
+		return nil, errorf(1, "rule must be a condition such as price(BTC) > 100000, got a %s", t)
+	}
+	if len(c.symbols) == 0 {
+		return nil, errorf(1, "rule must reference at least one symbol")
+	}
+
+	return &Program{
+		source:  source,
+		root:    root,
+		symbols: c.symbols,
+		window:  c.window,
+		samples: c.samples,
+	}, nil
+}
+
+func (p *Program) String() string {
+	return p.source
+}
+
+// Symbols returns the symbols the rule references, in the order they first
+// appear.
+func (p *Program) Symbols() []string {
+	return append([]string(nil), p.symbols...)
+}
+
+// Window is the longest price window the rule reads.
+func (p *Program) Window() time.Duration {
+	return p.window
+}
+
+// Samples is the largest number of recent prices the rule reads.
+func (p *Program) Samples() int {
+	return p.samples
+}
+
+// Eval evaluates the rule. known is false when the outcome depends on
+// history that is not available yet, such as sma(BTC, 50) before 50 ticks.
+func (p *Program) Eval(env Env) (result, known bool) {
+	v, known := eval(p.root, env)
+	return v.boolean, known
+}
+
+type value struct {
+	number   float64
+	boolean  bool
+	symbol   string
+	duration time.Duration
+	count    int
+}
+
+func eval(n node, env Env) (value, bool) {
+	switch n := n.(type) {
+	case *numberLit:
+		return value{number: n.value, count: int(n.value)}, true
+	case *durationLit:
+		return value{duration: n.value}, true
+	case *symbolRef:
+		return value{symbol: n.name}, true
+	case *unary:
+		x, known := eval(n.x, env)
+		if !known {
+			return value{}, false
+		}
+		if n.op == "!" {
+			return value{boolean: !x.boolean}, known
+		}
+		return value{number: -x.number}, known
+	case *binary:
+		return evalBinary(n, env)
+	case *call:
+		fn := builtins[n.name]
+		args := make([]value, len(n.args))
+		for i, arg := range n.args {
+			v, known := eval(arg, env)
+			if !known {
+				return value{}, false
+			}
+			args[i] = v
+		}
+		result, known := fn.eval(env, args)
+		if !known || math.IsNaN(result) || math.IsInf(result, 0) {
+			return value{}, false
+		}
+		return value{number: result}, true
+	default:
+		panic(fmt.Sprintf("rules: unexpected node %T", n))
+	}
+}
+
+func evalBinary(n *binary, env Env) (value, bool) {
+	x, xKnown := eval(n.x, env)
+
+	// A known false (for &&) or true (for ||) side settles the result even
+	// when the other side is unknown.
+	switch n.op {
+	case "&&":
+		if xKnown && !x.boolean {
+			return value{boolean: false}, true
+		}
+		y, yKnown := eval(n.y, env)
+		if yKnown && !y.boolean {
+			return value{boolean: false}, true
+		}
+		return value{boolean: xKnown && yKnown}, xKnown && yKnown
+	case "||":
+		if xKnown && x.boolean {
+			return value{boolean: true}, true
+		}
+		y, yKnown := eval(n.y, env)
+		if yKnown && y.boolean {
+			return value{boolean: true}, true
+		}
+		return value{boolean: false}, xKnown && yKnown
+	}
+
+	y, yKnown := eval(n.y, env)
+	if !xKnown || !yKnown {
+		return value{}, false
+	}
+
+	const epsilon = 0.001
+	switch n.op {
+	case "+":
+		return value{number: x.number + y.number}, true
+	case "-":
+		return value{number: x.number - y.number}, true
+	case "*":
+		return value{number: x.number * y.number}, true
+	case "/":
+		if y.number == 0 {
+			return value{}, false
+		}
+		return value{number: x.number / y.number}, true
+	case "<":
+		return value{boolean: x.number < y.number}, true
+	case "<=":
+		return value{boolean: x.number <= y.number}, true
+	case ">":
+		return value{boolean: x.number > y.number}, true
+	case ">=":
+		return value{boolean: x.number >= y.number}, true
+	case "==":
+		return value{boolean: math.Abs(x.number-y.number) < epsilon}, true
+	case "!=":
+		return value{boolean: math.Abs(x.number-y.number) >= epsilon}, true
+	default:
+		panic(fmt.Sprintf("rules: unexpected operator %q", n.op))
+	}
+}
@@ -0,0 +1,133 @@
+package rules
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeEnv holds each symbol's prices one minute apart, oldest first.
+type fakeEnv map[string][]float64
+
+func (f fakeEnv) Price(symbol string) (float64, bool) {
+	prices := f[symbol]
+	if len(prices) == 0 {
+		return 0, false
+	}
+	return prices[len(prices)-1], true
+}
+
+func (f fakeEnv) Window(symbol string, window time.Duration) []float64 {
+	prices := f[symbol]
+	n := int(window/time.Minute) + 1
+	if n > len(prices) {
+		n = len(prices)
+	}
+	return prices[len(prices)-n:]
+}
+
+func (f fakeEnv) Samples(symbol string, n int) []float64 {
+	prices := f[symbol]
+	if n > len(prices) {
+		n = len(prices)
+	}
+	return prices[len(prices)-n:]
+}
+
+func TestCompile(t *testing.T) {
+	program, err := Compile("pct_change(BTC, 1h) > 5 && price(eth) < sma(ETH, 50) || low(BTC, 2d) < 1")
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	if symbols := program.Symbols(); strings.Join(symbols, ",") != "BTC,ETH" {
+		t.Errorf("Symbols() = %v, expected [BTC ETH]", symbols)
+	}
+	if program.Window() != 48*time.Hour {
+		t.Errorf("Window() = %v, expected 48h", program.Window())
+	}
+	if program.Samples() != 50 {
+		t.Errorf("Samples() = %d, expected 50", program.Samples())
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		rule string
+		col  int
+		msg  string
+	}{
+		{"", 1, "empty"},
+		{"price(BTC)", 1, "must be a condition"},
+		{"BTC > 100", 1, "use price(BTC)"},
+		{"price(BTC) > 100 AND price(ETH) < 5", 18, "use &&"},
+		{"prise(BTC) > 1", 1, `unknown function "prise"`},
+		{"sma(BTC) > 1", 1, "sma takes 2 argument(s), got 1; use sma(symbol, samples)"},
+		{"sma(BTC, 2.5) > 1", 10, "whole number of samples"},
+		{"pct_change(BTC, 60) > 1", 17, "duration"},
+		{"pct_change(1h, BTC) > 1", 12, "must be a symbol"},
+		{"price(BTC) > 1 > 0", 16, "cannot be chained"},
+		{"price(BTC) = 1", 12, "use =="},
+		{"(price(BTC) > 1", 16, `expected ")"`},
+		{"price(BTC) > 1 && 5", 19, "must be a condition, got a number"},
+		{"!price(BTC) > 1", 2, "operand of ! must be a condition"},
+		{"abs(1) > 0", 1, "at least one symbol"},
+		{"sma(BTC, 1001) > 1", 10, "at most 1000 samples"},
+		{"high(BTC, 8d) > 1", 11, "at most 7d"},
+		{"low(BTC, 9999999999999d) > 1", 10, "invalid duration"},
+		{strings.Repeat("(", 40) + "price(BTC) > 1" + strings.Repeat(")", 40), 33, "nested more than 32 levels"},
+		{strings.Repeat("-", 40) + "price(BTC) > 1", 33, "nested more than 32 levels"},
+		{"price(BTC) > " + strings.Repeat("1", MaxLength), MaxLength + 1, "the limit is 4096"},
+	}
+
+	for _, tt := range tests {
+		_, err := Compile(tt.rule)
+
+		var ruleErr *Error
+		if !errors.As(err, &ruleErr) {
+			t.Errorf("Compile(%q) error = %v, expected a rule error", tt.rule, err)
+			continue
+		}
+		if ruleErr.Col != tt.col || !strings.Contains(ruleErr.Msg, tt.msg) {
+			t.Errorf("Compile(%q) error = %v, expected col %d containing %q", tt.rule, err, tt.col, tt.msg)
+		}
+	}
+}
+
+func TestProgram_Eval(t *testing.T) {
+	env := fakeEnv{
+		"BTC": {100, 102, 104, 106, 110},
+		"ETH": {10, 10, 10, 9},
+	}
+
+	tests := []struct {
+		rule   string
+		result bool
+		known  bool
+	}{
+		{"pct_change(BTC, 4m) >= 10", true, true},
+		{"pct_change(BTC, 2m) > 5", true, true},
+		{"change(BTC, 1m) == 4 && high(BTC, 10m) - low(BTC, 10m) == 10", true, true},
+		{"price(ETH) < sma(ETH, 4)", true, true},
+		{"price(ETH) < sma(ETH, 5)", false, false},
+		{"price(SOL) > 1 || price(BTC) > 100", true, true},
+		{"price(SOL) > 1 && price(BTC) < 100", false, true},
+		{"price(SOL) > 1 && price(BTC) > 100", false, false},
+		{"!(price(BTC) / (price(ETH) - 9) > 0)", false, false},
+		{"max(price(BTC), 200) * -1 < -150 && abs(-price(ETH)) == 9", true, true},
+	}
+
+	for _, tt := range tests {
+		program, err := Compile(tt.rule)
+		if err != nil {
+			t.Errorf("Compile(%q) error = %v", tt.rule, err)
+			continue
+		}
+
+		result, known := program.Eval(env)
+		if result != tt.result || known != tt.known {
+			t.Errorf("Eval(%q) = %v, %v; expected %v, %v", tt.rule, result, known, tt.result, tt.known)
+		}
+	}
+}